@@ -0,0 +1,116 @@
+package generations
+
+import "github.com/juju/errors"
+
+const maxInt = int(^uint(0) >> 1)
+
+// Ahnentafel is the list of ancestors of a proband numbered after Kekulé: the proband is 1, the father of n is 2n and
+// the mother 2n+1
+type Ahnentafel struct {
+	Proband     Person
+	Generations []AhnentafelGeneration
+
+	// numbers maps the IDs of the persons in the list to their numbers
+	numbers map[string][]int
+}
+
+// AhnentafelGeneration are the ancestors of one generation ordered by number
+type AhnentafelGeneration struct {
+	// Index is 0 for the proband, 1 for the parents and so on
+	Index   int
+	Entries []AhnentafelEntry
+}
+
+// AhnentafelEntry is a person with one of its numbers
+type AhnentafelEntry struct {
+	Number int
+	Person Person
+	// ImplexOf is the first number of the person if it appears several times (implex), 0 otherwise. Its ancestors are
+	// listed for the first number only.
+	ImplexOf int
+}
+
+// NewAhnentafel lists the biological ancestors of proband up to o.MaxParentGenerations, ignored persons are skipped
+func NewAhnentafel(proband Person, o RenderTreeOptions) (*Ahnentafel, error) {
+	o.SetDefaults()
+	a := &Ahnentafel{
+		Proband: proband,
+		numbers: make(map[string][]int),
+	}
+	if proband.IsDummy() || isPersonIgnored(proband, o) {
+		return a, nil
+	}
+
+	entries := []AhnentafelEntry{{Number: 1, Person: proband}}
+	for index := 0; len(entries) > 0; index++ {
+		var parents []AhnentafelEntry
+		for i, entry := range entries {
+			id := entry.Person.GetBestID()
+			if numbers, ok := a.numbers[id]; ok {
+				entries[i].ImplexOf = numbers[0]
+			}
+			a.numbers[id] = append(a.numbers[id], entry.Number)
+			if entries[i].ImplexOf != 0 || index >= o.MaxParentGenerations {
+				continue
+			}
+
+			dad, err := entry.Person.GetDad()
+			if err != nil {
+				return nil, err
+			}
+			mom, err := entry.Person.GetMom()
+			if err != nil {
+				return nil, err
+			}
+			for j, parent := range []Person{dad, mom} {
+				if parent.IsDummy() || isPersonIgnored(parent, o) {
+					continue
+				}
+				number := kekuleParent(entry.Number, j == 1)
+				if number == 0 {
+					return nil, errors.Errorf("too many generations for Kekulé numbers above %s", proband.GetBestID())
+				}
+				parents = append(parents, AhnentafelEntry{
+					Number: number,
+					Person: parent,
+				})
+			}
+		}
+		a.Generations = append(a.Generations, AhnentafelGeneration{
+			Index:   index,
+			Entries: entries,
+		})
+		entries = parents
+	}
+	return a, nil
+}
+
+// Numbers returns all numbers of p in ascending order, none if p is not in the list
+func (a *Ahnentafel) Numbers(p Person) []int {
+	return a.numbers[p.GetBestID()]
+}
+
+// Implex returns the entries repeating persons listed with a lower number already
+func (a *Ahnentafel) Implex() []AhnentafelEntry {
+	var result []AhnentafelEntry
+	for _, g := range a.Generations {
+		for _, e := range g.Entries {
+			if e.ImplexOf != 0 {
+				result = append(result, e)
+			}
+		}
+	}
+	return result
+}
+
+// kekuleParent returns the Kekulé number of the father or mother of the person numbered n, 0 if n is unknown or the
+// number would overflow
+func kekuleParent(n int, mother bool) int {
+	if n <= 0 || n > (maxInt-1)/2 {
+		return 0
+	}
+	if mother {
+		return 2*n + 1
+	}
+	return 2 * n
+}
@@ -0,0 +1,120 @@
+package generations
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAhnentafel(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "implex.yml"))
+	assert.Nil(t, err)
+	proband, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name           string
+		options        RenderTreeOptions
+		expected       [][]string
+		expectedImplex []string
+	}{
+		{
+			name: "full",
+			expected: [][]string{
+				{"1 gauss"},
+				{"2 vater", "3 mutter"},
+				{"4 grossvater", "7 grossmutter"},
+				{"8 urgrossvater", "9 urgrossmutter", "14 urgrossvater", "15 urgrossmutter"},
+			},
+			expectedImplex: []string{"14 urgrossvater", "15 urgrossmutter"},
+		},
+		{
+			name: "limited",
+			options: RenderTreeOptions{
+				MaxParentGenerations: 2,
+			},
+			expected: [][]string{
+				{"1 gauss"},
+				{"2 vater", "3 mutter"},
+				{"4 grossvater", "7 grossmutter"},
+			},
+		},
+		{
+			name: "ignored",
+			options: RenderTreeOptions{
+				IgnoreIDs: []string{"vater"},
+			},
+			expected: [][]string{
+				{"1 gauss"},
+				{"3 mutter"},
+				{"7 grossmutter"},
+				{"14 urgrossvater", "15 urgrossmutter"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		a, err := NewAhnentafel(proband, test.options)
+		assert.Nil(t, err, test.name)
+
+		generations := make([][]string, len(a.Generations))
+		for i, g := range a.Generations {
+			assert.Equal(t, i, g.Index, test.name)
+			generations[i] = formatAhnentafelEntries(g.Entries)
+		}
+		assert.Equal(t, test.expected, generations, test.name)
+		assert.Equal(t, test.expectedImplex, formatAhnentafelEntries(a.Implex()), test.name)
+	}
+
+	a, err := NewAhnentafel(proband, RenderTreeOptions{})
+	assert.Nil(t, err)
+	person, err := database.GetByID("urgrossmutter")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{9, 15}, a.Numbers(person))
+	assert.Equal(t, 9, a.Implex()[1].ImplexOf)
+}
+
+func TestRenderParentTreeKekule(t *testing.T) {
+	renderOptions := RenderTreeOptions{
+		GenderOrder:                  GenderOrderFemaleFirst,
+		HideFamilyIDs:                true,
+		MaxParentGenerations:         2,
+		MaxParentSiblingsGenerations: -1,
+		ShowKekule:                   true,
+	}
+	addTestTemplates(&renderOptions)
+	renderOptions.SetDefaults()
+	renderOptions.RenderPersonOptions.HideAllData()
+	renderOptions.RenderPersonOptions.HideRootNodeHighlighting = true
+
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "implex.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	result, err := renderFullParentTree(person, renderOptions, false)
+	assert.Nil(t, err)
+	assertOutputSemantic(t, `parent{
+		g[id=gauss,]{kekule={1},}
+		parent{
+			g[id=mutter,]{kekule={3},}
+			p[id=grossmutter,]{kekule={7},}
+		}
+		parent{
+			g[id=vater,]{kekule={2},}
+			p[id=grossvater,]{kekule={4},}
+		}
+	}`, string(result), "kekule")
+}
+
+func formatAhnentafelEntries(entries []AhnentafelEntry) []string {
+	var result []string
+	for _, e := range entries {
+		result = append(result, fmt.Sprintf("%d %s", e.Number, e.Person.GetID()))
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jojomi/generations"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCheckFormat string
+)
+
+func getCheckCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "check <database>...",
+		Short: "checks databases for broken references and implausible data",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   checkHandler,
+	}
+	flags := cmd.PersistentFlags()
+	flags.StringVarP(&flagCheckFormat, "format", "f", "text", "output format (text or json)")
+	return &cmd
+}
+
+func checkHandler(c *cobra.Command, args []string) {
+	if flagCheckFormat != "text" && flagCheckFormat != "json" {
+		fmt.Printf("invalid format %s\n", flagCheckFormat)
+		os.Exit(1)
+	}
+
+	database := generations.NewMemoryDatabase()
+	for _, db := range args {
+		err := database.ParseYamlFile(getDatabaseFilename(db))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	issues := generations.Validate(database)
+	if flagCheckFormat == "json" {
+		data, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		printIssues(issues)
+	}
+	if generations.HasErrors(issues) {
+		os.Exit(2)
+	}
+}
+
+func printIssues(issues []generations.Issue) {
+	for _, issue := range issues {
+		fmt.Println(issue.String())
+	}
+}
+
+// getDatabaseFilename resolves database files relative to the database base dir unless they exist as given
+func getDatabaseFilename(db string) string {
+	if filepath.IsAbs(db) {
+		return db
+	}
+	if _, err := os.Stat(db); err == nil {
+		return db
+	}
+	basePath, err := homedir.Expand(flagRootDatabaseBaseDir)
+	if err != nil {
+		panic(err)
+	}
+	return filepath.Join(basePath, db)
+}
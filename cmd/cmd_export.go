@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jojomi/generations"
+	"github.com/jojomi/go-script/print"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagExportOutput string
+
+	flagExportDotProband              string
+	flagExportDotMaxParentGenerations int
+	flagExportDotMaxChildGenerations  int
+	flagExportDotIgnore               []string
+)
+
+func getExportCommand() *cobra.Command {
+	var exportCmd = cobra.Command{
+		Use:   "export",
+		Short: "converts yaml databases to other formats",
+	}
+	flags := exportCmd.PersistentFlags()
+	flags.StringVarP(&flagExportOutput, "output", "w", "", "file to write (default: first input filename with the extension of the format)")
+
+	exportCmd.AddCommand(getExportGedcomCommand())
+	exportCmd.AddCommand(getExportDotCommand())
+
+	return &exportCmd
+}
+
+func getExportGedcomCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "gedcom <file>...",
+		Short: "export yaml databases to a GEDCOM 5.5.1 file",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   exportGedcomHandler,
+	}
+	return &cmd
+}
+
+func exportGedcomHandler(c *cobra.Command, args []string) {
+	outputFile := flagExportOutput
+	if outputFile == "" {
+		outputFile = strings.TrimSuffix(args[0], filepath.Ext(args[0])) + ".ged"
+	}
+
+	database := generations.NewMemoryDatabase()
+	for _, inputFile := range args {
+		print.Boldf("Reading %s...\n", inputFile)
+		err := database.ParseYamlFile(inputFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	err := database.WriteGedcomFile(outputFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	print.Successf("%d persons and %d sources written to %s.\n", len(database.Persons), len(database.Sources), outputFile)
+}
+
+func getExportDotCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "dot <file>...",
+		Short: "export yaml databases to a Graphviz DOT file",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   exportDotHandler,
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&flagExportDotProband, "proband", "p", "", "export the sandclock of this person only (default: all persons)")
+	flags.IntVarP(&flagExportDotMaxParentGenerations, "max-parent-generations", "", 0, "number of parent generations in the sandclock (default: all)")
+	flags.IntVarP(&flagExportDotMaxChildGenerations, "max-child-generations", "", 0, "number of child generations in the sandclock (default: all)")
+	flags.StringSliceVarP(&flagExportDotIgnore, "ignore", "", nil, "IDs of persons to leave out of the sandclock")
+	return &cmd
+}
+
+func exportDotHandler(c *cobra.Command, args []string) {
+	outputFile := flagExportOutput
+	if outputFile == "" {
+		outputFile = strings.TrimSuffix(args[0], filepath.Ext(args[0])) + ".dot"
+	}
+
+	database := generations.NewMemoryDatabase()
+	for _, inputFile := range args {
+		print.Boldf("Reading %s...\n", inputFile)
+		err := database.ParseYamlFile(inputFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	var (
+		graph *generations.DotGraph
+		err   error
+	)
+	if flagExportDotProband == "" {
+		graph, err = generations.NewDotGraph(database)
+	} else {
+		var proband generations.Person
+		proband, err = database.Get(flagExportDotProband)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		graph, err = generations.NewDotTreeGraph(proband, generations.RenderTreeOptions{
+			MaxParentGenerations: flagExportDotMaxParentGenerations,
+			MaxChildGenerations:  flagExportDotMaxChildGenerations,
+			IgnoreIDs:            flagExportDotIgnore,
+		})
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(3)
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(4)
+	}
+	err = graph.WriteDot(file)
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(4)
+	}
+	print.Successf("Graph written to %s.\n", outputFile)
+}
@@ -5,8 +5,8 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
@@ -24,10 +24,11 @@ import (
 var (
 	flagGenealogytreeShowConfig     bool
 	flagGenealogytreeCompile        bool
-	flagGenealogytreeAnonymize      bool
+	flagGenealogytreeAnonymize      string
 	flagGenealogytreeNumCompileRuns int
 	flagGenealogytreeMinify         bool
 	flagGenealogytreeCheckIDs       bool
+	flagGenealogytreeFormat         string
 )
 
 func getGenealogytreeCommand() *cobra.Command {
@@ -39,11 +40,13 @@ func getGenealogytreeCommand() *cobra.Command {
 	}
 	flags := genealogytreeCmd.PersistentFlags()
 	flags.BoolVarP(&flagGenealogytreeShowConfig, "debug-config", "c", false, "show parsed config")
-	flags.BoolVarP(&flagGenealogytreeCheckIDs, "check-ids", "i", true, "error on unlinked IDs")
-	flags.BoolVarP(&flagGenealogytreeAnonymize, "anonymize", "a", false, "anonymize data")
+	flags.BoolVarP(&flagGenealogytreeCheckIDs, "check-ids", "i", true, "report database issues, error on unlinked IDs")
+	flags.StringVarP(&flagGenealogytreeAnonymize, "anonymize", "a", "", "anonymize data using the given privacy policy")
+	flags.Lookup("anonymize").NoOptDefVal = generations.PrivacyPolicyAnonymize
 	flags.BoolVarP(&flagGenealogytreeCompile, "compile", "", true, "generate pdf file using lualatex")
 	flags.IntVarP(&flagGenealogytreeNumCompileRuns, "compile-runs", "n", 2, "number of times to call lualatex")
 	flags.BoolVarP(&flagGenealogytreeMinify, "minify", "m", true, "minify filesize of generated pdf file")
+	flags.StringVarP(&flagGenealogytreeFormat, "format", "f", OutputFormatPdf.String(), "output format of diagrams (pdf or svg)")
 
 	genealogytreeCmd.AddCommand(getTestCommand())
 
@@ -52,6 +55,10 @@ func getGenealogytreeCommand() *cobra.Command {
 
 func genealogytreeHandler(c *cobra.Command, args []string) {
 	sc := script.NewContext()
+	format, err := ParseOutputFormat(flagGenealogytreeFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	for _, configFile := range args {
 		print.Boldf("Config file: %s...\n", configFile)
@@ -73,6 +80,18 @@ func genealogytreeHandler(c *cobra.Command, args []string) {
 		print.Successln("Config data OK.")
 		config.SetDefaults()
 		setDefaultOutputPath(&config, configFile)
+		generations.Templates = generations.NewTemplateRegistry(templateSearchPath(configFile))
+		generations.NameFormats = config.GetNameFormats()
+		err = generations.Templates.Load(config.Templates.Document.Filename)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(6)
+		}
+		config.Locale, err = config.GetLocale(config.Language, generations.Templates.Path)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(6)
+		}
 
 		if flagGenealogytreeShowConfig {
 			spew.Dump(config)
@@ -88,31 +107,14 @@ func genealogytreeHandler(c *cobra.Command, args []string) {
 		config.Attribution = strtpl.MustEval(config.Attribution, templateData)
 
 		// load config, use it
+		var svgs [][]byte
 		for i, treeConfig := range config.Trees {
 			treeConfig.AddGlobals(config)
-
-			// level handling
-			treeConfig.Levels.AddDefaultLevels(-20, 20)
-			treeConfig.Levels.Inherit(treeConfig.ProbandLevel, config.Levels)
-			// reverse order!
-			themes := treeConfig.Levels.Themes
-			for i := range themes {
-				theme := themes[len(themes)-1-i]
-				themePath := filepath.Join("templates", "levels", theme+".yml")
-				themeData, err := ioutil.ReadFile(themePath)
-				if err != nil {
-					log.Fatal(err)
-				}
-				var themeLevels generations.LevelConfig
-				err = yaml.Unmarshal(themeData, &themeLevels)
-				if err != nil {
-					log.Fatal(err)
-				}
-				themeLevels.AddDefaultLevels(-20, 20)
-				themeLevels.Combine(treeConfig.ProbandLevel)
-				treeConfig.Levels.Inherit(treeConfig.ProbandLevel, themeLevels)
+			treeConfig.Locale, err = config.GetLocale(treeConfig.Language, generations.Templates.Path)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(6)
 			}
-			treeConfig.Levels.Combine(treeConfig.ProbandLevel)
 
 			database := generations.NewMemoryDatabase()
 			basePath, err := homedir.Expand(flagRootDatabaseBaseDir)
@@ -130,50 +132,86 @@ func genealogytreeHandler(c *cobra.Command, args []string) {
 				}
 			}
 
-			if flagGenealogytreeAnonymize {
-				for i, p := range database.Persons {
-					yearOfBirth, err := strconv.Atoi(first(p.Birth.Date, 4))
-					if err == nil && yearOfBirth < 1880 {
-						continue
-					}
-					if len(p.Name.First) > 0 {
-						if p.Name.Used != "" {
-							p.Name = generations.Name{
-								First: []string{first(p.Name.Used, 1) + "."},
-							}
-						} else {
-							p.Name = generations.Name{
-								First: []string{first(p.Name.First[0], 1) + "."},
-							}
-						}
-					} else {
-						p.Name = generations.Name{}
-					}
-					p.Birth.Place = ""
-					p.Birth.Date = first(p.Birth.Date, 4)
-					p.Death.Place = ""
-					p.Death.Date = first(p.Death.Date, 4)
-					p.Baptism = generations.DatePlace{}
-					p.Burial = generations.DatePlace{}
-					p.Jobs = ""
-					for j, r := range p.Partners {
-						r.Engagement = generations.DatePlace{}
-						r.Marriage.Date = first(r.Marriage.Date, 4)
-						r.Divorce.Date = first(r.Divorce.Date, 4)
-						p.Partners[j] = r
+			if flagGenealogytreeCheckIDs {
+				// only issues breaking the tree stop rendering, everything else is reported
+				issues := generations.Validate(database)
+				printIssues(issues)
+				if generations.HasBreakingIssues(issues) {
+					os.Exit(1)
+				}
+			}
+
+			if flagGenealogytreeAnonymize != "" {
+				treeConfig.Privacy = flagGenealogytreeAnonymize
+			}
+			if treeConfig.Privacy != "" {
+				policy, err := config.GetPrivacyPolicy(treeConfig.Privacy)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				err = database.ApplyPrivacy(policy, treeConfig.Date)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			person, err := database.Get(treeConfig.Proband)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(2)
+			}
+			var secondPerson generations.Person
+			if treeConfig.RenderTreeOptions.GraphType == generations.GraphTypeConnection {
+				secondPerson, err = database.Get(treeConfig.SecondProband)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(2)
+				}
+				if treeConfig.ProbandLevel == 0 {
+					level, err := generations.ConnectionProbandLevel(person, secondPerson)
+					if err == nil {
+						treeConfig.ProbandLevel = level
 					}
-					p.Floruit = ""
-					p.Comment = ""
-					database.Persons[i] = p
 				}
 			}
 
+			// level handling
+			treeConfig.Levels.AddDefaultLevels(-20, 20)
+			treeConfig.Levels.Inherit(treeConfig.ProbandLevel, config.Levels)
+			// reverse order!
+			themes := treeConfig.Levels.Themes
+			for i := range themes {
+				theme := themes[len(themes)-1-i]
+				themePath := path.Join("templates", "levels", theme+".yml")
+				themeData, err := generations.Templates.Path.ReadFile(themePath)
+				if err != nil {
+					log.Fatal(err)
+				}
+				var themeLevels generations.LevelConfig
+				err = yaml.Unmarshal(themeData, &themeLevels)
+				if err != nil {
+					log.Fatal(err)
+				}
+				themeLevels.AddDefaultLevels(-20, 20)
+				themeLevels.Combine(treeConfig.ProbandLevel)
+				treeConfig.Levels.Inherit(treeConfig.ProbandLevel, themeLevels)
+			}
+			treeConfig.Levels.Combine(treeConfig.ProbandLevel)
+
 			o := treeConfig.RenderTreeOptions
 			o.Levels = treeConfig.Levels.Combined
 			// template filenames
 			if o.TemplateFilenameTree == "" {
 				o.TemplateFilenameTree = "templates/tree.tpl"
 			}
+			if o.TemplateFilenameTreeParent == "" {
+				o.TemplateFilenameTreeParent = "templates/tree_parent.tpl"
+			}
+			if o.TemplateFilenameTreeChild == "" {
+				o.TemplateFilenameTreeChild = "templates/tree_child.tpl"
+			}
 			if o.TemplateFilenamePerson == "" {
 				o.TemplateFilenamePerson = "templates/person.tpl"
 			}
@@ -194,41 +232,103 @@ func genealogytreeHandler(c *cobra.Command, args []string) {
 			}
 			o.RenderPersonOptions.TemplateFilename = o.TemplateFilenamePerson
 			o.RenderPersonOptions.Date = treeConfig.Date
-
-			person, err := database.Get(treeConfig.Proband)
+			o.RenderPersonOptions.Locale = treeConfig.Locale
+			_, err = o.RenderPersonOptions.GetNameFormat()
 			if err != nil {
 				fmt.Println(err)
-				os.Exit(2)
+				os.Exit(6)
+			}
+			if !o.HideImplex {
+				o.Implex = &generations.ImplexStatistics{}
 			}
-			tree, err := generations.RenderGenealogytree(person, o)
+			// all templates are parsed before rendering to report errors early
+			err = generations.Templates.Load(append(o.TemplateFilenames(), treeConfig.GetTemplate().Filename)...)
 			if err != nil {
 				fmt.Println(err)
-				os.Exit(3)
+				os.Exit(6)
 			}
 
-			treeConfig.Content = string(tree)
+			if format == OutputFormatSvg && treeConfig.Variant != TreeVariantDiagram {
+				fmt.Printf("Skipping tree %d, only diagrams can be rendered as SVG.\n", i+1)
+				continue
+			}
+			switch treeConfig.Variant {
+			case TreeVariantAhnentafel:
+				treeConfig.Ahnentafel, err = generations.NewAhnentafel(person, o)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(3)
+				}
+			case TreeVariantRegister:
+				treeConfig.Register, err = generations.NewDescendantRegister(person, treeConfig.Numbering, o)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(3)
+				}
+			default:
+				if format == OutputFormatSvg {
+					svg, err := renderSVG(person, secondPerson, o, treeConfig.ProbandLevel)
+					if err != nil {
+						fmt.Println(err)
+						os.Exit(3)
+					}
+					svgs = append(svgs, svg)
+					break
+				}
+				var model *generations.TreeModel
+				if o.GraphType == generations.GraphTypeConnection {
+					model, err = generations.BuildConnectionTree(person, secondPerson, o)
+				} else {
+					model, err = generations.BuildTree(person, o)
+				}
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(3)
+				}
+				tree, err := model.Genealogytree()
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(3)
+				}
+				treeConfig.Content = generations.LaTeX(tree)
+				treeConfig.Links = model.Links()
+				treeConfig.Implex = o.Implex
+			}
+			treeConfig.Sources = database.GetCitedSources()
 
 			config.Trees[i] = treeConfig
 		}
 
-		var renderedTrees string
+		if format == OutputFormatSvg {
+			err = writeSVGs(svgs, config.OutputFilename)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(4)
+			}
+			continue
+		}
+
+		config.CollectSources()
+
+		var renderedTrees generations.LaTeX
 		for _, treeConfig := range config.Trees {
-			renderedTree, err := generations.RenderTemplateFile(treeConfig.Templates.Tree.Filename, struct {
+			template := treeConfig.GetTemplate()
+			renderedTree, err := generations.RenderTemplateFile(template.Filename, struct {
 				Config     Config
 				TreeConfig TreeConfig
 				Options    map[string]interface{}
 			}{
 				Config:     config,
 				TreeConfig: treeConfig,
-				Options:    treeConfig.Templates.Tree.Options,
+				Options:    template.Options,
 			})
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(6)
 			}
-			renderedTrees = renderedTrees + string(renderedTree)
+			renderedTrees = renderedTrees + generations.LaTeX(renderedTree)
 		}
-		config.RenderedTrees = string(renderedTrees)
+		config.RenderedTrees = renderedTrees
 		print.Successln("Templates generated.")
 
 		print.Boldf("Rendering to file: %s\n", config.OutputFilename)
@@ -287,11 +387,47 @@ func genealogytreeHandler(c *cobra.Command, args []string) {
 	}
 }
 
-func first(input string, count int) string {
-	if len(input) <= count {
-		return input
+// renderSVG builds the diagram of a tree config and renders it to SVG
+func renderSVG(person, secondPerson generations.Person, o generations.RenderTreeOptions, probandLevel int) ([]byte, error) {
+	var (
+		m   *generations.TreeModel
+		err error
+	)
+	if o.GraphType == generations.GraphTypeConnection {
+		m, err = generations.BuildConnectionTree(person, secondPerson, o)
+	} else {
+		m, err = generations.BuildTree(person, o)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.SVG(probandLevel)
+}
+
+// writeSVGs writes one file next to the configured pdf per diagram, numbered if there are several
+func writeSVGs(svgs [][]byte, outputFilename string) error {
+	if len(svgs) == 0 {
+		fmt.Println("No diagrams to render as SVG.")
+		return nil
 	}
-	return string([]rune(input[0:count]))
+	err := os.MkdirAll(filepath.Dir(outputFilename), 0750)
+	if err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(outputFilename, ".pdf")
+	for i, svg := range svgs {
+		filename := base + ".svg"
+		if len(svgs) > 1 {
+			filename = fmt.Sprintf("%s-%d.svg", base, i+1)
+		}
+		print.Boldf("Rendering to file: %s\n", filename)
+		err = ioutil.WriteFile(filename, svg, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	print.Successln("SVG files written.")
+	return nil
 }
 
 func compileDocument(inputFile string, numRuns int, verbose bool) error {
@@ -400,6 +536,8 @@ func commandTest(c *cobra.Command, args []string) {
 
 		renderOptions := generations.RenderTreeOptions{
 			TemplateFilenameTree:               "templates/tree.tpl",
+			TemplateFilenameTreeParent:         "templates/tree_parent.tpl",
+			TemplateFilenameTreeChild:          "templates/tree_child.tpl",
 			TemplateFilenamePerson:             "templates/person.tpl",
 			TemplateFilenameParentTree:         "templates/parent_tree.tpl",
 			TemplateFilenameParentTreeHeadless: "templates/parent_tree_headless.tpl",
@@ -421,7 +559,7 @@ func commandTest(c *cobra.Command, args []string) {
 		}
 
 		TreeConfig := TreeConfig{
-			Content: string(tree),
+			Content: generations.LaTeX(tree),
 			Title:   file.Name(),
 
 			PostContent: "{\\tiny \\begin{verbatim}" + string(tree) + "\n\\end{verbatim}\n}",
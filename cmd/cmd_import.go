@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jojomi/generations"
+	"github.com/jojomi/go-script/print"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagImportOutput string
+	flagImportForce  bool
+)
+
+func getImportCommand() *cobra.Command {
+	var importCmd = cobra.Command{
+		Use:   "import",
+		Short: "converts databases from other formats to yaml",
+	}
+	flags := importCmd.PersistentFlags()
+	flags.StringVarP(&flagImportOutput, "output", "w", "", "yaml database file to write (default: input filename with extension .yml)")
+	flags.BoolVarP(&flagImportForce, "force", "f", false, "overwrite an existing yaml database file")
+
+	importCmd.AddCommand(getImportGedcomCommand())
+
+	return &importCmd
+}
+
+func getImportGedcomCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "gedcom <file>",
+		Short: "import a GEDCOM 5.5.1 file",
+		Args:  cobra.ExactArgs(1),
+		Run:   importGedcomHandler,
+	}
+	return &cmd
+}
+
+func importGedcomHandler(c *cobra.Command, args []string) {
+	inputFile := args[0]
+	outputFile := flagImportOutput
+	if outputFile == "" {
+		outputFile = strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + ".yml"
+	}
+	if fileExists(outputFile) && !flagImportForce {
+		fmt.Printf("%s exists already, use --force to overwrite it.\n", outputFile)
+		os.Exit(3)
+	}
+
+	print.Boldf("Importing %s...\n", inputFile)
+	database := generations.NewMemoryDatabase()
+	issues, err := database.ParseGedcomFile(inputFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	for _, issue := range issues {
+		print.Errorln(issue.String())
+	}
+	print.Successf("%d persons and %d sources imported, %d issues.\n", len(database.Persons), len(database.Sources), len(issues))
+
+	err = database.WriteYamlFile(outputFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	print.Successf("Database written to %s.\n", outputFile)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jojomi/generations"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRelateDatabases []string
+	flagRelateLanguage  string
+)
+
+func getRelateCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "relate <search> <search>",
+		Short: "shows how two persons are related",
+		Args:  cobra.ExactArgs(2),
+		Run:   relateHandler,
+	}
+	flags := cmd.PersistentFlags()
+	flags.StringSliceVarP(&flagRelateDatabases, "database", "b", nil, "database files to load")
+	flags.StringVarP(&flagRelateLanguage, "language", "l", "en", "language of the output (en or de)")
+	cmd.MarkPersistentFlagRequired("database")
+	return &cmd
+}
+
+func relateHandler(c *cobra.Command, args []string) {
+	language, err := generations.ParseLanguage(flagRelateLanguage)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	locale := generations.Locale{Language: language}
+
+	database := generations.NewMemoryDatabase()
+	for _, db := range flagRelateDatabases {
+		err := database.ParseYamlFile(getDatabaseFilename(db))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	persons := make([]generations.Person, len(args))
+	for i, search := range args {
+		persons[i], err = database.Get(search)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	r, err := generations.Relationship(persons[0], persons[1])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(3)
+	}
+	fmt.Printf("%s: %s\n", describePerson(persons[1], locale), r.Format(locale))
+	path := make([]string, len(r.Persons))
+	for i, p := range r.Persons {
+		path[i] = describePerson(p, locale)
+	}
+	fmt.Println(strings.Join(path, " → "))
+}
+
+func describePerson(p generations.Person, locale generations.Locale) string {
+	name := p.GetName().FormatFullIn(locale)
+	if name == "" {
+		return p.GetBestID()
+	}
+	return fmt.Sprintf("%s (%s)", name, p.GetBestID())
+}
@@ -15,6 +15,12 @@ func getRootCommand() *cobra.Command {
 	}
 
 	rootCmd.AddCommand(getGenealogytreeCommand())
+	rootCmd.AddCommand(getImportCommand())
+	rootCmd.AddCommand(getExportCommand())
+	rootCmd.AddCommand(getCheckCommand())
+	rootCmd.AddCommand(getRelateCommand())
+	rootCmd.AddCommand(getSiteCommand())
+	rootCmd.AddCommand(getTemplatesCommand())
 
 	flags := rootCmd.PersistentFlags()
 	flags.BoolVarP(&flagRootVerbose, "verbose", "v", true, "verbose output (e.g. lualatex output)")
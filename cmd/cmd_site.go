@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/jojomi/generations"
+	"github.com/jojomi/go-script/print"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagSiteTemplates   string
+	flagSiteLivingYears int
+	flagSiteLanguage    string
+)
+
+// siteEvent is a dated event shown on a person page
+type siteEvent struct {
+	Label string
+	Value generations.DatePlace
+}
+
+func getSiteCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "site <database> <outdir>",
+		Short: "creates a static family website with a page per person",
+		Args:  cobra.ExactArgs(2),
+		Run:   siteHandler,
+	}
+	flags := cmd.PersistentFlags()
+	flags.StringVarP(&flagSiteTemplates, "templates", "t", "", "directory of the html templates and the stylesheet, the default templates if empty")
+	flags.IntVarP(&flagSiteLivingYears, "living-years", "y", 100, "age up to which persons without date of death are considered living")
+	flags.StringVarP(&flagSiteLanguage, "language", "l", "de", "language of the pages (en or de)")
+	return &cmd
+}
+
+func siteHandler(c *cobra.Command, args []string) {
+	databaseFile := getDatabaseFilename(args[0])
+	outDir := args[1]
+	language, err := generations.ParseLanguage(flagSiteLanguage)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	database := generations.NewMemoryDatabase()
+	print.Boldf("Reading %s...\n", databaseFile)
+	err = database.ParseYamlFile(databaseFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	site, err := generations.NewSite(database.GetPersons(), generations.SiteOptions{
+		Privacy: generations.PrivacyPolicy{
+			LivingYears: flagSiteLivingYears,
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	searchPath := templateSearchPath("")
+	locale, err := (&Config{}).GetLocale(language, searchPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(3)
+	}
+	var templateFS fs.FS = searchPath
+	templateDir := "templates/site"
+	if flagSiteTemplates != "" {
+		templateFS = generations.TemplateDir(flagSiteTemplates)
+		templateDir = "."
+	}
+	templates, err := template.New("site").Funcs(template.FuncMap{
+		"event":    newSiteEvent,
+		"year":     year,
+		"t":        locale.T,
+		"tf":       locale.Tf,
+		"language": locale.GetLanguage().String,
+		"date": func(t time.Time) string {
+			return generations.PartialDate{Year: t.Year(), Month: int(t.Month()), Day: t.Day()}.Format(locale)
+		},
+		"linkType": func(t generations.ParentLinkType) string {
+			return locale.T("parent-link." + t.String())
+		},
+		"biographyType": func(t generations.BiographyElementType) string {
+			if t == 0 {
+				t = generations.BiographyElementTypeEvent
+			}
+			return locale.T("biography." + t.String())
+		},
+	}).ParseFS(templateFS, path.Join(templateDir, "*.html"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(3)
+	}
+
+	err = os.MkdirAll(filepath.Join(outDir, "images"), 0750)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(4)
+	}
+	err = writeSitePage(templates, "index.html", filepath.Join(outDir, "index.html"), site)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(4)
+	}
+	for _, p := range site.Persons {
+		err = writeSitePage(templates, "person.html", filepath.Join(outDir, p.Page), struct {
+			Site *generations.Site
+			Page *generations.SitePerson
+		}{
+			Site: site,
+			Page: p,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(4)
+		}
+		if p.Image == "" {
+			continue
+		}
+		image := p.Person.GetImageFilename()
+		if !filepath.IsAbs(image) {
+			// images are relative to the database, the working directory is a fallback
+			if candidate := filepath.Join(filepath.Dir(databaseFile), image); fileExists(candidate) {
+				image = candidate
+			}
+		}
+		err = copyFile(image, filepath.Join(outDir, filepath.FromSlash(p.Image)))
+		if err != nil {
+			print.Errorln(fmt.Sprintf("Could not copy image of %s: %s", p.Person.GetBestID(), err))
+		}
+	}
+
+	style, err := fs.ReadFile(templateFS, path.Join(templateDir, "style.css"))
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(outDir, "style.css"), style, 0644)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(4)
+	}
+	print.Successf("%d person pages written to %s.\n", len(site.Persons), outDir)
+}
+
+func writeSitePage(templates *template.Template, name, filename string, data interface{}) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = templates.ExecuteTemplate(file, name, data)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// newSiteEvent accepts dates given by value or pointer, nil pointers are empty events
+func newSiteEvent(label string, value interface{}) siteEvent {
+	e := siteEvent{Label: label}
+	switch v := value.(type) {
+	case generations.DatePlace:
+		e.Value = v
+	case *generations.DatePlace:
+		if v != nil {
+			e.Value = *v
+		}
+	}
+	return e
+}
+
+// year returns the year of a date, an empty string if it is unknown
+func year(d generations.Date) string {
+	y, ok := d.Year()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(y)
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+func copyFile(from, to string) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
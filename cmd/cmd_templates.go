@@ -0,0 +1,82 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jojomi/generations"
+	"github.com/jojomi/go-script/print"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+// userTemplatesBaseDir contains the templates directory with the user's overrides of the default templates
+const userTemplatesBaseDir = "~/.generations"
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+var flagTemplatesExportForce bool
+
+func getTemplatesCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "templates",
+		Short: "manages the default templates",
+	}
+	exportCmd := cobra.Command{
+		Use:   "export <dir>",
+		Short: "copies the default templates to a directory for customization, e.g. ~/.generations/templates",
+		Args:  cobra.ExactArgs(1),
+		Run:   templatesExportHandler,
+	}
+	exportCmd.Flags().BoolVarP(&flagTemplatesExportForce, "force", "f", false, "overwrite existing files")
+	cmd.AddCommand(&exportCmd)
+	return &cmd
+}
+
+func templatesExportHandler(c *cobra.Command, args []string) {
+	outDir := args[0]
+	var written int
+	err := fs.WalkDir(embeddedTemplates, "templates", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(outDir, filepath.FromSlash(strings.TrimPrefix(name, "templates")))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0750)
+		}
+		if fileExists(target) && !flagTemplatesExportForce {
+			fmt.Printf("Skipping existing file %s.\n", target)
+			return nil
+		}
+		content, err := embeddedTemplates.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		written++
+		return ioutil.WriteFile(target, content, 0644)
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	print.Successf("%d templates written to %s.\n", written, outDir)
+}
+
+// templateSearchPath returns the search path for templates named like "templates/tree.tpl": the directory of the
+// config file (if any), ~/.generations and the templates embedded in the binary
+func templateSearchPath(configFile string) generations.TemplatePath {
+	var result generations.TemplatePath
+	if configFile != "" {
+		result = append(result, generations.TemplateDir(filepath.Dir(configFile)))
+	}
+	if dir, err := homedir.Expand(userTemplatesBaseDir); err == nil {
+		result = append(result, generations.TemplateDir(dir))
+	}
+	return append(result, embeddedTemplates)
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"fmt"
+	"path"
 	"time"
 
 	"github.com/jojomi/generations"
+	"github.com/juju/errors"
 )
 
 type Template struct {
@@ -14,8 +17,10 @@ type Template struct {
 type Config struct {
 	Databases []string `yaml:"databases,omitempty"`
 	Templates struct {
-		Document Template `yaml:"document,omitempty"`
-		Tree     Template `yaml:"tree,omitempty"`
+		Document   Template `yaml:"document,omitempty"`
+		Tree       Template `yaml:"tree,omitempty"`
+		Ahnentafel Template `yaml:"ahnentafel,omitempty"`
+		Register   Template `yaml:"register,omitempty"`
 	} `yaml:"templates,omitempty"`
 
 	DocumentOptions string    `yaml:"document-options,omitempty"`
@@ -23,6 +28,13 @@ type Config struct {
 	Date            time.Time `yaml:"date,omitempty"`
 	DateFormat      string    `yaml:"date-format,omitempty"`
 
+	// Language of the generated texts, German by default
+	Language generations.Language `yaml:"locale,omitempty"`
+	// Messages override the messages of the locales by language
+	Messages map[generations.Language]map[string]string `yaml:"messages,omitempty"`
+	// Locale is the resolved locale of Language
+	Locale generations.Locale `yaml:"-"`
+
 	Attribution string                  `yaml:"attribution,omitempty"`
 	PreContent  string                  `yaml:"pre-content,omitempty"`
 	PostContent string                  `yaml:"post-content,omitempty"`
@@ -32,31 +44,66 @@ type Config struct {
 	CustomStyles string `yaml:"custom-styles,omitempty"`
 	CustomDraw   string `yaml:"custom-draw,omitempty"`
 
-	Trees         []TreeConfig `yaml:"trees"`
-	RenderedTrees string       `yaml:"-"`
+	// PrivacyPolicies are named privacy policies in addition to (or replacing) the built-in ones
+	PrivacyPolicies map[string]generations.PrivacyPolicy `yaml:"privacy-policies,omitempty"`
+	// Privacy is the name of the privacy policy for all trees, none if empty
+	Privacy string `yaml:"privacy,omitempty"`
+
+	// NameFormats are named name formats in addition to (or replacing) the built-in ones
+	NameFormats map[string]generations.NameFormat `yaml:"name-formats,omitempty"`
+
+	Trees         []TreeConfig      `yaml:"trees"`
+	RenderedTrees generations.LaTeX `yaml:"-"`
+
+	// Sources cited in any of the trees
+	Sources []generations.Source `yaml:"-"`
 
 	OutputFilename string `yaml:"output-filename,omitempty"`
 }
 
 type TreeConfig struct {
-	Databases []string `yaml:"databases,omitempty"`
+	// Variant is the kind of output, a diagram by default
+	Variant   TreeVariant `yaml:"variant,omitempty"`
+	Databases []string    `yaml:"databases,omitempty"`
 	Templates struct {
-		Tree Template `yaml:"tree,omitempty"`
+		Tree       Template `yaml:"tree,omitempty"`
+		Ahnentafel Template `yaml:"ahnentafel,omitempty"`
+		Register   Template `yaml:"register,omitempty"`
 	} `yaml:"templates,omitempty"`
 
 	Date       time.Time `yaml:"date,omitempty"`
 	DateFormat string    `yaml:"date-format,omitempty"`
 
+	// Language of the generated texts, the one of the config by default
+	Language generations.Language `yaml:"locale,omitempty"`
+	// Locale is the resolved locale of Language
+	Locale generations.Locale `yaml:"-"`
+
 	Title       string `yaml:"title,omitempty"`
 	Attribution string `yaml:"attribution,omitempty"`
 
-	Proband      string                  `yaml:"proband,omitempty"`
-	ProbandLevel int                     `yaml:"proband-level,omitempty"`
-	Levels       generations.LevelConfig `yaml:"levels,omitempty"`
-
-	PreContent  string `yaml:"pre-content,omitempty"`
-	PostContent string `yaml:"post-content,omitempty"`
-	Content     string `yaml:"-,omitempty"`
+	// Privacy is the name of the privacy policy applied to the databases of the tree
+	Privacy string `yaml:"privacy,omitempty"`
+
+	Proband string `yaml:"proband,omitempty"`
+	// SecondProband is the person to connect the proband with in connection graphs
+	SecondProband string                  `yaml:"second-proband,omitempty"`
+	ProbandLevel  int                     `yaml:"proband-level,omitempty"`
+	Levels        generations.LevelConfig `yaml:"levels,omitempty"`
+
+	PreContent  string            `yaml:"pre-content,omitempty"`
+	PostContent string            `yaml:"post-content,omitempty"`
+	Content     generations.LaTeX `yaml:"-,omitempty"`
+	// Links between the nodes of Content drawn in addition to the edges, e.g. from repeated persons
+	Links []generations.NodeLink `yaml:"-"`
+	// Implex are the persons repeated in the rendered tree
+	Implex *generations.ImplexStatistics `yaml:"-"`
+	// Ahnentafel is the list of ancestors for the ahnentafel variant
+	Ahnentafel *generations.Ahnentafel `yaml:"-"`
+	// Numbering is the numbering scheme of the register variant
+	Numbering generations.NumberingScheme `yaml:"numbering,omitempty"`
+	// Register is the list of descendants for the register variant
+	Register *generations.DescendantRegister `yaml:"-"`
 
 	CustomStyles string `yaml:"custom-styles,omitempty"`
 	CustomDraw   string `yaml:"custom-draw,omitempty"`
@@ -66,6 +113,9 @@ type TreeConfig struct {
 	PageBreakAfter bool `yaml:"page-break-after,omitempty"`
 
 	RenderTreeOptions generations.RenderTreeOptions `yaml:"render-tree-options"`
+
+	// Sources cited in the databases of this tree
+	Sources []generations.Source `yaml:"-"`
 }
 
 func (c *Config) SetDefaults() {
@@ -75,12 +125,59 @@ func (c *Config) SetDefaults() {
 	if c.Templates.Tree.Filename == "" {
 		c.Templates.Tree.Filename = "templates/tree/basic.tex"
 	}
+	if c.Templates.Ahnentafel.Filename == "" {
+		c.Templates.Ahnentafel.Filename = "templates/ahnentafel/basic.tex"
+	}
+	if c.Templates.Register.Filename == "" {
+		c.Templates.Register.Filename = "templates/register/basic.tex"
+	}
 	if c.Date.IsZero() {
 		c.Date = time.Now()
 	}
+	if c.Language == 0 {
+		c.Language = generations.DefaultLanguage
+	}
+}
+
+// GetLocale returns the locale for language with the messages of the catalog locales/<language>.yml in the search
+// path (if any), overridden by the messages of the config
+func (c *Config) GetLocale(language generations.Language, searchPath generations.TemplatePath) (generations.Locale, error) {
+	var catalogs []map[string]string
+	data, err := searchPath.ReadFile(path.Join("locales", language.String()+".yml"))
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return generations.Locale{}, err
+	default:
+		messages, err := generations.ParseMessages(data)
+		if err != nil {
+			return generations.Locale{}, errors.Annotatef(err, "locales/%s.yml", language)
+		}
+		catalogs = append(catalogs, messages)
+	}
+	catalogs = append(catalogs, c.Messages[language])
+	return generations.NewLocale(language, catalogs...), nil
+}
+
+// CollectSources sets the sources cited by any tree, without duplicates
+func (c *Config) CollectSources() {
+	seen := make(map[string]struct{})
+	c.Sources = make([]generations.Source, 0)
+	for _, t := range c.Trees {
+		for _, s := range t.Sources {
+			if _, ok := seen[s.ID]; ok {
+				continue
+			}
+			seen[s.ID] = struct{}{}
+			c.Sources = append(c.Sources, s)
+		}
+	}
 }
 
 func (t *TreeConfig) AddGlobals(config Config) {
+	if t.Variant == 0 {
+		t.Variant = TreeVariantDiagram
+	}
 	if len(t.Databases) == 0 {
 		t.Databases = config.Databases
 	}
@@ -96,6 +193,12 @@ func (t *TreeConfig) AddGlobals(config Config) {
 	if t.DateFormat == "" {
 		t.DateFormat = config.DateFormat
 	}
+	if t.Privacy == "" {
+		t.Privacy = config.Privacy
+	}
+	if t.Language == 0 {
+		t.Language = config.Language
+	}
 	// Templates
 	if t.Templates.Tree.Filename == "" {
 		t.Templates.Tree.Filename = config.Templates.Tree.Filename
@@ -103,4 +206,48 @@ func (t *TreeConfig) AddGlobals(config Config) {
 	if t.Templates.Tree.Options == nil {
 		t.Templates.Tree.Options = config.Templates.Tree.Options
 	}
+	if t.Templates.Ahnentafel.Filename == "" {
+		t.Templates.Ahnentafel.Filename = config.Templates.Ahnentafel.Filename
+	}
+	if t.Templates.Ahnentafel.Options == nil {
+		t.Templates.Ahnentafel.Options = config.Templates.Ahnentafel.Options
+	}
+	if t.Templates.Register.Filename == "" {
+		t.Templates.Register.Filename = config.Templates.Register.Filename
+	}
+	if t.Templates.Register.Options == nil {
+		t.Templates.Register.Options = config.Templates.Register.Options
+	}
+}
+
+// GetPrivacyPolicy returns the privacy policy with the given name from the config or the built-in ones
+func (c *Config) GetPrivacyPolicy(name string) (generations.PrivacyPolicy, error) {
+	if policy, ok := c.PrivacyPolicies[name]; ok {
+		return policy, nil
+	}
+	if policy, ok := generations.PrivacyPolicies()[name]; ok {
+		return policy, nil
+	}
+	return generations.PrivacyPolicy{}, fmt.Errorf("unknown privacy policy %s", name)
+}
+
+// GetNameFormats returns the built-in name formats and the ones of the config by name
+func (c *Config) GetNameFormats() map[string]generations.NameFormat {
+	result := generations.DefaultNameFormats()
+	for name, f := range c.NameFormats {
+		result[name] = f
+	}
+	return result
+}
+
+// GetTemplate returns the template to render the tree config with depending on its variant
+func (t TreeConfig) GetTemplate() Template {
+	switch t.Variant {
+	case TreeVariantAhnentafel:
+		return t.Templates.Ahnentafel
+	case TreeVariantRegister:
+		return t.Templates.Register
+	default:
+		return t.Templates.Tree
+	}
 }
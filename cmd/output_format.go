@@ -0,0 +1,10 @@
+package main
+
+//go:generate go-enum -f=output_format.go
+
+// OutputFormat selects the file format the genealogytree command renders diagrams to
+/* ENUM(
+pdf = 1
+svg
+*/
+type OutputFormat int
@@ -0,0 +1,43 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	// OutputFormatPdf is a OutputFormat of type Pdf
+	OutputFormatPdf OutputFormat = iota + 1
+	// OutputFormatSvg is a OutputFormat of type Svg
+	OutputFormatSvg
+)
+
+const _OutputFormatName = "pdfsvg"
+
+var _OutputFormatMap = map[OutputFormat]string{
+	1: _OutputFormatName[0:3],
+	2: _OutputFormatName[3:6],
+}
+
+// String implements the Stringer interface.
+func (x OutputFormat) String() string {
+	if str, ok := _OutputFormatMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("OutputFormat(%d)", x)
+}
+
+var _OutputFormatValue = map[string]OutputFormat{
+	_OutputFormatName[0:3]: 1,
+	_OutputFormatName[3:6]: 2,
+}
+
+// ParseOutputFormat attempts to convert a string to a OutputFormat
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	if x, ok := _OutputFormatValue[name]; ok {
+		return x, nil
+	}
+	return OutputFormat(0), fmt.Errorf("%s is not a valid OutputFormat", name)
+}
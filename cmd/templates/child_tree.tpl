@@ -1,4 +1,4 @@
-child{{ with .FamilyID }}{{ if . }}[id={{ . }}]{{ end }}{{ end }} {
+child{{ if or .FamilyID .EdgeStyle }}[{{ with .FamilyID }}id={{ raw . }}{{ end }}{{ if and .FamilyID .EdgeStyle }}, {{ end }}{{ with .EdgeStyle }}edges={foreground={ {{- raw . -}} }}{{ end }}]{{ end }} {
     {{ .G }}
     {{ .Parent }}
     {{ .Children }}
@@ -1,4 +1,4 @@
-parent{{ with .FamilyID }}{{ if . }}[id={{ . }}]{{ end }}{{ end }} {
+parent{{ if or .FamilyID .EdgeStyle }}[{{ with .FamilyID }}id={{ raw . }}{{ end }}{{ if and .FamilyID .EdgeStyle }}, {{ end }}{{ with .EdgeStyle }}edges={foreground={ {{- raw . -}} }}{{ end }}]{{ end }} {
     {{ .SiblingsOlder }}
     {{ .G }}
     {{ .SiblingsYounger }}
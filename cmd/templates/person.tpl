@@ -1,10 +1,12 @@
 {{- .Options.NodeType -}}[%
   {{ if not .Options.HideID -}}
-    {{ if .Person.GetID -}}
-      id={{ .Person.GetID }},
+    {{ if .Options.ImplexID -}}
+      id={{ raw .Options.ImplexID }},
+    {{ else if .Person.GetID -}}
+      id={{ raw .Person.GetID }},
     {{ else }}
       {{ if .Person.GetUUID -}}
-        id={{ .Person.GetUUID }},
+        id={{ raw .Person.GetUUID }},
       {{- end }}%
     {{- end }}%
   {{- end }}%
@@ -12,20 +14,24 @@
   {{- $hideList := $.Options.HideAttributes }}
   {{ if eq (len $hideList) 1 -}}
   {{ if eq (index $hideList 0) "all" }}%
-  {{ $hideList = .Person.GetAttributes }}%
+  {{ $hideList = .Options.GetAttributes .Person }}%
   {{- end }}%
   {{- end }}%
-  {{ $attributes := getFilteredStringSlice .Person.GetAttributes $hideList }}%
+  {{ $attributes := getFilteredStringSlice (.Options.GetAttributes .Person) $hideList }}%
   {{ if $attributes }}%
-    {{ join $attributes "," }}
+    {{ raw (join $attributes ",") }}
   {{- end }}%
 ]{
   {{ with .Person.GetUUID }}
     {{ if . }}
-      uuid={{ . }},
+      uuid={{ raw . }},
     {{ end }}
   {{ end }}
 
+  {{ with .Options.Kekule }}
+      kekule = { {{- . -}} },
+  {{ end }}
+
   {{ $gender := .Person.GetGender }}
   {{ if and (not $gender.IsUnknown) (not .Options.HideGender) }}
     {{ with $gender }}
@@ -35,73 +41,25 @@
 
   {{ $name := .Person.GetName }}
   {{ if and (not $name.Empty) (not .Options.HideName) }}
-    {{ with .Person.GetName }}
       name = {%
-        {{ with $name.Title }}{{ if . }}\titlename{ {{- . -}} }\ %{{ end }}{{ end }}
-        {{ if $name.Used }}
-          {{ $matched := false }}
-          {{ range .First }}
-            {{ if ne . $name.Used }}
-              {{ if and . (not $.Options.HideMiddleNames) }}
-                \middlename{ {{- . -}} }\ %
-              {{ end }}
-            {{ else }}
-              {{ $matched = true }}
-              \pref{ {{- . -}} }\ %
-            {{ end }}
-          {{ end }}
-          {{ if not $matched }}
-          \pref{ {{- $name.Used -}} }\ %
-          {{ end }}
-
+      {{ range $name.Parts .Options.GetNameFormat }}
+        {{ $kind := .Kind.String }}
+        {{ if eq $kind "birth" }}
+        \surnbirth{ {{- .Text -}} }\ %
+        {{ else if eq $kind "alias" }}
+        ~-- \alias{ {{- .Text -}} }\ %
         {{ else }}
-          {{ range $i, $name := .First }}
-            {{ if ne $i 0 }}
-              {{ if and . (not $.Options.HideMiddleNames) }}
-                \middlename{ {{- $name -}} }\ %
-              {{ end }}
-            {{ else }}
-              \pref{ {{- $name -}} }\ %
-            {{ end }}
-          {{ end }}
+        {{ if eq .Separator ", " }},{{ end }}{{ if .Separator }}\ {{ end }}%
+        {{ if eq $kind "title" }}\titlename{ {{- .Text -}} }%
+        {{ else if or (eq $kind "preferred") (eq $kind "used") }}\pref{ {{- .Text -}} }%
+        {{ else if or (eq $kind "given") (eq $kind "patronymic") }}\middlename{ {{- .Text -}} }%
+        {{ else if eq $kind "nick" }}\nick{ {{- .Text -}} }%
+        {{ else if eq $kind "surname" }}\surn{ {{- .Text -}} }%
+        {{ else }}{{ .Text }}%
+        {{ end }}
         {{ end }}
-
-      {{ with .Nick }}
-      {{ if . }}
-      \nick{ {{- . -}} }\ %
-      {{ end }}
-      {{ end }}
-
-
-      {{ if or (eq $.Options.LastnamePolicy.String "CurrentAndBirth") (eq $.Options.LastnamePolicy.String "Current") }}
-      {{ if .Last }}
-        \surn{ {{- .Last -}} }%
-      {{- end }}
-      {{ end }}
-
-      {{ if eq $.Options.LastnamePolicy.String "CurrentAndBirth" }}
-      {{- if .Birth -}}
-        \surnbirth{ {{- .Birth -}} }\ %
-      {{ end }}
-      {{ end }}
-
-      {{ if eq $.Options.LastnamePolicy.String "Birth" }}
-      {{- if .Birth -}}
-        \surn{ {{- .Birth -}} }\ %
-      {{ else }}
-        \surn{ {{- .Last -}} }\ %
-      {{ end }}
-      {{ end }}
-
-
-      {{ with .Alias }}
-      {{ if . }}
-      ~-- \alias{ {{- . -}} }\ %
-      {{ end }}
       {{ end }}
-
       },
-    {{ end }}
   {{ end }}
 
 
@@ -109,16 +67,14 @@
   {{ if and (not $birth.Empty) (not .Options.HideBirth) }}
     {{ with $birth }}
       {{ if and (not $.Options.HidePlaces) (ne .Place "") }}
-      birth = { {{- if .Date }}{{ .Date }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
+      birth = { {{- if .Date }}{{ .Date.Genealogytree }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
       {{ else }}
-      birth- = { {{- .Date -}} },
+      birth- = { {{- .Date.Genealogytree -}} },
       {{ end }}
 
       {{ $age := $.Person.GetAge $.Options.Date }}
-      {{ with $age }}
-        {{ if and (ne . -1) ($.Options.ShowAge) ($.Person.GetDeath.Empty) }}
-          age = { {{- . -}} },
-        {{ end }}
+      {{ if and (not $age.Empty) ($.Options.ShowAge) ($.Person.GetDeath.Empty) }}
+        age = { {{- $age -}} },
       {{ end }}
     {{ end }}
   {{ end }}
@@ -127,9 +83,9 @@
   {{ if and (not $baptism.Empty) (not .Options.HideBaptism) }}
     {{ with $baptism }}
       {{ if and (not $.Options.HidePlaces) (ne .Place "") }}
-      baptism = { {{- if .Date }}{{ .Date }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
+      baptism = { {{- if .Date }}{{ .Date.Genealogytree }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
       {{ else }}
-      baptism- = { {{- .Date -}} },
+      baptism- = { {{- .Date.Genealogytree -}} },
       {{ end }}
     {{ end }}
   {{ end }}
@@ -138,19 +94,17 @@
   {{ if and (not $death.Empty) (not .Options.HideDeath) }}
     {{ with $death }}
       {{ if and (not $.Options.HidePlaces) (ne .Place "") }}
-      death = { {{- if .Date }}{{ .Date }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
+      death = { {{- if .Date }}{{ .Date.Genealogytree }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
       {{ else }}
-      death- = { {{- .Date -}} },
+      death- = { {{- .Date.Genealogytree -}} },
       {{ end }}
     {{ end }}
   {{ end }}
 
   {{ if not $.Options.HideBirth }}
     {{ $deathAge := .Person.GetDeathAge }}
-    {{ with $deathAge }}
-      {{ if and (ne . -1) (not $.Options.HideDeathAge) }}
-        deathage = { {{- . -}} },
-      {{ end }}
+    {{ if and (not $deathAge.Empty) (not $.Options.HideDeathAge) }}
+      deathage = { {{- $deathAge -}} },
     {{ end }}
   {{ end }}
 
@@ -158,9 +112,9 @@
   {{ if and (not $burial.Empty) (not .Options.HideBurial) }}
     {{ with $burial }}
       {{ if and (not $.Options.HidePlaces) (ne .Place "") }}
-      burial = { {{- if .Date }}{{ .Date }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
+      burial = { {{- if .Date }}{{ .Date.Genealogytree }}{{ else }}-{{ end -}} }{ {{- .Place -}} },
       {{ else }}
-      burial- = { {{- .Date -}} },
+      burial- = { {{- .Date.Genealogytree -}} },
       {{ end }}
     {{ end }}
   {{ end }}
@@ -173,9 +127,9 @@
       {{ with .Engagement }}
         {{ if and (not $.Options.HideEngagement) (not .Empty) }}
           {{ if and (not $.Options.HidePlaces) (ne .Place "") }}
-          engagement = { {{- .Date -}} }{ {{- .Place -}} },
+          engagement = { {{- .Date.Genealogytree -}} }{ {{- .Place -}} },
           {{ else }}
-          engagement- = { {{- .Date -}} },
+          engagement- = { {{- .Date.Genealogytree -}} },
           {{ end }}
         {{ end }}
       {{ end }}
@@ -183,17 +137,15 @@
       {{ with .Marriage }}
         {{ if and (not $.Options.HideMarriage) (not .Empty) }}
           {{ if and (not $.Options.HidePlaces) (ne .Place "") }}
-          marriage = { {{- .Date -}} }{ {{- .Place -}} },
+          marriage = { {{- .Date.Genealogytree -}} }{ {{- .Place -}} },
           {{ else }}
-          marriage- = { {{- .Date -}} },
+          marriage- = { {{- .Date.Genealogytree -}} },
           {{ end }}
 
           {{ if not $.Options.HideBirth }}
             {{ $marriageAge := .GetAgeBegin $birth }}
-            {{ with $marriageAge }}
-              {{ if and (ne . -1) (not $.Options.HideMarriageAge) }}
-                marriageage = { {{- . -}} },
-              {{ end }}
+            {{ if and (not $marriageAge.Empty) (not $.Options.HideMarriageAge) }}
+              marriageage = { {{- $marriageAge -}} },
             {{ end }}
           {{ end }}
         {{ end }}
@@ -202,9 +154,9 @@
       {{ with .Divorce }}
         {{ if and (not $.Options.HideDivorce) (not .Empty) }}
           {{ if and (not $.Options.HidePlaces) (ne .Place "") }}
-          divorce = { {{- .Date -}} }{ {{- .Place -}} },
+          divorce = { {{- .Date.Genealogytree -}} }{ {{- .Place -}} },
           {{ else }}
-          divorce- = { {{- .Date -}} },
+          divorce- = { {{- .Date.Genealogytree -}} },
           {{ end }}
         {{ end }}
       {{ end }}
@@ -221,18 +173,30 @@
   {{ end }}
   {{ end }}
 
+  {{ if and (not .Options.HideResidences) (not .Person.GetFloruit) }}
+  {{ with .Person.GetResidences }}
+    {{ if and (not $.Options.HidePlaces) .Places }}
+      floruit = { {{- with .Period.Genealogytree }}{{ . }}{{ else }}-{{ end -}} }{ {{- .Places -}} },
+    {{ else if not .Period.Empty }}
+      floruit- = { {{- .Period.Genealogytree -}} },
+    {{ end }}
+  {{ end }}
+  {{ end }}
+
   {{ if not .Options.HideJobs }}
-  {{ with .Person.GetJobs }}
-    {{ if . }}
+  {{ $jobs := .Person.GetJobs }}
+  {{ if .Options.CurrentJobOnly }}
+    {{ $jobs = $jobs.CurrentAt .Options.Date }}
+  {{ end }}
+  {{ with $jobs.Format }}
       profession = { {{- . -}} },
-    {{ end }}
   {{ end }}
   {{ end }}
 
   {{ if not .Options.HideImage }}
   {{ with .Person.GetImageFilename }}
     {{ if . }}
-      image = { {{- . -}} },
+      image = { {{- raw . -}} },
     {{ end }}
   {{ end }}
   {{ end }}
@@ -244,4 +208,10 @@
     {{ end }}
   {{ end }}
   {{ end }}
+
+  {{ if not .Options.HideSources }}
+  {{ with getSourceIDs .Person.GetAllReferences }}
+      sources = { {{- raw (join . ",") -}} },
+  {{ end }}
+  {{ end }}
 }
\ No newline at end of file
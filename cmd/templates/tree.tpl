@@ -1,4 +1,4 @@
-sandclock {
+sandclock{{ with .EdgeStyle }}[edges={foreground={ {{- raw . -}} }}]{{ end }} {
   {{ .ParentTree }}
   {{ .SiblingsOlder}}
   {{ .ChildTree }}
@@ -0,0 +1 @@
+{{ .ChildTree }}
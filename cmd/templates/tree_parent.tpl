@@ -0,0 +1 @@
+{{ .ParentTree }}
@@ -1,4 +1,4 @@
-union{{ with .FamilyID }}{{ if . }}[id={{ . }}]{{ end }}{{ end }} {
+union{{ if or .FamilyID .EdgeStyle }}[{{ with .FamilyID }}id={{ raw . }}{{ end }}{{ if and .FamilyID .EdgeStyle }}, {{ end }}{{ with .EdgeStyle }}edges={foreground={ {{- raw . -}} }}{{ end }}]{{ end }} {
     {{ .Parent }}
     {{ .Children }}
 }
\ No newline at end of file
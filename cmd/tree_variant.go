@@ -0,0 +1,11 @@
+package main
+
+//go:generate go-enum -f=tree_variant.go --marshal
+
+// TreeVariant selects what a tree config renders into the document
+/* ENUM(
+diagram = 1
+ahnentafel
+register
+*/
+type TreeVariant int
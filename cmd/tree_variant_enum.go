@@ -0,0 +1,63 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	// TreeVariantDiagram is a TreeVariant of type Diagram
+	TreeVariantDiagram TreeVariant = iota + 1
+	// TreeVariantAhnentafel is a TreeVariant of type Ahnentafel
+	TreeVariantAhnentafel
+	// TreeVariantRegister is a TreeVariant of type Register
+	TreeVariantRegister
+)
+
+const _TreeVariantName = "diagramahnentafelregister"
+
+var _TreeVariantMap = map[TreeVariant]string{
+	1: _TreeVariantName[0:7],
+	2: _TreeVariantName[7:17],
+	3: _TreeVariantName[17:25],
+}
+
+// String implements the Stringer interface.
+func (x TreeVariant) String() string {
+	if str, ok := _TreeVariantMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("TreeVariant(%d)", x)
+}
+
+var _TreeVariantValue = map[string]TreeVariant{
+	_TreeVariantName[0:7]:   1,
+	_TreeVariantName[7:17]:  2,
+	_TreeVariantName[17:25]: 3,
+}
+
+// ParseTreeVariant attempts to convert a string to a TreeVariant
+func ParseTreeVariant(name string) (TreeVariant, error) {
+	if x, ok := _TreeVariantValue[name]; ok {
+		return x, nil
+	}
+	return TreeVariant(0), fmt.Errorf("%s is not a valid TreeVariant", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x TreeVariant) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *TreeVariant) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseTreeVariant(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
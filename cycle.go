@@ -0,0 +1,85 @@
+package generations
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ancestryPath is the list of persons on the way from the root of a tree to the person currently rendered
+type ancestryPath []Person
+
+// with returns the path extended by p, an error naming the cycle if p is on the path already
+func (a ancestryPath) with(p Person) (ancestryPath, error) {
+	for i, visited := range a {
+		if visited == p || (p.GetID() != "" && visited.MatchesIDUUID(p.GetID())) {
+			return nil, errors.Errorf("cycle in ancestry: %s", formatCycle(append(a[i:len(a):len(a)], p)))
+		}
+	}
+	// never share the underlying array between branches of the tree
+	return append(a[:len(a):len(a)], p), nil
+}
+
+// formatCycle returns a cycle like "a → b → a"
+func formatCycle(persons []Person) string {
+	ids := make([]string, len(persons))
+	for i, p := range persons {
+		ids[i] = p.GetBestID()
+	}
+	return strings.Join(ids, " → ")
+}
+
+// ancestryCycle is a cycle of parent links found in a database, closed by link number linkIndex of the last person
+type ancestryCycle struct {
+	persons   []Person
+	linkIndex int
+}
+
+// findAncestryCycles searches the parent links of all persons (of any type) for cycles. Every cycle is reported once.
+// Self references and links to unknown persons are skipped.
+func findAncestryCycles(d Database, persons []Person) []ancestryCycle {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	var (
+		result []ancestryCycle
+		state  = make(map[Person]int, len(persons))
+		path   []Person
+		visit  func(p Person)
+	)
+	visit = func(p Person) {
+		state[p] = active
+		path = append(path, p)
+		for i, link := range p.GetParentLinks() {
+			parent, err := d.GetByID(link.ID)
+			if err != nil || parent == p {
+				continue
+			}
+			switch state[parent] {
+			case unvisited:
+				visit(parent)
+			case active:
+				start := len(path) - 1
+				for path[start] != parent {
+					start--
+				}
+				cycle := make([]Person, 0, len(path)-start+1)
+				cycle = append(cycle, path[start:]...)
+				result = append(result, ancestryCycle{
+					persons:   append(cycle, parent),
+					linkIndex: i,
+				})
+			}
+		}
+		path = path[:len(path)-1]
+		state[p] = done
+	}
+	for _, p := range persons {
+		if state[p] == unvisited {
+			visit(p)
+		}
+	}
+	return result
+}
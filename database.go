@@ -4,7 +4,7 @@ import (
 	"io/ioutil"
 	"math"
 	"regexp"
-	"strconv"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/rs/zerolog/log"
@@ -14,6 +14,8 @@ import (
 type Database interface {
 	Get(search string) (Person, error)
 	GetByID(ID string) (Person, error)
+	// GetPersons returns all persons in the database
+	GetPersons() []Person
 	MakeIDs(f func(p Person, d Database) error) error
 }
 
@@ -25,7 +27,11 @@ type YamlDatabase struct {
 
 type MemoryDatabase struct {
 	Persons []*FlatPerson
-	Sources []Source // TODO Familybook sollte Source heißen und Source eher Reference
+	Sources []Source
+
+	// merged are the persons given again in another file, they were merged into the first one
+	merged []*FlatPerson
+	index  *memoryIndex
 }
 
 func NewMemoryDatabase() *MemoryDatabase {
@@ -43,13 +49,22 @@ func (y *MemoryDatabase) ParseYamlFile(filename string) error {
 	var yamlDatabase YamlDatabase
 	err = yaml.UnmarshalStrict(data, &yamlDatabase)
 	if err != nil {
-		return errors.Annotatef(err, "syntax error reading yaml database %s", filename)
+		// legacy format: plain list of persons without sources
+		if yaml.UnmarshalStrict(data, &yamlDatabase.Persons) != nil {
+			return errors.Annotatef(err, "syntax error reading yaml database %s", filename)
+		}
 	}
 
-	// augment: auto ID, set DB handle
+	// augment: auto ID, set DB handle, position in file
+	positions := scanPersonPositions(filename, data)
 	checkDuplicatesOnImport := true
-	for _, p := range yamlDatabase.Persons {
+	for i, p := range yamlDatabase.Persons {
 		p.Database = y
+		if len(positions) == len(yamlDatabase.Persons) {
+			p.position = positions[i]
+		} else {
+			p.position = &personPosition{Position: Position{File: filename}}
+		}
 
 		// add default sources if no main source is given for the person
 		if p.Sources == nil || len(p.Sources) == 0 {
@@ -70,6 +85,7 @@ func (y *MemoryDatabase) ParseYamlFile(filename string) error {
 					existing.SetRawMom(p.GetRawMom())
 				}
 
+				y.merged = append(y.merged, p)
 				continue
 			}
 		}
@@ -77,6 +93,7 @@ func (y *MemoryDatabase) ParseYamlFile(filename string) error {
 		persons = append(persons, p)
 	}
 	y.Persons = persons
+	y.Reindex()
 
 	// add sources to database
 	y.Sources = append(y.Sources, yamlDatabase.Sources...)
@@ -107,66 +124,82 @@ func (y *MemoryDatabase) MakeIDs(f func(p Person, d Database) error) error {
 		}
 		y.Persons[i] = p
 	}
+	y.Reindex()
 	return nil
 }
 
-func (y MemoryDatabase) Anonymize() {
-	for i, p := range y.Persons {
-		yearOfBirth, err := strconv.Atoi(first(p.Birth.Date, 4))
-		if err == nil && yearOfBirth < 1880 {
-			continue
-		}
-		if len(p.Name.First) > 0 {
-			if p.Name.Used != "" {
-				p.Name = Name{
-					First: []string{first(p.Name.Used, 1) + "."},
-				}
-			} else {
-				p.Name = Name{
-					First: []string{first(p.Name.First[0], 1) + "."},
-				}
-			}
-		} else {
-			p.Name = Name{}
-		}
-		p.Birth.Place = ""
-		p.Birth.Date = first(p.Birth.Date, 4)
-		p.Death.Place = ""
-		p.Death.Date = first(p.Death.Date, 4)
-		p.Baptism = DatePlace{}
-		p.Burial = DatePlace{}
-		p.Jobs = make([]Job, 0)
-		for j, r := range p.Partners {
-			r.Engagement = DatePlace{}
-			r.Marriage.Date = first(r.Marriage.Date, 4)
-			r.Divorce.Date = first(r.Divorce.Date, 4)
-			p.Partners[j] = r
-		}
-		p.Residences = make([]Residence, 0)
+// Reindex rebuilds the lookup index. It is required after changing the persons in the database directly, the
+// setters of FlatPerson take care of it on their own.
+func (y *MemoryDatabase) Reindex() {
+	y.index = newMemoryIndex(y.Persons)
+}
 
-		p.BiographyElements = make([]BiographyElement, 0)
-		p.Comments = make([]Comment, 0)
-		p.Sources = make([]Reference, 0)
-		y.Persons[i] = p
+// invalidateIndex marks the index as outdated so that it is rebuilt on next access
+func (y *MemoryDatabase) invalidateIndex() {
+	y.index = nil
+}
+
+func (y *MemoryDatabase) getIndex() *memoryIndex {
+	if y.index == nil || y.index.count != len(y.Persons) {
+		y.Reindex()
 	}
+	return y.index
 }
 
-func (y MemoryDatabase) Get(search string) (Person, error) {
+// Anonymize hides the data of living persons using the built-in anonymize privacy policy
+func (y *MemoryDatabase) Anonymize() {
+	// the built-in policy is valid and FlatPersons don't fail to look up children
+	_ = y.ApplyPrivacy(PrivacyPolicies()[PrivacyPolicyAnonymize], time.Now())
+}
+
+func (y *MemoryDatabase) Get(search string) (Person, error) {
+	if p := y.getIndex().get(search); p != nil {
+		return p, nil
+	}
+	return nil, errors.Errorf("person not found for search '%s'", search)
+}
+
+func (y *MemoryDatabase) GetPersons() []Person {
+	result := make([]Person, 0, len(y.Persons))
 	for _, p := range y.Persons {
-		if p.MatchesSearch(search) {
-			return p, nil
+		result = append(result, p)
+	}
+	return result
+}
+
+func (y *MemoryDatabase) GetByID(ID string) (Person, error) {
+	if p := y.getIndex().getByIDUUID(ID); p != nil {
+		return p, nil
+	}
+	return nil, errors.Errorf("person not found for ID %s", ID)
+}
+
+// GetSource returns the source with the given ID
+func (y *MemoryDatabase) GetSource(ID string) (Source, error) {
+	for _, s := range y.Sources {
+		if s.ID == ID {
+			return s, nil
 		}
 	}
-	return nil, errors.Errorf("person not found for search '%s'", search)
+	return Source{}, errors.Errorf("source not found for ID %s", ID)
 }
 
-func (y MemoryDatabase) GetByID(ID string) (Person, error) {
+// GetCitedSources returns all sources that are referenced by any person or fact in the database, ordered as in the
+// database
+func (y *MemoryDatabase) GetCitedSources() []Source {
+	cited := make(map[string]struct{})
 	for _, p := range y.Persons {
-		if p.MatchesIDUUID(ID) {
-			return p, nil
+		for _, id := range GetSourceIDs(p.GetAllReferences()) {
+			cited[id] = struct{}{}
 		}
 	}
-	return nil, errors.Errorf("person not found for ID %s", ID)
+	result := make([]Source, 0, len(cited))
+	for _, s := range y.Sources {
+		if _, ok := cited[s.ID]; ok {
+			result = append(result, s)
+		}
+	}
+	return result
 }
 
 func firstLetters(input string, count int) string {
@@ -193,7 +226,7 @@ func GetFourFourYearID(p Person) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		year := re.FindString(birth.Date)
+		year := re.FindString(string(birth.Date))
 		id += year
 	}
 	return id, nil
@@ -0,0 +1,129 @@
+package generations
+
+// memoryIndex allows lookups in a MemoryDatabase without scanning all persons
+type memoryIndex struct {
+	// count is the number of persons indexed, used to detect direct changes to the list of persons
+	count    int
+	position map[*FlatPerson]int
+	byID     map[string]*FlatPerson
+	byUUID   map[string]*FlatPerson
+	byName   map[string]*FlatPerson
+	// children maps the ID or UUID used in a parent link to the children referencing it
+	children map[string][]*FlatPerson
+	// partnerOf maps the ID or UUID used as partner to the persons listing it
+	partnerOf map[string][]*FlatPerson
+}
+
+func newMemoryIndex(persons []*FlatPerson) *memoryIndex {
+	index := &memoryIndex{
+		count:     len(persons),
+		position:  make(map[*FlatPerson]int, len(persons)),
+		byID:      make(map[string]*FlatPerson, len(persons)),
+		byUUID:    make(map[string]*FlatPerson, len(persons)),
+		byName:    make(map[string]*FlatPerson, len(persons)),
+		children:  make(map[string][]*FlatPerson, len(persons)),
+		partnerOf: make(map[string][]*FlatPerson),
+	}
+	for i, p := range persons {
+		if p == nil {
+			continue
+		}
+		if _, ok := index.position[p]; ok {
+			continue
+		}
+		index.position[p] = i
+		// the first person wins, just like scanning the list would
+		addIfMissing(index.byID, p.ID, p)
+		addIfMissing(index.byUUID, p.UUID, p)
+		if len(p.Name.First) > 0 {
+			addIfMissing(index.byName, p.Name.First[0]+" "+p.Name.Last, p)
+		}
+
+		linked := make(map[string]struct{})
+		for _, link := range p.GetParentLinks() {
+			if _, ok := linked[link.ID]; ok {
+				continue
+			}
+			linked[link.ID] = struct{}{}
+			index.children[link.ID] = append(index.children[link.ID], p)
+		}
+		for _, r := range p.Partners {
+			if r.PartnerID != "" {
+				index.partnerOf[r.PartnerID] = append(index.partnerOf[r.PartnerID], p)
+			}
+		}
+	}
+	return index
+}
+
+func addIfMissing(m map[string]*FlatPerson, key string, p *FlatPerson) {
+	if key == "" {
+		return
+	}
+	if _, ok := m[key]; ok {
+		return
+	}
+	m[key] = p
+}
+
+// first returns the candidate that comes first in the database, nil if there is none
+func (i *memoryIndex) first(candidates ...*FlatPerson) *FlatPerson {
+	var result *FlatPerson
+	for _, c := range candidates {
+		if c == nil {
+			continue
+		}
+		if result == nil || i.position[c] < i.position[result] {
+			result = c
+		}
+	}
+	return result
+}
+
+func (i *memoryIndex) getByIDUUID(ID string) *FlatPerson {
+	if ID == "" {
+		return nil
+	}
+	return i.first(i.byID[ID], i.byUUID[ID])
+}
+
+func (i *memoryIndex) get(search string) *FlatPerson {
+	return i.first(i.getByIDUUID(search), i.byName[search])
+}
+
+// getChildCandidates returns all persons linking to the given person as a parent in database order
+func (i *memoryIndex) getChildCandidates(p *FlatPerson) []*FlatPerson {
+	if p.UUID == "" || p.UUID == p.ID {
+		return i.children[p.ID]
+	}
+	if p.ID == "" {
+		return i.children[p.UUID]
+	}
+
+	// merge both lists keeping database order
+	a, b := i.children[p.ID], i.children[p.UUID]
+	result := make([]*FlatPerson, 0, len(a)+len(b))
+	for len(a) > 0 || len(b) > 0 {
+		var next *FlatPerson
+		switch {
+		case len(b) == 0 || (len(a) > 0 && i.position[a[0]] <= i.position[b[0]]):
+			next, a = a[0], a[1:]
+		default:
+			next, b = b[0], b[1:]
+		}
+		if len(result) > 0 && result[len(result)-1] == next {
+			continue
+		}
+		result = append(result, next)
+	}
+	return result
+}
+
+// getListingPartners returns all persons listing the given person as partner
+func (i *memoryIndex) getListingPartners(p *FlatPerson) []*FlatPerson {
+	result := i.partnerOf[p.ID]
+	if p.UUID != "" && p.UUID != p.ID {
+		result = append(result[:len(result):len(result)], i.partnerOf[p.UUID]...)
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package generations
 import (
 	"io/ioutil"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,36 @@ func TestParseYamlFile(t *testing.T) {
 	assert.Len(t, db.Persons, 1)
 }
 
+func TestParseYamlFileSources(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/database/sources.yml")
+	assert.Nil(t, err)
+	assert.Len(t, db.Sources, 3)
+
+	// default sources only apply to persons without sources
+	gauss, err := db.GetByID("gauss")
+	assert.Nil(t, err)
+	assert.Equal(t, []Reference{{SourceID: "chronik"}}, gauss.GetSources())
+	osthoff, err := db.GetByID("osthoff")
+	assert.Nil(t, err)
+	assert.Equal(t, "Johanna Osthoff, Tochter eines Weißgerbers", osthoff.GetSources()[0].Transcript)
+
+	// fact references
+	assert.Equal(t, ReferenceQualityPrimary, gauss.GetBirth().Sources[0].Quality)
+	assert.Equal(t, []string{"chronik", "kb-braunschweig"}, GetSourceIDs(gauss.GetAllReferences()))
+
+	source, err := db.GetSource("kb-braunschweig")
+	assert.Nil(t, err)
+	assert.Equal(t, "Kirchenbuch St. Katharinen. Landeskirchliches Archiv Braunschweig, KB 1777-1810", source.Format())
+	_, err = db.GetSource("missing")
+	assert.NotNil(t, err)
+
+	cited := db.GetCitedSources()
+	assert.Len(t, cited, 2)
+	assert.Equal(t, "kb-braunschweig", cited[0].ID)
+	assert.Equal(t, "chronik", cited[1].ID)
+}
+
 func TestGet(t *testing.T) {
 	db := NewMemoryDatabase()
 	db.ParseYamlFile("testdata/database/single-full-details.yml")
@@ -90,3 +121,147 @@ func TestMakeIDs(t *testing.T) {
 	assert.Equal(t, "Andr", p4.ID)
 	assert.Equal(t, "1999", p5.ID)
 }
+
+func TestIndexUpdates(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/database/children.yml")
+	assert.Nil(t, err)
+
+	// lookup by ID and UUID
+	p, err := db.GetByID("frau-gauss")
+	assert.Nil(t, err)
+	assert.Equal(t, "frau-gauss", p.GetUUID())
+
+	// changing IDs through the setter
+	p.SetID("johanna")
+	p, err = db.Get("johanna")
+	assert.Nil(t, err)
+	assert.Equal(t, "frau-gauss", p.GetUUID())
+
+	// changing parent links through the setter
+	sohn, err := db.GetByID("sohn")
+	assert.Nil(t, err)
+	sohn.SetRawMom("")
+	children, err := p.GetChildren()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"tochter"}, getPersonSliceIDs(children.GetPersons()))
+
+	// adding persons directly
+	db.Persons = append(db.Persons, &FlatPerson{ID: "enkel", Dad: "sohn", Database: db})
+	children, err = sohn.GetChildren()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"enkel"}, getPersonSliceIDs(children.GetPersons()))
+}
+
+// newBenchmarkDatabase generates a database of couples with four children each
+func newBenchmarkDatabase(size int) *MemoryDatabase {
+	db := NewMemoryDatabase()
+	db.Persons = make([]*FlatPerson, size)
+	for i := range db.Persons {
+		p := &FlatPerson{
+			ID:       "p" + strconv.Itoa(i),
+			Name:     Name{First: []string{"First" + strconv.Itoa(i)}, Last: "Last"},
+			Birth:    DatePlace{Date: Date(strconv.Itoa(1500 + i/20))},
+			Database: db,
+		}
+		if i%2 == 0 {
+			p.Gender = "male"
+			p.Partners = []FlatRelationship{{PartnerID: "p" + strconv.Itoa(i+1)}}
+		} else {
+			p.Gender = "female"
+		}
+		if i >= 2 {
+			couple := (i - 2) / 4
+			p.Dad = "p" + strconv.Itoa(2*couple)
+			p.Mom = "p" + strconv.Itoa(2*couple+1)
+		}
+		db.Persons[i] = p
+	}
+	db.Reindex()
+	return db
+}
+
+func benchmarkDatabase(b *testing.B, f func(db *MemoryDatabase, p Person)) {
+	for _, size := range []int{100, 1000, 10000} {
+		db := newBenchmarkDatabase(size)
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p, err := db.GetByID("p" + strconv.Itoa(i%size/2))
+				if err != nil {
+					b.Fatal(err)
+				}
+				f(db, p)
+			}
+		})
+	}
+}
+
+func BenchmarkGetByID(b *testing.B) {
+	benchmarkDatabase(b, func(db *MemoryDatabase, p Person) {})
+}
+
+func BenchmarkGet(b *testing.B) {
+	benchmarkDatabase(b, func(db *MemoryDatabase, p Person) {
+		if _, err := db.Get(p.GetName().First[0] + " Last"); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+func BenchmarkGetChildren(b *testing.B) {
+	benchmarkDatabase(b, func(db *MemoryDatabase, p Person) {
+		if _, err := p.GetChildren(); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+func BenchmarkGetChildrenWith(b *testing.B) {
+	benchmarkDatabase(b, func(db *MemoryDatabase, p Person) {
+		partners, err := p.GetPartners()
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, partner := range partners.GetPersons() {
+			if _, err := p.GetChildrenWith(partner); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkRenderGenealogytree(b *testing.B) {
+	o := RenderTreeOptions{
+		MaxParentGenerations:         3,
+		MaxParentSiblingsGenerations: 1,
+		MaxChildGenerations:          3,
+		MaxChildPartnersGenerations:  3,
+	}
+	addTestTemplates(&o)
+	o.SetDefaults()
+	benchmarkDatabase(b, func(db *MemoryDatabase, p Person) {
+		if _, err := RenderGenealogytree(p, o); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+func BenchmarkParseYamlFile(b *testing.B) {
+	tempFile, err := ioutil.TempFile("", "generations-bench-")
+	if err != nil {
+		b.Fatal(err)
+	}
+	tempFileName := tempFile.Name()
+	tempFile.Close()
+	defer os.Remove(tempFileName)
+	if err := newBenchmarkDatabase(10000).WriteYamlFile(tempFileName); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := NewMemoryDatabase().ParseYamlFile(tempFileName); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package generations
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	age "github.com/bearbin/go-age"
+	"github.com/juju/errors"
+)
+
+//go:generate go-enum -f=date.go
+
+/* ENUM(
+exact = 1
+about
+estimated
+before
+after
+between
+unknown
+*/
+type DateQualifier int
+
+// Date is a genealogical date as given in the database. Besides exact dates like "1850-03-12" it can hold
+// partial dates ("1850", "1850-03"), German notation ("12.03.1850", "03/1850") and qualified dates like
+// "before 1932", "after 1850", "about 1900", "est 1850", "between 1850 and 1855" or "?".
+type Date string
+
+// PartialDate is a date where month and day may be unknown (0)
+type PartialDate struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// FuzzyDate is the structured representation of a Date
+type FuzzyDate struct {
+	Qualifier DateQualifier
+	From      PartialDate
+	// To is only set for DateQualifierBetween
+	To PartialDate
+}
+
+var (
+	dateISORegexp       = regexp.MustCompile(`^(\d{3,4})(?:-(\d{1,2})(?:-(\d{1,2}))?)?$`)
+	dateGermanRegexp    = regexp.MustCompile(`^(?:(\d{1,2})\.)?(\d{1,2})[./](\d{3,4})$`)
+	dateBetweenRegexp   = regexp.MustCompile(`^(?:between|bet\.?|btw\.?|zwischen)\s+(.+?)\s+(?:and|und)\s+(.+)$`)
+	dateRangeRegexp     = regexp.MustCompile(`^\((.+)~(.+)\)$`)
+	dateBeforePrefixes  = []string{"before ", "bef. ", "bef ", "vor ", "/"}
+	dateAfterPrefixes   = []string{"after ", "aft. ", "aft ", "nach "}
+	dateAboutPrefixes   = []string{"about ", "abt. ", "abt ", "circa ", "ca. ", "ca ", "ca", "um ", "~"}
+	dateEstimatePrefix  = []string{"estimated ", "est. ", "est ", "geschätzt "}
+	dateUnknownSynonyms = []string{"?", "unknown", "unbekannt"}
+)
+
+// Empty returns true iff no date is given
+func (d Date) Empty() bool {
+	return strings.TrimSpace(string(d)) == ""
+}
+
+// Parse returns the structured representation of the date. Empty dates and "?" parse to DateQualifierUnknown.
+func (d Date) Parse() (FuzzyDate, error) {
+	input := strings.ToLower(strings.TrimSpace(string(d)))
+	for _, s := range dateUnknownSynonyms {
+		if input == s {
+			return FuzzyDate{Qualifier: DateQualifierUnknown}, nil
+		}
+	}
+	if input == "" {
+		return FuzzyDate{Qualifier: DateQualifierUnknown}, nil
+	}
+
+	// ranges
+	if m := dateBetweenRegexp.FindStringSubmatch(input); m != nil {
+		return parseDateRange(d, m[1], m[2])
+	}
+	if m := dateRangeRegexp.FindStringSubmatch(input); m != nil {
+		return parseDateRange(d, m[1], m[2])
+	}
+
+	// qualified dates
+	prefixed := []struct {
+		Prefixes  []string
+		Qualifier DateQualifier
+	}{
+		{dateBeforePrefixes, DateQualifierBefore},
+		{dateAfterPrefixes, DateQualifierAfter},
+		{dateEstimatePrefix, DateQualifierEstimated},
+		{dateAboutPrefixes, DateQualifierAbout},
+	}
+	for _, p := range prefixed {
+		for _, prefix := range p.Prefixes {
+			if !strings.HasPrefix(input, prefix) {
+				continue
+			}
+			partial, err := parsePartialDate(strings.TrimPrefix(input, prefix))
+			if err != nil {
+				return FuzzyDate{}, errors.Errorf("invalid date %q: %s", string(d), err)
+			}
+			return FuzzyDate{Qualifier: p.Qualifier, From: partial}, nil
+		}
+	}
+	if strings.HasSuffix(input, "/") {
+		partial, err := parsePartialDate(strings.TrimSuffix(input, "/"))
+		if err != nil {
+			return FuzzyDate{}, errors.Errorf("invalid date %q: %s", string(d), err)
+		}
+		return FuzzyDate{Qualifier: DateQualifierAfter, From: partial}, nil
+	}
+	if strings.HasSuffix(input, "?") {
+		partial, err := parsePartialDate(strings.TrimSuffix(input, "?"))
+		if err != nil {
+			return FuzzyDate{}, errors.Errorf("invalid date %q: %s", string(d), err)
+		}
+		return FuzzyDate{Qualifier: DateQualifierEstimated, From: partial}, nil
+	}
+
+	partial, err := parsePartialDate(input)
+	if err != nil {
+		return FuzzyDate{}, errors.Errorf("invalid date %q: %s", string(d), err)
+	}
+	return FuzzyDate{Qualifier: DateQualifierExact, From: partial}, nil
+}
+
+func parseDateRange(d Date, from, to string) (FuzzyDate, error) {
+	fromDate, err := parsePartialDate(strings.TrimSpace(from))
+	if err != nil {
+		return FuzzyDate{}, errors.Errorf("invalid date %q: %s", string(d), err)
+	}
+	toDate, err := parsePartialDate(strings.TrimSpace(to))
+	if err != nil {
+		return FuzzyDate{}, errors.Errorf("invalid date %q: %s", string(d), err)
+	}
+	if toDate.Earliest().Before(fromDate.Earliest()) {
+		return FuzzyDate{}, errors.Errorf("invalid date %q: end of range before its beginning", string(d))
+	}
+	return FuzzyDate{Qualifier: DateQualifierBetween, From: fromDate, To: toDate}, nil
+}
+
+func parsePartialDate(input string) (PartialDate, error) {
+	var year, month, day string
+	if m := dateISORegexp.FindStringSubmatch(input); m != nil {
+		year, month, day = m[1], m[2], m[3]
+	} else if m := dateGermanRegexp.FindStringSubmatch(input); m != nil {
+		day, month, year = m[1], m[2], m[3]
+	} else {
+		return PartialDate{}, errors.Errorf("unknown date format %q", input)
+	}
+
+	var p PartialDate
+	p.Year, _ = strconv.Atoi(year)
+	if month != "" {
+		p.Month, _ = strconv.Atoi(month)
+		if p.Month < 1 || p.Month > 12 {
+			return PartialDate{}, errors.Errorf("invalid month %d", p.Month)
+		}
+	}
+	if day != "" {
+		p.Day, _ = strconv.Atoi(day)
+		if p.Day < 1 || p.Day > daysIn(p.Year, p.Month) {
+			return PartialDate{}, errors.Errorf("invalid day %d", p.Day)
+		}
+	}
+	return p, nil
+}
+
+func daysIn(year, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Earliest returns the first day the partial date could refer to
+func (p PartialDate) Earliest() time.Time {
+	month, day := p.Month, p.Day
+	if month == 0 {
+		month = 1
+	}
+	if day == 0 {
+		day = 1
+	}
+	return time.Date(p.Year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Latest returns the last day the partial date could refer to
+func (p PartialDate) Latest() time.Time {
+	month, day := p.Month, p.Day
+	if month == 0 {
+		month = 12
+	}
+	if day == 0 {
+		day = daysIn(p.Year, month)
+	}
+	return time.Date(p.Year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// String formats the partial date as yyyy, yyyy-mm or yyyy-mm-dd
+func (p PartialDate) String() string {
+	switch {
+	case p.Month == 0:
+		return fmt.Sprintf("%04d", p.Year)
+	case p.Day == 0:
+		return fmt.Sprintf("%04d-%02d", p.Year, p.Month)
+	default:
+		return fmt.Sprintf("%04d-%02d-%02d", p.Year, p.Month, p.Day)
+	}
+}
+
+// Format formats the partial date for readers using the date messages of the locale, e.g. "12.03.1850" in German or
+// "12 March 1850" in English
+func (p PartialDate) Format(l Locale) string {
+	key := "date.day"
+	switch {
+	case p.Month == 0:
+		key = "date.year"
+	case p.Day == 0:
+		key = "date.month"
+	}
+	return strings.NewReplacer(
+		"{day}", strconv.Itoa(p.Day),
+		"{day2}", fmt.Sprintf("%02d", p.Day),
+		"{month}", strconv.Itoa(p.Month),
+		"{month2}", fmt.Sprintf("%02d", p.Month),
+		"{monthName}", l.T("month."+strconv.Itoa(p.Month)),
+		"{year}", strconv.Itoa(p.Year),
+	).Replace(l.T(key))
+}
+
+// IsUnknown returns true iff nothing is known about the date
+func (f FuzzyDate) IsUnknown() bool {
+	return f.Qualifier == DateQualifierUnknown || f.Qualifier == 0
+}
+
+// IsApproximate returns true for dates qualified as about or estimated
+func (f FuzzyDate) IsApproximate() bool {
+	return f.Qualifier == DateQualifierAbout || f.Qualifier == DateQualifierEstimated
+}
+
+// Earliest returns the earliest point in time the date could refer to, false if there is no lower bound
+func (f FuzzyDate) Earliest() (time.Time, bool) {
+	switch f.Qualifier {
+	case DateQualifierExact, DateQualifierAbout, DateQualifierEstimated, DateQualifierBetween, DateQualifierAfter:
+		return f.From.Earliest(), true
+	}
+	return time.Time{}, false
+}
+
+// Latest returns the latest point in time the date could refer to, false if there is no upper bound
+func (f FuzzyDate) Latest() (time.Time, bool) {
+	switch f.Qualifier {
+	case DateQualifierExact, DateQualifierAbout, DateQualifierEstimated, DateQualifierBefore:
+		return f.From.Latest(), true
+	case DateQualifierBetween:
+		return f.To.Latest(), true
+	}
+	return time.Time{}, false
+}
+
+// sortTime is the point in time used to order dates
+func (f FuzzyDate) sortTime() time.Time {
+	if f.Qualifier == DateQualifierBefore {
+		return f.From.Earliest().AddDate(0, 0, -1)
+	}
+	return f.From.Earliest()
+}
+
+// String formats the date in the database syntax (e.g. "about 1850")
+func (f FuzzyDate) String() string {
+	switch f.Qualifier {
+	case DateQualifierExact:
+		return f.From.String()
+	case DateQualifierAbout:
+		return "about " + f.From.String()
+	case DateQualifierEstimated:
+		return "est " + f.From.String()
+	case DateQualifierBefore:
+		return "before " + f.From.String()
+	case DateQualifierAfter:
+		return "after " + f.From.String()
+	case DateQualifierBetween:
+		return "between " + f.From.String() + " and " + f.To.String()
+	}
+	return ""
+}
+
+// dateQualifierMessages are the message keys of qualified dates
+var dateQualifierMessages = map[DateQualifier]string{
+	DateQualifierAbout:     "date.about",
+	DateQualifierEstimated: "date.estimated",
+	DateQualifierBefore:    "date.before",
+	DateQualifierAfter:     "date.after",
+	DateQualifierBetween:   "date.between",
+}
+
+// Format formats the date for readers in the given locale, e.g. "about 1850" or "zwischen 1850 und 1855"
+func (f FuzzyDate) Format(l Locale) string {
+	switch f.Qualifier {
+	case 0, DateQualifierUnknown:
+		return l.T("date.unknown")
+	case DateQualifierExact:
+		return f.From.Format(l)
+	case DateQualifierBetween:
+		return l.Tf(dateQualifierMessages[f.Qualifier], f.From.Format(l), f.To.Format(l))
+	}
+	return l.Tf(dateQualifierMessages[f.Qualifier], f.From.Format(l))
+}
+
+// Genealogytree formats the date in the date syntax of the genealogytree LaTeX package
+func (f FuzzyDate) Genealogytree() LaTeX {
+	switch f.Qualifier {
+	case DateQualifierExact:
+		return LaTeX(f.From.String())
+	case DateQualifierAbout, DateQualifierEstimated:
+		return LaTeX("ca" + f.From.String())
+	case DateQualifierBefore:
+		return LaTeX("/" + f.From.String())
+	case DateQualifierAfter:
+		return LaTeX(f.From.String() + "/")
+	case DateQualifierBetween:
+		return LaTeX("(" + f.From.String() + "~" + f.To.String() + ")")
+	}
+	return ""
+}
+
+// Genealogytree formats the date for the genealogytree LaTeX package. Dates that can't be parsed are returned escaped.
+func (d Date) Genealogytree() LaTeX {
+	f, err := d.Parse()
+	if err != nil {
+		return EscapeLaTeX(string(d))
+	}
+	return f.Genealogytree()
+}
+
+// Format formats the date for readers in the given locale. Dates that can't be parsed are returned unchanged.
+func (d Date) Format(l Locale) string {
+	if d.Empty() {
+		return ""
+	}
+	f, err := d.Parse()
+	if err != nil {
+		return string(d)
+	}
+	return f.Format(l)
+}
+
+// Year returns the (first) year of the date, false iff it is unknown
+func (d Date) Year() (int, bool) {
+	f, err := d.Parse()
+	if err != nil || f.IsUnknown() {
+		return 0, false
+	}
+	return f.From.Year, true
+}
+
+// YearOnly returns the date with month and day information removed
+func (d Date) YearOnly() Date {
+	f, err := d.Parse()
+	if err != nil {
+		return Date(first(string(d), 4))
+	}
+	f.From = PartialDate{Year: f.From.Year}
+	if f.Qualifier == DateQualifierBetween {
+		f.To = PartialDate{Year: f.To.Year}
+		if f.From == f.To {
+			f.Qualifier = DateQualifierExact
+		}
+	}
+	return Date(f.String())
+}
+
+// Compare returns -1, 0 or 1 depending on d being before, equal to or after other. Unknown or invalid dates are
+// ordered before known ones.
+func (d Date) Compare(other Date) int {
+	a, errA := d.Parse()
+	b, errB := other.Parse()
+	aKnown := errA == nil && !a.IsUnknown()
+	bKnown := errB == nil && !b.IsUnknown()
+	switch {
+	case !aKnown && !bKnown:
+		return strings.Compare(string(d), string(other))
+	case !aKnown:
+		return -1
+	case !bKnown:
+		return 1
+	}
+	ta, tb := a.sortTime(), b.sortTime()
+	switch {
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	}
+	return 0
+}
+
+// Before returns true iff d is to be sorted before other
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// AgeRange is an age in years that can be known only approximately
+type AgeRange struct {
+	Min         int
+	Max         int
+	Approximate bool
+}
+
+// NoAge is the AgeRange for ages that can't be determined
+var NoAge = AgeRange{Min: -1, Max: -1}
+
+// Empty returns true iff the age can't be determined
+func (a AgeRange) Empty() bool {
+	return a.Min < 0 || a.Max < 0
+}
+
+// String formats the age as "63", "~63" or "62–63"
+func (a AgeRange) String() string {
+	if a.Empty() {
+		return ""
+	}
+	var result string
+	if a.Min == a.Max {
+		result = strconv.Itoa(a.Min)
+	} else {
+		result = strconv.Itoa(a.Min) + "–" + strconv.Itoa(a.Max)
+	}
+	if a.Approximate {
+		result = "~" + result
+	}
+	return result
+}
+
+// AgeBetween returns the age of somebody born at birth at the date event
+func AgeBetween(birth, event Date) AgeRange {
+	b, err := birth.Parse()
+	if err != nil || b.IsUnknown() {
+		return NoAge
+	}
+	e, err := event.Parse()
+	if err != nil || e.IsUnknown() {
+		return NoAge
+	}
+	return ageBetween(b, e)
+}
+
+// AgeAt returns the age of somebody born at birth at the given point of time
+func AgeAt(birth Date, now time.Time) AgeRange {
+	if now.IsZero() {
+		return NoAge
+	}
+	b, err := birth.Parse()
+	if err != nil || b.IsUnknown() {
+		return NoAge
+	}
+	return ageBetween(b, FuzzyDate{
+		Qualifier: DateQualifierExact,
+		From:      PartialDate{Year: now.Year(), Month: int(now.Month()), Day: now.Day()},
+	})
+}
+
+func ageBetween(birth, event FuzzyDate) AgeRange {
+	birthEarliest, ok1 := birth.Earliest()
+	birthLatest, ok2 := birth.Latest()
+	eventEarliest, ok3 := event.Earliest()
+	eventLatest, ok4 := event.Latest()
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return NoAge
+	}
+	result := AgeRange{
+		Min:         age.AgeAt(birthLatest, eventEarliest),
+		Max:         age.AgeAt(birthEarliest, eventLatest),
+		Approximate: birth.IsApproximate() || event.IsApproximate(),
+	}
+	if result.Max < 0 {
+		return NoAge
+	}
+	if result.Min < 0 {
+		result.Min = 0
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// DateQualifierExact is a DateQualifier of type Exact
+	DateQualifierExact DateQualifier = iota + 1
+	// DateQualifierAbout is a DateQualifier of type About
+	DateQualifierAbout
+	// DateQualifierEstimated is a DateQualifier of type Estimated
+	DateQualifierEstimated
+	// DateQualifierBefore is a DateQualifier of type Before
+	DateQualifierBefore
+	// DateQualifierAfter is a DateQualifier of type After
+	DateQualifierAfter
+	// DateQualifierBetween is a DateQualifier of type Between
+	DateQualifierBetween
+	// DateQualifierUnknown is a DateQualifier of type Unknown
+	DateQualifierUnknown
+)
+
+const _DateQualifierName = "exactaboutestimatedbeforeafterbetweenunknown"
+
+var _DateQualifierMap = map[DateQualifier]string{
+	1: _DateQualifierName[0:5],
+	2: _DateQualifierName[5:10],
+	3: _DateQualifierName[10:19],
+	4: _DateQualifierName[19:25],
+	5: _DateQualifierName[25:30],
+	6: _DateQualifierName[30:37],
+	7: _DateQualifierName[37:44],
+}
+
+// String implements the Stringer interface.
+func (x DateQualifier) String() string {
+	if str, ok := _DateQualifierMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("DateQualifier(%d)", x)
+}
+
+var _DateQualifierValue = map[string]DateQualifier{
+	_DateQualifierName[0:5]:   1,
+	_DateQualifierName[5:10]:  2,
+	_DateQualifierName[10:19]: 3,
+	_DateQualifierName[19:25]: 4,
+	_DateQualifierName[25:30]: 5,
+	_DateQualifierName[30:37]: 6,
+	_DateQualifierName[37:44]: 7,
+}
+
+// ParseDateQualifier attempts to convert a string to a DateQualifier
+func ParseDateQualifier(name string) (DateQualifier, error) {
+	if x, ok := _DateQualifierValue[name]; ok {
+		return x, nil
+	}
+	return DateQualifier(0), fmt.Errorf("%s is not a valid DateQualifier", name)
+}
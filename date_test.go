@@ -0,0 +1,115 @@
+package generations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateParse(t *testing.T) {
+	tests := []struct {
+		Input    Date
+		Expected FuzzyDate
+		Error    bool
+	}{
+		{Input: "", Expected: FuzzyDate{Qualifier: DateQualifierUnknown}},
+		{Input: "?", Expected: FuzzyDate{Qualifier: DateQualifierUnknown}},
+		{Input: "1850", Expected: FuzzyDate{Qualifier: DateQualifierExact, From: PartialDate{Year: 1850}}},
+		{Input: "1850-03", Expected: FuzzyDate{Qualifier: DateQualifierExact, From: PartialDate{Year: 1850, Month: 3}}},
+		{Input: "1850-03-12", Expected: FuzzyDate{Qualifier: DateQualifierExact, From: PartialDate{Year: 1850, Month: 3, Day: 12}}},
+		{Input: "12.03.1850", Expected: FuzzyDate{Qualifier: DateQualifierExact, From: PartialDate{Year: 1850, Month: 3, Day: 12}}},
+		{Input: "10/1854", Expected: FuzzyDate{Qualifier: DateQualifierExact, From: PartialDate{Year: 1854, Month: 10}}},
+		{Input: "before 1932", Expected: FuzzyDate{Qualifier: DateQualifierBefore, From: PartialDate{Year: 1932}}},
+		{Input: "vor 1932", Expected: FuzzyDate{Qualifier: DateQualifierBefore, From: PartialDate{Year: 1932}}},
+		{Input: "/1932", Expected: FuzzyDate{Qualifier: DateQualifierBefore, From: PartialDate{Year: 1932}}},
+		{Input: "after 1850-05", Expected: FuzzyDate{Qualifier: DateQualifierAfter, From: PartialDate{Year: 1850, Month: 5}}},
+		{Input: "1850/", Expected: FuzzyDate{Qualifier: DateQualifierAfter, From: PartialDate{Year: 1850}}},
+		{Input: "about 1900", Expected: FuzzyDate{Qualifier: DateQualifierAbout, From: PartialDate{Year: 1900}}},
+		{Input: "um 1826", Expected: FuzzyDate{Qualifier: DateQualifierAbout, From: PartialDate{Year: 1826}}},
+		{Input: "ca1900", Expected: FuzzyDate{Qualifier: DateQualifierAbout, From: PartialDate{Year: 1900}}},
+		{Input: "est 1850", Expected: FuzzyDate{Qualifier: DateQualifierEstimated, From: PartialDate{Year: 1850}}},
+		{Input: "1850?", Expected: FuzzyDate{Qualifier: DateQualifierEstimated, From: PartialDate{Year: 1850}}},
+		{Input: "between 1850 and 1855", Expected: FuzzyDate{Qualifier: DateQualifierBetween, From: PartialDate{Year: 1850}, To: PartialDate{Year: 1855}}},
+		{Input: "(1850~1855)", Expected: FuzzyDate{Qualifier: DateQualifierBetween, From: PartialDate{Year: 1850}, To: PartialDate{Year: 1855}}},
+		// errors
+		{Input: "next summer", Error: true},
+		{Input: "1850-13", Error: true},
+		{Input: "1850-02-30", Error: true},
+		{Input: "between 1855 and 1850", Error: true},
+	}
+
+	for _, test := range tests {
+		result, err := test.Input.Parse()
+		if test.Error {
+			assert.NotNil(t, err, string(test.Input))
+			continue
+		}
+		assert.Nil(t, err, string(test.Input))
+		assert.Equal(t, test.Expected, result, string(test.Input))
+	}
+}
+
+func TestDateGenealogytree(t *testing.T) {
+	tests := []struct {
+		Input    Date
+		Expected LaTeX
+	}{
+		{Input: "", Expected: ""},
+		{Input: "1850", Expected: "1850"},
+		{Input: "24.12.1999", Expected: "1999-12-24"},
+		{Input: "before 1932", Expected: "/1932"},
+		{Input: "after 1932", Expected: "1932/"},
+		{Input: "about 1900", Expected: "ca1900"},
+		{Input: "between 1850 and 1855", Expected: "(1850~1855)"},
+		{Input: "next summer", Expected: "next summer"},
+		{Input: "~1850 (50% sure)", Expected: `\textasciitilde{}1850 (50\% sure)`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, test.Input.Genealogytree(), string(test.Input))
+	}
+}
+
+func TestDateYearOnly(t *testing.T) {
+	assert.Equal(t, Date("1850"), Date("1850-03-12").YearOnly())
+	assert.Equal(t, Date("about 1900"), Date("um 1900-05").YearOnly())
+	assert.Equal(t, Date("1850"), Date("between 1850-01 and 1850-06").YearOnly())
+	assert.Equal(t, Date("next"), Date("next summer").YearOnly())
+}
+
+func TestDateCompare(t *testing.T) {
+	assert.True(t, Date("1850").Before("1851"))
+	assert.True(t, Date("1850-03").Before("1850-04-01"))
+	assert.True(t, Date("before 1850").Before("1850"))
+	assert.True(t, Date("").Before("1850"))
+	assert.False(t, Date("1850").Before("1850"))
+	assert.Equal(t, 0, Date("1850").Compare("about 1850"))
+	assert.Equal(t, 1, Date("after 1850").Compare("1849-12-31"))
+}
+
+func TestAgeBetween(t *testing.T) {
+	tests := []struct {
+		Birth    Date
+		Event    Date
+		Expected string
+	}{
+		{Birth: "1850-03-12", Event: "1913-03-12", Expected: "63"},
+		{Birth: "1850-03-12", Event: "1913-03-11", Expected: "62"},
+		{Birth: "1850", Event: "1913-05-01", Expected: "62–63"},
+		{Birth: "about 1850-03-12", Event: "1913-05-01", Expected: "~63"},
+		{Birth: "between 1840 and 1850", Event: "1900", Expected: "49–60"},
+		{Birth: "before 1850", Event: "1900", Expected: ""},
+		{Birth: "?", Event: "1900", Expected: ""},
+		{Birth: "1900", Event: "1850", Expected: ""},
+	}
+
+	for _, test := range tests {
+		result := AgeBetween(test.Birth, test.Event)
+		assert.Equal(t, test.Expected, result.String(), string(test.Birth)+" → "+string(test.Event))
+		assert.Equal(t, test.Expected == "", result.Empty(), string(test.Birth)+" → "+string(test.Event))
+	}
+
+	assert.Equal(t, "63", AgeAt("1850-03-12", time.Date(1913, 3, 12, 0, 0, 0, 0, time.UTC)).String())
+	assert.True(t, AgeAt("1850-03-12", time.Time{}).Empty())
+}
@@ -0,0 +1,141 @@
+package generations
+
+import (
+	"fmt"
+	"strconv"
+)
+
+//go:generate go-enum -f=descendant_register.go --marshal
+
+// NumberingScheme selects the numbers of descendants in a register
+/* ENUM(
+daboville = 1
+henry
+*/
+type NumberingScheme int
+
+// DescendantRegister lists a person and its descendants with their partners and children
+type DescendantRegister struct {
+	Root   Person
+	Scheme NumberingScheme
+	// Entries are the descendants in depth-first order
+	Entries []DescendantEntry
+}
+
+// DescendantEntry is a descendant with its partnerships
+type DescendantEntry struct {
+	Number string
+	// Generation is 0 for the root, 1 for its children and so on
+	Generation int
+	Person     Person
+	Unions     []DescendantUnion
+}
+
+// DescendantUnion is a partner of a descendant and their common children
+type DescendantUnion struct {
+	// Partner is a dummy if unknown
+	Partner  Person
+	Children []DescendantChild
+}
+
+// DescendantChild is a child in a union
+type DescendantChild struct {
+	Number string
+	Person Person
+	// ListedAs is the number of the entry of the person if it is listed under another number already (implex)
+	ListedAs string
+}
+
+// NewDescendantRegister lists the biological descendants of root up to o.MaxChildGenerations, ignored persons are
+// skipped
+func NewDescendantRegister(root Person, scheme NumberingScheme, o RenderTreeOptions) (*DescendantRegister, error) {
+	o.SetDefaults()
+	if scheme == 0 {
+		scheme = NumberingSchemeDaboville
+	}
+	r := &DescendantRegister{
+		Root:   root,
+		Scheme: scheme,
+	}
+	if root.IsDummy() || isPersonIgnored(root, o) {
+		return r, nil
+	}
+	err := r.add(root, "1", 0, make(map[string]string), o)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// add appends the entry for p and those of its descendants, listed maps the IDs of the persons with an entry to their
+// numbers
+func (r *DescendantRegister) add(p Person, number string, generation int, listed map[string]string, o RenderTreeOptions) error {
+	listed[p.GetBestID()] = number
+	index := len(r.Entries)
+	r.Entries = append(r.Entries, DescendantEntry{
+		Number:     number,
+		Generation: generation,
+		Person:     p,
+	})
+
+	partners, err := p.GetPartners()
+	if err != nil {
+		return err
+	}
+	var (
+		unions []DescendantUnion
+		// descendants are listed after all children of p are known
+		descendants []DescendantChild
+		childIndex  int
+	)
+	partners = nonIgnored(partners, o)
+	for _, partner := range partners.GetPersons() {
+		union := DescendantUnion{
+			Partner: partner,
+		}
+		if generation < o.MaxChildGenerations {
+			children, err := p.GetChildrenWith(partner)
+			if err != nil {
+				return err
+			}
+			children = nonIgnored(children, o)
+			for _, child := range children.GetPersons() {
+				childIndex++
+				c := DescendantChild{
+					Number:   r.Scheme.childNumber(number, childIndex),
+					Person:   child,
+					ListedAs: listed[child.GetBestID()],
+				}
+				if c.ListedAs == "" {
+					// reserve the number so that later mentions refer to it
+					listed[child.GetBestID()] = c.Number
+					descendants = append(descendants, c)
+				}
+				union.Children = append(union.Children, c)
+			}
+		}
+		unions = append(unions, union)
+	}
+	r.Entries[index].Unions = unions
+
+	for _, d := range descendants {
+		err = r.add(d.Person, d.Number, generation+1, listed, o)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// childNumber returns the number of child number index (counting from 1) of the person numbered parent
+func (x NumberingScheme) childNumber(parent string, index int) string {
+	switch x {
+	case NumberingSchemeHenry:
+		if index > 9 {
+			return fmt.Sprintf("%s(%d)", parent, index)
+		}
+		return parent + strconv.Itoa(index)
+	default:
+		return parent + "." + strconv.Itoa(index)
+	}
+}
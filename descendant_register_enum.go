@@ -0,0 +1,59 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// NumberingSchemeDaboville is a NumberingScheme of type Daboville
+	NumberingSchemeDaboville NumberingScheme = iota + 1
+	// NumberingSchemeHenry is a NumberingScheme of type Henry
+	NumberingSchemeHenry
+)
+
+const _NumberingSchemeName = "dabovillehenry"
+
+var _NumberingSchemeMap = map[NumberingScheme]string{
+	1: _NumberingSchemeName[0:9],
+	2: _NumberingSchemeName[9:14],
+}
+
+// String implements the Stringer interface.
+func (x NumberingScheme) String() string {
+	if str, ok := _NumberingSchemeMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("NumberingScheme(%d)", x)
+}
+
+var _NumberingSchemeValue = map[string]NumberingScheme{
+	_NumberingSchemeName[0:9]:  1,
+	_NumberingSchemeName[9:14]: 2,
+}
+
+// ParseNumberingScheme attempts to convert a string to a NumberingScheme
+func ParseNumberingScheme(name string) (NumberingScheme, error) {
+	if x, ok := _NumberingSchemeValue[name]; ok {
+		return x, nil
+	}
+	return NumberingScheme(0), fmt.Errorf("%s is not a valid NumberingScheme", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x NumberingScheme) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *NumberingScheme) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseNumberingScheme(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
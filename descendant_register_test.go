@@ -0,0 +1,107 @@
+package generations
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDescendantRegister(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "register.yml"))
+	assert.Nil(t, err)
+	root, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name     string
+		scheme   NumberingScheme
+		options  RenderTreeOptions
+		expected []string
+	}{
+		{
+			name:   "d'Aboville",
+			scheme: NumberingSchemeDaboville,
+			expected: []string{
+				"1 gauss: erste-frau (1.1 tochter, 1.2 sohn), zweite-frau (1.3 nachzuegler)",
+				"1.1 tochter: schwiegersohn (1.1.1 enkel)",
+				"1.1.1 enkel: ? (1.1.1.1 urenkel)",
+				"1.1.1.1 urenkel:",
+				"1.2 sohn:",
+				"1.3 nachzuegler:",
+			},
+		},
+		{
+			name:   "Henry",
+			scheme: NumberingSchemeHenry,
+			expected: []string{
+				"1 gauss: erste-frau (11 tochter, 12 sohn), zweite-frau (13 nachzuegler)",
+				"11 tochter: schwiegersohn (111 enkel)",
+				"111 enkel: ? (1111 urenkel)",
+				"1111 urenkel:",
+				"12 sohn:",
+				"13 nachzuegler:",
+			},
+		},
+		{
+			name:   "limited",
+			scheme: NumberingSchemeDaboville,
+			options: RenderTreeOptions{
+				MaxChildGenerations: 1,
+				IgnoreIDs:           []string{"sohn"},
+			},
+			expected: []string{
+				"1 gauss: erste-frau (1.1 tochter), zweite-frau (1.2 nachzuegler)",
+				"1.1 tochter: schwiegersohn",
+				"1.2 nachzuegler:",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		r, err := NewDescendantRegister(root, test.scheme, test.options)
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.expected, formatDescendantEntries(r.Entries), test.name)
+	}
+}
+
+func TestNumberingSchemeChildNumber(t *testing.T) {
+	assert.Equal(t, "1.2.10", NumberingSchemeDaboville.childNumber("1.2", 10))
+	assert.Equal(t, "129", NumberingSchemeHenry.childNumber("12", 9))
+	assert.Equal(t, "12(10)", NumberingSchemeHenry.childNumber("12", 10))
+}
+
+func formatDescendantEntries(entries []DescendantEntry) []string {
+	var result []string
+	for _, e := range entries {
+		line := fmt.Sprintf("%s %s:", e.Number, e.Person.GetID())
+		for i, u := range e.Unions {
+			if i > 0 {
+				line += ","
+			}
+			partner := u.Partner.GetID()
+			if u.Partner.IsDummy() {
+				partner = "?"
+			}
+			line += " " + partner
+			if len(u.Children) == 0 {
+				continue
+			}
+			line += " ("
+			for j, c := range u.Children {
+				if j > 0 {
+					line += ", "
+				}
+				line += c.Number + " " + c.Person.GetID()
+				if c.ListedAs != "" {
+					line += " = " + c.ListedAs
+				}
+			}
+			line += ")"
+		}
+		result = append(result, line)
+	}
+	return result
+}
@@ -0,0 +1,190 @@
+package generations
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// dotAttributeStyles are the DOT node attributes for person attributes
+var dotAttributeStyles = map[string]string{
+	"dead":     `fillcolor="gray90"`,
+	"rootnode": `penwidth=2.5`,
+}
+
+// DotGraph is a graph of persons connected by their unions, written in the DOT language of Graphviz
+type DotGraph struct {
+	persons   []Person
+	linkTypes []ParentLinkType
+
+	unions     []*dotUnion
+	unionIndex map[string]*dotUnion
+}
+
+// dotUnion is a junction node between parents or partners and their children
+type dotUnion struct {
+	ID       string
+	Parents  []Person
+	Children []dotChild
+}
+
+type dotChild struct {
+	Person Person
+	Type   ParentLinkType
+}
+
+// NewDotGraph returns the graph of all persons in the database connected by parent links of any type
+func NewDotGraph(y *MemoryDatabase) (*DotGraph, error) {
+	return newDotGraph(y.GetPersons(), []ParentLinkType{
+		ParentLinkTypeBiological,
+		ParentLinkTypeAdoptive,
+		ParentLinkTypeStep,
+		ParentLinkTypeFoster,
+	})
+}
+
+// NewDotTreeGraph returns the graph of the persons in the sandclock of proband, limited and filtered like a tree
+func NewDotTreeGraph(proband Person, o RenderTreeOptions) (*DotGraph, error) {
+	o.GraphType = GraphTypeSandclock
+	o.HideImplex = true
+	m, err := BuildTree(proband, o)
+	if err != nil {
+		return nil, errors.Annotate(err, "could not build tree")
+	}
+	return newDotGraph(m.Persons(), m.Options.ParentLinkTypes)
+}
+
+func newDotGraph(persons []Person, linkTypes []ParentLinkType) (*DotGraph, error) {
+	g := &DotGraph{
+		persons:    persons,
+		linkTypes:  linkTypes,
+		unionIndex: make(map[string]*dotUnion),
+	}
+	included := make(map[string]Person, len(persons))
+	for _, p := range persons {
+		included[p.GetBestID()] = p
+	}
+	for _, p := range persons {
+		// the parents of every link type are a union of their own, e.g. adoptive parents
+		for _, linkType := range linkTypes {
+			parentList, err := p.GetParentsByType(linkType)
+			if err != nil {
+				return nil, err
+			}
+			var parents []Person
+			for _, parent := range parentList.GetPersons() {
+				if _, ok := included[parent.GetBestID()]; ok {
+					parents = append(parents, parent)
+				}
+			}
+			if len(parents) > 0 {
+				u := g.union(parents...)
+				u.Children = append(u.Children, dotChild{Person: p, Type: linkType})
+			}
+		}
+	}
+	// partners without children in the graph are connected too
+	for _, p := range persons {
+		partners, err := p.GetPartners()
+		if err != nil {
+			return nil, err
+		}
+		for _, partner := range partners.GetPersons() {
+			if _, ok := included[partner.GetBestID()]; ok {
+				g.union(p, partner)
+			}
+		}
+	}
+	return g, nil
+}
+
+// union returns the union of the given persons, it is created if it does not exist yet
+func (g *DotGraph) union(persons ...Person) *dotUnion {
+	ids := make([]string, len(persons))
+	for i, p := range persons {
+		ids[i] = p.GetBestID()
+	}
+	sort.Strings(ids)
+	key := strings.Join(ids, "\x00")
+	if u, ok := g.unionIndex[key]; ok {
+		return u
+	}
+	u := &dotUnion{
+		ID:      fmt.Sprintf("union-%d", len(g.unions)+1),
+		Parents: persons,
+	}
+	g.unions = append(g.unions, u)
+	g.unionIndex[key] = u
+	return u
+}
+
+// WriteDot writes the graph in the DOT language
+func (g *DotGraph) WriteDot(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("digraph generations {\n")
+	b.WriteString("\tnode [shape=box, style=\"rounded,filled\", fillcolor=white, fontname=\"sans-serif\"];\n")
+	b.WriteString("\tedge [arrowhead=none];\n")
+	for _, p := range g.persons {
+		fmt.Fprintf(b, "\t%s [%s];\n", dotQuote(p.GetBestID()), strings.Join(dotPersonAttributes(p), ", "))
+	}
+	for _, u := range g.unions {
+		fmt.Fprintf(b, "\t%s [shape=point, width=0.08, label=\"\"];\n", dotQuote(u.ID))
+		for _, parent := range u.Parents {
+			fmt.Fprintf(b, "\t%s -> %s;\n", dotQuote(parent.GetBestID()), dotQuote(u.ID))
+		}
+		for _, child := range u.Children {
+			var style string
+			if child.Type != ParentLinkTypeBiological {
+				style = fmt.Sprintf(" [style=dashed, label=%s]", dotQuote(child.Type.String()))
+			}
+			fmt.Fprintf(b, "\t%s -> %s%s;\n", dotQuote(u.ID), dotQuote(child.Person.GetBestID()), style)
+		}
+	}
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+func dotPersonAttributes(p Person) []string {
+	result := []string{"label=" + dotQuote(dotLabel(p))}
+	switch p.GetGender() {
+	case GenderMale:
+		result = append(result, `color="steelblue"`)
+	case GenderFemale:
+		result = append(result, `color="indianred"`)
+	}
+	for _, attribute := range p.GetAttributes() {
+		if style, ok := dotAttributeStyles[attribute]; ok {
+			result = append(result, style)
+		}
+	}
+	return result
+}
+
+// dotLabel returns the name of a person with the years of birth and death, the ID if the name is unknown
+func dotLabel(p Person) string {
+	label := strings.TrimSpace(p.GetName().FormatFullNoMiddle())
+	if label == "" {
+		label = p.GetBestID()
+	}
+	birth, hasBirth := p.GetBirth().Date.Year()
+	death, hasDeath := p.GetDeath().Date.Year()
+	switch {
+	case hasBirth && hasDeath:
+		label += fmt.Sprintf("\n%d–%d", birth, death)
+	case hasBirth:
+		label += fmt.Sprintf("\n* %d", birth)
+	case hasDeath:
+		label += fmt.Sprintf("\n† %d", death)
+	}
+	return label
+}
+
+// dotQuote returns s as a quoted DOT ID
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,105 @@
+package generations
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDotGraph(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "adoption.yml"))
+	assert.Nil(t, err)
+
+	g, err := NewDotGraph(database)
+	assert.Nil(t, err)
+	var buffer bytes.Buffer
+	err = g.WriteDot(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, `digraph generations {
+	node [shape=box, style="rounded,filled", fillcolor=white, fontname="sans-serif"];
+	edge [arrowhead=none];
+	"gauss" [label="gauss"];
+	"mama" [label="mama", color="indianred"];
+	"papa" [label="papa", color="steelblue"];
+	"adoptiv-mama" [label="adoptiv-mama", color="indianred"];
+	"adoptiv-papa" [label="adoptiv-papa", color="steelblue"];
+	"union-1" [shape=point, width=0.08, label=""];
+	"mama" -> "union-1";
+	"papa" -> "union-1";
+	"union-1" -> "gauss";
+	"union-2" [shape=point, width=0.08, label=""];
+	"adoptiv-mama" -> "union-2";
+	"adoptiv-papa" -> "union-2";
+	"union-2" -> "gauss" [style=dashed, label="adoptive"];
+}
+`, buffer.String())
+}
+
+func TestDotTreeGraph(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "relationship.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	g, err := NewDotTreeGraph(person, RenderTreeOptions{
+		MaxParentGenerations: 1,
+		IgnoreIDs:            []string{"stiefsohn"},
+	})
+	assert.Nil(t, err)
+	var buffer bytes.Buffer
+	err = g.WriteDot(&buffer)
+	assert.Nil(t, err)
+	dot := buffer.String()
+
+	assert.Contains(t, dot, `"gauss" [label="gauss", color="steelblue", penwidth=2.5];`)
+	for _, id := range []string{"vater", "mutter", "bruder", "frau", "sohn", "enkelin"} {
+		assert.Contains(t, dot, `"`+id+`" [`, id)
+	}
+	for _, id := range []string{"grossvater", "stiefsohn", "fremd", "frueherer-mann"} {
+		assert.NotContains(t, dot, `"`+id+`"`, id)
+	}
+	// gauss and bruder share the union of their parents, frau and gauss the one of sohn
+	assert.Contains(t, dot, `"mutter" -> "union-1";
+	"vater" -> "union-1";
+	"union-1" -> "gauss";
+	"union-1" -> "bruder";`)
+	assert.Contains(t, dot, `"frau" -> "union-2";
+	"gauss" -> "union-2";
+	"union-2" -> "sohn";`)
+}
+
+func TestDotLabel(t *testing.T) {
+	tests := []struct {
+		Person   FlatPerson
+		Expected string
+	}{
+		{
+			Person:   FlatPerson{ID: "gauss"},
+			Expected: "gauss",
+		},
+		{
+			Person: FlatPerson{
+				Name:  Name{First: []string{"Johann", "Carl", "Friedrich"}, Last: "Gauss"},
+				Birth: DatePlace{Date: "1777-04-30", Place: "Braunschweig"},
+				Death: DatePlace{Date: "um 1855"},
+			},
+			Expected: "Johann Gauss\n1777–1855",
+		},
+		{
+			Person: FlatPerson{
+				Name:  Name{First: []string{"Carl"}},
+				Death: DatePlace{Date: "1855"},
+			},
+			Expected: "Carl\n† 1855",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, dotLabel(&test.Person))
+	}
+	assert.Equal(t, `"a \"b\"\\c\nd"`, dotQuote("a \"b\"\\c\nd"))
+}
@@ -3,31 +3,37 @@ package generations
 import (
 	"time"
 
-	age "github.com/bearbin/go-age"
 	"github.com/jojomi/strtpl"
 )
 
 type FlatPerson struct {
-	Dummy         bool               `yaml:"-"`
-	ID            string             `yaml:"id,omitempty"`
-	UUID          string             `yaml:"uuid,omitempty"`
-	ChildNumber   int                `yaml:"child_number,omitempty"`
-	Name          Name               `yaml:"name,omitempty"`
-	Gender        string             `yaml:"gender,omitempty"`
-	Birth         DatePlace          `yaml:"birth,omitempty"`
-	Baptism       DatePlace          `yaml:"baptism,omitempty"`
-	Death         DatePlace          `yaml:"death,omitempty"`
-	Burial        DatePlace          `yaml:"burial,omitempty"`
-	Mom           string             `yaml:"mom,omitempty"`
-	Dad           string             `yaml:"dad,omitempty"`
-	Partners      []FlatRelationship `yaml:"partners,omitempty"`
-	Attributes    []string           `yaml:"attributes,omitempty"`
-	ImageFilename string             `yaml:"image,omitempty"`
-	Floruit       string             `yaml:"floruit,omitempty"`
-	Jobs          string             `yaml:"jobs,omitempty"`
-	Comment       string             `yaml:"comment,omitempty"`
+	Dummy             bool               `yaml:"-"`
+	ID                string             `yaml:"id,omitempty"`
+	UUID              string             `yaml:"uuid,omitempty"`
+	ChildNumber       int                `yaml:"child_number,omitempty"`
+	Name              Name               `yaml:"name,omitempty"`
+	Gender            string             `yaml:"gender,omitempty"`
+	Birth             DatePlace          `yaml:"birth,omitempty"`
+	Baptism           DatePlace          `yaml:"baptism,omitempty"`
+	Death             DatePlace          `yaml:"death,omitempty"`
+	Burial            DatePlace          `yaml:"burial,omitempty"`
+	Mom               string             `yaml:"mom,omitempty"`
+	Dad               string             `yaml:"dad,omitempty"`
+	Parents           []ParentLink       `yaml:"parents,omitempty"`
+	Partners          []FlatRelationship `yaml:"partners,omitempty"`
+	Attributes        []string           `yaml:"attributes,omitempty"`
+	ImageFilename     string             `yaml:"image,omitempty"`
+	Floruit           string             `yaml:"floruit,omitempty"`
+	Jobs              Jobs               `yaml:"jobs,omitempty"`
+	Residences        Residences         `yaml:"residences,omitempty"`
+	BiographyElements []BiographyElement `yaml:"biography,omitempty"`
+	Comment           string             `yaml:"comment,omitempty"`
+	Sources           []Reference        `yaml:"sources,omitempty"`
+	// Private persons have their data hidden by privacy policies regardless of their age
+	Private bool `yaml:"private,omitempty"`
 
 	Database *MemoryDatabase `yaml:"-"`
+	position *personPosition
 }
 
 func NewDummyFlatPerson() *FlatPerson {
@@ -39,6 +45,20 @@ func NewDummyFlatPerson() *FlatPerson {
 
 func (d *FlatPerson) SetID(id string) {
 	d.ID = id
+	d.invalidateIndex()
+}
+
+// invalidateIndex makes sure changes to IDs and parent links are reflected by database lookups
+func (d *FlatPerson) invalidateIndex() {
+	if d.Database != nil {
+		d.Database.invalidateIndex()
+	}
+}
+
+// GetPosition returns the location of a field (e.g. "mom" or "partners[1]") in the database file, the location of the
+// person if the field is not found
+func (d *FlatPerson) GetPosition(field string) Position {
+	return d.position.get(field)
 }
 
 func (d *FlatPerson) GetID() string {
@@ -49,6 +69,14 @@ func (d *FlatPerson) GetUUID() string {
 	return d.UUID
 }
 
+// GetBestID returns the ID if set, the UUID otherwise
+func (d *FlatPerson) GetBestID() string {
+	if d.ID != "" {
+		return d.ID
+	}
+	return d.UUID
+}
+
 func (d *FlatPerson) MatchesIDUUID(idUUIDSearches ...string) bool {
 	for _, search := range idUUIDSearches {
 		if search == "" {
@@ -93,8 +121,8 @@ func (d *FlatPerson) GetChildNumber() int {
 	return d.ChildNumber
 }
 
-func (d *FlatPerson) GetRelationships() []Relationship {
-	result := make([]Relationship, len(d.Partners))
+func (d *FlatPerson) GetRelationships() []PartnerRelationship {
+	result := make([]PartnerRelationship, len(d.Partners))
 	for i, item := range d.Partners {
 		item.Person = *d
 		result[i] = item
@@ -114,71 +142,111 @@ func (d *FlatPerson) GetDeath() DatePlace {
 	return d.Death
 }
 
-func (d *FlatPerson) GetAge(now time.Time) int {
-	birth := d.GetBirth()
+func (d *FlatPerson) GetAge(now time.Time) AgeRange {
+	return AgeAt(d.GetBirth().Date, now)
+}
 
-	if now.IsZero() {
-		return -1
-	}
+func (d *FlatPerson) GetDeathAge() AgeRange {
+	return AgeBetween(d.GetBirth().Date, d.GetDeath().Date)
+}
 
-	if birth.Empty() || len(birth.Date) != 10 {
-		return -1
-	}
-	birthTime, err := time.Parse("2006-01-02", birth.Date)
-	if err != nil {
-		return -1
-	}
+func (d *FlatPerson) GetBurial() DatePlace {
+	return d.Burial
+}
+
+func (d *FlatPerson) GetMom() (Person, error) {
+	return d.GetMomByType(ParentLinkTypeBiological)
+}
 
-	return age.AgeAt(birthTime, now)
+func (d *FlatPerson) GetDad() (Person, error) {
+	return d.GetDadByType(ParentLinkTypeBiological)
+}
+
+func (d *FlatPerson) GetMomByType(types ...ParentLinkType) (Person, error) {
+	return d.getParentByRole(ParentRoleMom, types)
 }
 
-func (d *FlatPerson) GetDeathAge() int {
-	birth := d.GetBirth()
-	death := d.GetDeath()
+func (d *FlatPerson) GetDadByType(types ...ParentLinkType) (Person, error) {
+	return d.getParentByRole(ParentRoleDad, types)
+}
+
+func (d *FlatPerson) getParentByRole(role ParentRole, types []ParentLinkType) (Person, error) {
+	for _, link := range d.GetParentLinks() {
+		if !link.GetType().IsAnyOf(types...) || (link.Role != 0 && link.Role != role) {
+			continue
+		}
+		parent, err := d.Database.GetByID(link.ID)
+		if err != nil {
+			return NewDummyFlatPerson(), err
+		}
+		if link.Role == 0 && !roleMatchesGender(role, parent.GetGender()) {
+			continue
+		}
+		return parent, nil
+	}
+	return NewDummyFlatPerson(), nil
+}
 
-	if birth.Empty() || len(birth.Date) != 10 {
-		return -1
+// GetParentLinks returns all links to parental figures, starting with the biological ones given as mom and dad
+func (d *FlatPerson) GetParentLinks() []ParentLink {
+	result := make([]ParentLink, 0, len(d.Parents)+2)
+	if d.Mom != "" {
+		result = append(result, ParentLink{ID: d.Mom, Type: ParentLinkTypeBiological, Role: ParentRoleMom})
 	}
-	birthTime, err := time.Parse("2006-01-02", birth.Date)
-	if err != nil {
-		return -1
+	if d.Dad != "" {
+		result = append(result, ParentLink{ID: d.Dad, Type: ParentLinkTypeBiological, Role: ParentRoleDad})
 	}
+	for _, link := range d.Parents {
+		link.Type = link.GetType()
+		result = append(result, link)
+	}
+	return result
+}
 
-	if death.Empty() || len(death.Date) != 10 {
-		return -1
+// GetParentLinkType returns the type of the link to the given parent, false if it is none of the parents
+func (d *FlatPerson) GetParentLinkType(parent Person) (ParentLinkType, bool) {
+	if parent == nil || parent.IsDummy() {
+		return 0, false
 	}
-	deathTime, err := time.Parse("2006-01-02", death.Date)
-	if err != nil {
-		return -1
+	for _, link := range d.GetParentLinks() {
+		if parent.MatchesIDUUID(link.ID) {
+			return link.Type, true
+		}
 	}
+	return 0, false
+}
 
-	return age.AgeAt(birthTime, deathTime)
+func (d *FlatPerson) GetParentsByType(types ...ParentLinkType) (PersonList, error) {
+	result := NewPersonList(nil)
+	for _, link := range d.GetParentLinks() {
+		if !link.Type.IsAnyOf(types...) {
+			continue
+		}
+		parent, err := d.Database.GetByID(link.ID)
+		if err != nil {
+			return NewPersonList(nil), err
+		}
+		result.AddPerson(parent)
+	}
+	return *result.RemoveDuplicates(), nil
 }
 
-func (d *FlatPerson) GetBurial() DatePlace {
-	return d.Burial
+func (d *FlatPerson) GetRawMom() string {
+	return d.Mom
 }
 
-func (d *FlatPerson) GetMom() (Person, error) {
-	if d.Mom == "" {
-		return NewDummyFlatPerson(), nil
-	}
-	mom, err := d.Database.GetByID(d.Mom)
-	if err != nil {
-		return NewDummyFlatPerson(), err
-	}
-	return mom, nil
+func (d *FlatPerson) SetRawMom(mom string) {
+	d.Mom = mom
+	d.invalidateIndex()
 }
 
-func (d *FlatPerson) GetDad() (Person, error) {
-	if d.Dad == "" {
-		return NewDummyFlatPerson(), nil
-	}
-	dad, err := d.Database.GetByID(d.Dad)
-	if err != nil {
-		return NewDummyFlatPerson(), err
-	}
-	return dad, nil
+func (d *FlatPerson) GetRawDad() string {
+	return d.Dad
+}
+
+func (d *FlatPerson) SetRawDad(dad string) {
+	d.Dad = dad
+	d.invalidateIndex()
 }
 
 func (d *FlatPerson) GetPartners() (PersonList, error) {
@@ -186,10 +254,8 @@ func (d *FlatPerson) GetPartners() (PersonList, error) {
 
 	// find explicit partners
 	for _, partner := range d.Partners {
-		for _, person := range d.Database.Persons {
-			if person.MatchesIDUUID(partner.PartnerID) {
-				result.AddPerson(person)
-			}
+		if person := d.Database.getIndex().getByIDUUID(partner.PartnerID); person != nil {
+			result.AddPerson(person)
 		}
 	}
 
@@ -208,6 +274,10 @@ func (d *FlatPerson) GetPartners() (PersonList, error) {
 }
 
 func (d *FlatPerson) GetChildrenParents() (PersonList, error) {
+	return d.GetChildrenParentsByType(ParentLinkTypeBiological)
+}
+
+func (d *FlatPerson) GetChildrenParentsByType(types ...ParentLinkType) (PersonList, error) {
 	result := NewPersonList(nil)
 	parentsSeen := make(map[string]struct{}, 0)
 	var (
@@ -217,19 +287,21 @@ func (d *FlatPerson) GetChildrenParents() (PersonList, error) {
 		ok        bool
 		err       error
 	)
-	children, err := d.GetChildren()
+	children, err := d.GetChildrenByType(types...)
 	if err != nil {
 		return result, err
 	}
 	for _, child := range children.GetPersons() {
-		mom, err = child.GetMom()
+		linkType, _ := child.GetParentLinkType(d)
+		mom, err = child.GetMomByType(linkType)
 		if err != nil {
 			return result, err
 		}
-		dad, err = child.GetDad()
+		dad, err = child.GetDadByType(linkType)
 		if err != nil {
 			return result, err
 		}
+		candidate = nil
 
 		// if this FlatPerson is mom or dad the other one is a candidate parent to be returned
 		if mom != nil && mom.MatchesIDUUID(d.GetUUID(), d.GetID()) {
@@ -255,29 +327,21 @@ func (d *FlatPerson) GetChildrenParents() (PersonList, error) {
 }
 
 func (d *FlatPerson) GetChildren() (PersonList, error) {
+	return d.GetChildrenByType(ParentLinkTypeBiological)
+}
+
+func (d *FlatPerson) GetChildrenByType(types ...ParentLinkType) (PersonList, error) {
 	result := NewPersonList(nil)
 
-	var (
-		mom Person
-		dad Person
-		err error
-	)
-	for _, child := range d.Database.Persons {
-		mom, err = child.GetMom()
-		if err != nil {
-			return NewPersonList(nil), err
-		}
-		if mom != nil && mom.MatchesIDUUID(d.GetUUID(), d.GetID()) {
-			result.AddPerson(child)
-			continue
-		}
-		dad, err = child.GetDad()
-		if err != nil {
-			return NewPersonList(nil), err
-		}
-		if dad != nil && dad.MatchesIDUUID(d.GetUUID(), d.GetID()) {
-			result.AddPerson(child)
-			continue
+	for _, child := range d.Database.getIndex().getChildCandidates(d) {
+		for _, link := range child.GetParentLinks() {
+			if !link.Type.IsAnyOf(types...) {
+				continue
+			}
+			if d.MatchesIDUUID(link.ID) {
+				result.AddPerson(child)
+				break
+			}
 		}
 	}
 
@@ -285,21 +349,30 @@ func (d *FlatPerson) GetChildren() (PersonList, error) {
 }
 
 func (d *FlatPerson) GetChildrenWith(partner Person) (PersonList, error) {
+	return d.GetChildrenWithByType(partner, ParentLinkTypeBiological)
+}
+
+func (d *FlatPerson) GetChildrenWithByType(partner Person, types ...ParentLinkType) (PersonList, error) {
 	result := NewPersonList(nil)
-	children, err := d.GetChildren()
+	children, err := d.GetChildrenByType(types...)
 	if err != nil {
 		return result, err
 	}
 	for _, child := range children.GetPersons() {
-		mom, err := child.GetMom()
+		// the other parent is the one linked to the child in the same way
+		linkType, _ := child.GetParentLinkType(d)
+		mom, err := child.GetMomByType(linkType)
 		if err != nil {
 			return NewPersonList(nil), err
 		}
-		dad, err := child.GetDad()
+		dad, err := child.GetDadByType(linkType)
 		if err != nil {
 			return NewPersonList(nil), err
 		}
 		otherParent := getOtherPerson(mom, dad, d)
+		if otherParent == nil {
+			continue
+		}
 		if partner.IsDummy() {
 			if otherParent.IsDummy() {
 				result.AddPerson(child)
@@ -321,7 +394,7 @@ func (d *FlatPerson) GetChildrenWith(partner Person) (PersonList, error) {
 		if sortPersons[i].GetChildNumber() != sortPersons[j].GetChildNumber() {
 			return sortPersons[i].GetChildNumber() < sortPersons[j].GetChildNumber()
 		}
-		return sortPersons[i].GetBirth().Date < sortPersons[j].GetBirth().Date
+		return sortPersons[i].GetBirth().Date.Before(sortPersons[j].GetBirth().Date)
 	})
 
 	return result, nil
@@ -348,6 +421,11 @@ func (d *FlatPerson) GetAttributes() []string {
 }
 
 func (d *FlatPerson) AddAttribute(attr string) {
+	for _, a := range d.Attributes {
+		if a == attr {
+			return
+		}
+	}
 	d.Attributes = append(d.Attributes, attr)
 }
 
@@ -359,14 +437,36 @@ func (d *FlatPerson) SetImageFilename(filename string) {
 	d.ImageFilename = filename
 }
 
-func (d *FlatPerson) GetJobs() string {
+func (d *FlatPerson) GetJobs() Jobs {
 	return d.Jobs
 }
 
-func (d *FlatPerson) SetJobs(jobs string) {
+func (d *FlatPerson) SetJobs(jobs Jobs) {
 	d.Jobs = jobs
 }
 
+func (d *FlatPerson) GetResidences() Residences {
+	return d.Residences
+}
+
+func (d *FlatPerson) GetBiographyElements() []BiographyElement {
+	return d.BiographyElements
+}
+
+func (d *FlatPerson) GetBiographyElementsByType(t BiographyElementType) []BiographyElement {
+	result := make([]BiographyElement, 0)
+	for _, b := range d.BiographyElements {
+		if b.Type == t {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+func (d *FlatPerson) IsPrivate() bool {
+	return d.Private
+}
+
 func (d *FlatPerson) GetFloruit() string {
 	return d.Floruit
 }
@@ -379,6 +479,33 @@ func (d *FlatPerson) SetComment(comment string) {
 	d.Comment = comment
 }
 
+func (d *FlatPerson) GetSources() []Reference {
+	return d.Sources
+}
+
+// GetAllReferences returns the references for the person as well as those of all of its facts
+func (d *FlatPerson) GetAllReferences() []Reference {
+	result := append([]Reference{}, d.Sources...)
+	for _, fact := range []DatePlace{d.Birth, d.Baptism, d.Death, d.Burial} {
+		result = append(result, fact.Sources...)
+	}
+	for _, r := range d.Partners {
+		for _, fact := range []DatePlace{r.Engagement, r.Marriage, r.Divorce} {
+			result = append(result, fact.Sources...)
+		}
+	}
+	for _, j := range d.Jobs {
+		result = append(result, j.Sources...)
+	}
+	for _, r := range d.Residences {
+		result = append(result, r.Sources...)
+	}
+	for _, b := range d.BiographyElements {
+		result = append(result, b.Sources...)
+	}
+	return result
+}
+
 func (d *FlatPerson) IsDummy() bool {
 	return d == nil || d.Dummy
 }
@@ -47,7 +47,7 @@ func TestGetChildren(t *testing.T) {
 		children, err := person.GetChildren()
 		assert.Nil(t, err, test.Name)
 		assert.Len(t, children.GetPersons(), len(test.ExpectedIDs), test.Name)
-		childrenIDs := getPersonSliceIDs(children)
+		childrenIDs := getPersonSliceIDs(children.GetPersons())
 		assert.Equal(t, test.ExpectedIDs, childrenIDs, test.Name)
 	}
 }
@@ -106,7 +106,7 @@ func TestGetChildrenParents(t *testing.T) {
 		parents, err := person.GetChildrenParents()
 		assert.Nil(t, err, test.Name)
 		assert.Len(t, parents.GetPersons(), len(test.ExpectedIDs), test.Name)
-		parentIDs := getPersonSliceIDs(parents)
+		parentIDs := getPersonSliceIDs(parents.GetPersons())
 		assert.Equal(t, test.ExpectedIDs, parentIDs, test.Name)
 	}
 }
@@ -151,7 +151,73 @@ func TestGetPartners(t *testing.T) {
 		assert.Nil(t, err, test.Name+": database select by ID")
 		partners, err := person.GetPartners()
 		assert.Nil(t, err, test.Name+": GetPartners no error")
-		partnerIDs := getPersonSliceIDs(partners)
+		partnerIDs := getPersonSliceIDs(partners.GetPersons())
 		assert.Equal(t, test.ExpectedIDs, partnerIDs, test.Name+": partner IDs")
 	}
 }
+
+func TestGetParentsByType(t *testing.T) {
+	tests := []struct {
+		Name        string
+		ID          string
+		Types       []ParentLinkType
+		ExpectedIDs []string
+	}{
+		{
+			Name:        "biological",
+			ID:          "gauss",
+			Types:       []ParentLinkType{ParentLinkTypeBiological},
+			ExpectedIDs: []string{"mama", "papa"},
+		},
+		{
+			Name:        "adoptive",
+			ID:          "gauss",
+			Types:       []ParentLinkType{ParentLinkTypeAdoptive},
+			ExpectedIDs: []string{"adoptiv-mama", "adoptiv-papa"},
+		},
+		{
+			Name:        "all",
+			ID:          "gauss",
+			Types:       []ParentLinkType{ParentLinkTypeBiological, ParentLinkTypeAdoptive},
+			ExpectedIDs: []string{"mama", "papa", "adoptiv-mama", "adoptiv-papa"},
+		},
+		{
+			Name:        "none",
+			ID:          "gauss",
+			Types:       []ParentLinkType{ParentLinkTypeFoster},
+			ExpectedIDs: []string{},
+		},
+	}
+
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "adoption.yml"))
+	assert.Nil(t, err)
+	for _, test := range tests {
+		person, err := database.GetByID(test.ID)
+		assert.Nil(t, err, test.Name)
+		parents, err := person.GetParentsByType(test.Types...)
+		assert.Nil(t, err, test.Name)
+		assert.Equal(t, test.ExpectedIDs, getPersonSliceIDs(parents.GetPersons()), test.Name)
+	}
+
+	kind, _ := database.GetByID("gauss")
+	mom, err := kind.GetMomByType(ParentLinkTypeAdoptive)
+	assert.Nil(t, err)
+	assert.Equal(t, "adoptiv-mama", mom.GetID())
+	dad, err := kind.GetDad()
+	assert.Nil(t, err)
+	assert.Equal(t, "papa", dad.GetID())
+
+	linkType, ok := kind.GetParentLinkType(mom)
+	assert.True(t, ok)
+	assert.Equal(t, ParentLinkTypeAdoptive, linkType)
+	assert.Equal(t, "link-adoptive", linkType.GetStyle())
+	assert.Equal(t, "", ParentLinkTypeBiological.GetStyle())
+
+	children, err := mom.GetChildren()
+	assert.Nil(t, err)
+	assert.Len(t, children.GetPersons(), 0)
+	children, err = mom.GetChildrenByType(ParentLinkTypeAdoptive)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"gauss"}, getPersonSliceIDs(children.GetPersons()))
+}
@@ -0,0 +1,400 @@
+package generations
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// gedcomNode is a single line of a GEDCOM file including its subordinate lines
+type gedcomNode struct {
+	Line     int
+	Level    int
+	XRef     string
+	Tag      string
+	Value    string
+	Pointer  bool
+	Children []*gedcomNode
+}
+
+// GedcomIssue describes a part of a GEDCOM file that could not be imported (completely)
+type GedcomIssue struct {
+	Line    int
+	Path    string
+	Message string
+}
+
+func (i GedcomIssue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Path, i.Message)
+}
+
+// parseGedcom reads the lines of a GEDCOM file into a list of records, merging CONT and CONC lines into their parent
+func parseGedcom(r io.Reader) ([]*gedcomNode, error) {
+	var (
+		records []*gedcomNode
+		stack   []*gedcomNode
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		// spaces at the end are part of the value, CONC lines may split a value at a space
+		line := strings.TrimLeft(strings.TrimRight(scanner.Text(), "\r\n"), " \t")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		node, err := parseGedcomLine(line)
+		if err != nil {
+			return nil, errors.Annotatef(err, "line %d", lineNumber)
+		}
+		node.Line = lineNumber
+		if node.Level > len(stack) {
+			return nil, errors.Errorf("line %d: level %d without parent", lineNumber, node.Level)
+		}
+		stack = stack[:node.Level]
+
+		if node.Level == 0 {
+			records = append(records, node)
+			stack = append(stack, node)
+			continue
+		}
+		parent := stack[node.Level-1]
+		switch node.Tag {
+		case "CONC":
+			parent.Value += node.Value
+			continue
+		case "CONT":
+			parent.Value += "\n" + node.Value
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Annotate(err, "error reading gedcom data")
+	}
+	return records, nil
+}
+
+func parseGedcomLine(line string) (*gedcomNode, error) {
+	parts := strings.SplitN(line, " ", 2)
+	level, err := strconv.Atoi(parts[0])
+	if err != nil || level < 0 {
+		return nil, errors.Errorf("invalid level in %q", line)
+	}
+	if len(parts) < 2 {
+		return nil, errors.Errorf("missing tag in %q", line)
+	}
+	node := &gedcomNode{Level: level}
+	rest := parts[1]
+	if strings.HasPrefix(rest, "@") {
+		parts = strings.SplitN(rest, " ", 2)
+		node.XRef = parts[0]
+		if len(parts) < 2 {
+			return nil, errors.Errorf("missing tag in %q", line)
+		}
+		rest = parts[1]
+	}
+	parts = strings.SplitN(rest, " ", 2)
+	node.Tag = strings.ToUpper(parts[0])
+	if len(parts) > 1 {
+		node.Value = parts[1]
+	}
+	// pointers like @I1@ are kept, in all other values @@ is an escaped @
+	node.Pointer = len(node.Value) > 2 && strings.HasPrefix(node.Value, "@") && !strings.HasPrefix(node.Value, "@@") &&
+		strings.HasSuffix(node.Value, "@")
+	if !node.Pointer {
+		node.Value = strings.Replace(node.Value, "@@", "@", -1)
+	}
+	return node, nil
+}
+
+// child returns the first subordinate line with the given tag, nil if there is none
+func (n *gedcomNode) child(tag string) *gedcomNode {
+	for _, c := range n.Children {
+		if c.Tag == tag {
+			return c
+		}
+	}
+	return nil
+}
+
+// childValue returns the value of the first subordinate line with the given tag
+func (n *gedcomNode) childValue(tag string) string {
+	if c := n.child(tag); c != nil {
+		return c.Value
+	}
+	return ""
+}
+
+// isPointer returns true iff the value of the line references another record
+func (n *gedcomNode) isPointer() bool {
+	return n.Pointer
+}
+
+var gedcomMonths = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var gedcomMonthNames = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+// parseGedcomDate converts a GEDCOM date value like "ABT 12 MAR 1850" to a Date. The value is returned unchanged
+// and ok is false if it can't be converted.
+func parseGedcomDate(value string) (date Date, ok bool) {
+	if phrase, ok := gedcomDatePhrase(value); ok {
+		_, err := Date(phrase).Parse()
+		return Date(phrase), err == nil
+	}
+	fields := strings.Fields(strings.ToUpper(value))
+	if len(fields) == 0 {
+		return "", true
+	}
+
+	var result string
+	switch fields[0] {
+	case "ABT", "CAL":
+		result, ok = gedcomDateWithPrefix("about", fields[1:])
+	case "EST", "INT":
+		result, ok = gedcomDateWithPrefix("est", fields[1:])
+	case "BEF", "TO":
+		result, ok = gedcomDateWithPrefix("before", fields[1:])
+	case "AFT", "FROM":
+		if len(fields) > 1 && fields[0] == "FROM" {
+			if i := indexOf(fields, "TO"); i > 0 {
+				result, ok = gedcomDateRange(fields[1:i], fields[i+1:])
+				break
+			}
+		}
+		result, ok = gedcomDateWithPrefix("after", fields[1:])
+	case "BET":
+		if i := indexOf(fields, "AND"); i > 0 {
+			result, ok = gedcomDateRange(fields[1:i], fields[i+1:])
+		}
+	default:
+		result, ok = gedcomSimpleDate(fields)
+	}
+	if ok {
+		if _, err := Date(result).Parse(); err == nil {
+			return Date(result), true
+		}
+	}
+
+	// maybe it is in a format understood by Date already
+	if _, err := Date(value).Parse(); err == nil {
+		return Date(value), true
+	}
+	return Date(value), false
+}
+
+func gedcomDateWithPrefix(prefix string, fields []string) (string, bool) {
+	date, ok := gedcomSimpleDate(fields)
+	if !ok {
+		return "", false
+	}
+	return prefix + " " + date, true
+}
+
+func gedcomDateRange(from, to []string) (string, bool) {
+	fromDate, ok := gedcomSimpleDate(from)
+	if !ok {
+		return "", false
+	}
+	toDate, ok := gedcomSimpleDate(to)
+	if !ok {
+		return "", false
+	}
+	return "between " + fromDate + " and " + toDate, true
+}
+
+// gedcomSimpleDate converts a date like "12 MAR 1850", "MAR 1850" or "1850" to ISO format
+func gedcomSimpleDate(fields []string) (string, bool) {
+	var (
+		day, month int
+		err        error
+	)
+	switch len(fields) {
+	case 1:
+	case 2:
+		if month = gedcomMonths[fields[0]]; month == 0 {
+			return "", false
+		}
+	case 3:
+		if day, err = strconv.Atoi(fields[0]); err != nil {
+			return "", false
+		}
+		if month = gedcomMonths[fields[1]]; month == 0 {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	year, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || year <= 0 {
+		return "", false
+	}
+	result := fmt.Sprintf("%04d", year)
+	if month > 0 {
+		result += fmt.Sprintf("-%02d", month)
+	}
+	if day > 0 {
+		result += fmt.Sprintf("-%02d", day)
+	}
+	return result, true
+}
+
+// parseGedcomPeriod converts a GEDCOM date period like "FROM 1850 TO 1860" to a Period
+func parseGedcomPeriod(value string) (Period, bool) {
+	// periods with fuzzy dates are written as date phrase
+	if phrase, ok := gedcomDatePhrase(value); ok {
+		var period Period
+		upper := strings.ToUpper(phrase)
+		switch {
+		case strings.HasPrefix(upper, "FROM "):
+			parts := strings.SplitN(phrase[len("FROM "):], " TO ", 2)
+			period.From = Date(parts[0])
+			if len(parts) > 1 {
+				period.To = Date(parts[1])
+			}
+		case strings.HasPrefix(upper, "TO "):
+			period.To = Date(phrase[len("TO "):])
+		default:
+			date, ok := parseGedcomDate(value)
+			return Period{From: date, To: date}, ok
+		}
+		_, errFrom := period.From.Parse()
+		_, errTo := period.To.Parse()
+		return period, errFrom == nil && errTo == nil
+	}
+
+	fields := strings.Fields(strings.ToUpper(value))
+	if len(fields) == 0 {
+		return Period{}, true
+	}
+	switch fields[0] {
+	case "FROM":
+		var period Period
+		from, to := fields[1:], []string(nil)
+		if i := indexOf(fields, "TO"); i > 0 {
+			from, to = fields[1:i], fields[i+1:]
+		}
+		date, ok := gedcomSimpleDate(from)
+		if !ok {
+			return Period{}, false
+		}
+		period.From = Date(date)
+		if to != nil {
+			date, ok = gedcomSimpleDate(to)
+			if !ok {
+				return Period{}, false
+			}
+			period.To = Date(date)
+		}
+		return period, true
+	case "TO":
+		date, ok := gedcomSimpleDate(fields[1:])
+		return Period{To: Date(date)}, ok
+	}
+	date, ok := parseGedcomDate(value)
+	return Period{From: date, To: date}, ok
+}
+
+// gedcomDatePhrase returns the text of a date phrase like "(im Winter)"
+func gedcomDatePhrase(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+		return "", false
+	}
+	return value[1 : len(value)-1], true
+}
+
+// formatGedcomDate converts a Date to GEDCOM format, dates that can't be represented are written as date phrase
+func formatGedcomDate(d Date) string {
+	if d.Empty() {
+		return ""
+	}
+	fuzzy, err := d.Parse()
+	if err != nil || fuzzy.IsUnknown() {
+		return "(" + string(d) + ")"
+	}
+	from := formatGedcomPartialDate(fuzzy.From)
+	switch fuzzy.Qualifier {
+	case DateQualifierAbout:
+		return "ABT " + from
+	case DateQualifierEstimated:
+		return "EST " + from
+	case DateQualifierBefore:
+		return "BEF " + from
+	case DateQualifierAfter:
+		return "AFT " + from
+	case DateQualifierBetween:
+		return "BET " + from + " AND " + formatGedcomPartialDate(fuzzy.To)
+	}
+	return from
+}
+
+func formatGedcomPartialDate(p PartialDate) string {
+	result := strconv.Itoa(p.Year)
+	if p.Month > 0 {
+		result = gedcomMonthNames[p.Month] + " " + result
+	}
+	if p.Day > 0 {
+		result = strconv.Itoa(p.Day) + " " + result
+	}
+	return result
+}
+
+// formatGedcomPeriod converts a Period to GEDCOM format. GEDCOM periods can't hold fuzzy dates, so these are written
+// as date phrase.
+func formatGedcomPeriod(p Period) string {
+	if p.From == p.To {
+		return formatGedcomDate(p.From)
+	}
+	exact := true
+	for _, d := range []Date{p.From, p.To} {
+		if d.Empty() {
+			continue
+		}
+		if fuzzy, err := d.Parse(); err != nil || fuzzy.Qualifier != DateQualifierExact {
+			exact = false
+		}
+	}
+
+	var parts []string
+	if !p.From.Empty() {
+		from := string(p.From)
+		if exact {
+			from = formatGedcomDate(p.From)
+		}
+		parts = append(parts, "FROM "+from)
+	}
+	if !p.To.Empty() {
+		to := string(p.To)
+		if exact {
+			to = formatGedcomDate(p.To)
+		}
+		parts = append(parts, "TO "+to)
+	}
+	if exact {
+		return strings.Join(parts, " ")
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func indexOf(list []string, search string) int {
+	for i, s := range list {
+		if s == search {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,579 @@
+package generations
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+// gedcomMaxValueLength is the number of bytes after which values are continued by CONC lines
+const gedcomMaxValueLength = 200
+
+type gedcomWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a GEDCOM line, splitting the value into CONT and CONC lines where needed
+func (g *gedcomWriter) line(level int, xref, tag, value string) {
+	for i, text := range strings.Split(value, "\n") {
+		if i > 0 {
+			tag = "CONT"
+			xref = ""
+		}
+		chunks := splitGedcomValue(text)
+		for j, chunk := range chunks {
+			if j > 0 {
+				tag = "CONC"
+				xref = ""
+			}
+			lineLevel := level
+			if tag == "CONT" || tag == "CONC" {
+				lineLevel = level + 1
+			}
+			g.write(lineLevel, xref, tag, strings.Replace(chunk, "@", "@@", -1))
+		}
+	}
+}
+
+// pointer writes a GEDCOM line whose value references another record, it is not escaped
+func (g *gedcomWriter) pointer(level int, tag, xref string) {
+	g.write(level, "", tag, xref)
+}
+
+func (g *gedcomWriter) write(level int, xref, tag, value string) {
+	if g.err != nil {
+		return
+	}
+	line := strconv.Itoa(level)
+	if xref != "" {
+		line += " " + xref
+	}
+	line += " " + tag
+	if value != "" {
+		line += " " + value
+	}
+	_, g.err = g.w.WriteString(line + "\n")
+}
+
+// splitGedcomValue splits a value without line breaks into chunks not longer than gedcomMaxValueLength. Chunks are
+// only split between runes and preferably not next to a space because some applications trim them.
+func splitGedcomValue(value string) []string {
+	result := []string{}
+	for len(value) > gedcomMaxValueLength {
+		cut := previousRuneStart(value, gedcomMaxValueLength+1)
+		for c := cut; c > 0; c = previousRuneStart(value, c) {
+			if value[c] != ' ' && value[c-1] != ' ' {
+				cut = c
+				break
+			}
+		}
+		result = append(result, value[:cut])
+		value = value[cut:]
+	}
+	return append(result, value)
+}
+
+// previousRuneStart returns the last index before i where a rune starts in value, 0 if there is none
+func previousRuneStart(value string, i int) int {
+	for i--; i > 0 && !utf8.RuneStart(value[i]); i-- {
+	}
+	return i
+}
+
+type gedcomFamily struct {
+	XRef         string
+	Husband      *FlatPerson
+	Wife         *FlatPerson
+	Relationship FlatRelationship
+	Children     []gedcomChild
+}
+
+type gedcomChild struct {
+	Person    *FlatPerson
+	DadType   ParentLinkType
+	MomType   ParentLinkType
+	DadPeriod Period
+	MomPeriod Period
+}
+
+type gedcomExporter struct {
+	database *MemoryDatabase
+	g        *gedcomWriter
+
+	persons       map[*FlatPerson]string
+	families      []*gedcomFamily
+	familiesByKey map[string]*gedcomFamily
+	// families per person as spouse and as child
+	spouseFamilies map[*FlatPerson][]*gedcomFamily
+	childFamilies  map[*FlatPerson][]*gedcomFamily
+	sources        map[string]string
+	repositories   []string
+}
+
+// WriteGedcomFile writes the database to a GEDCOM 5.5.1 file
+func (y *MemoryDatabase) WriteGedcomFile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return errors.Annotatef(err, "error writing gedcom file %s", filename)
+	}
+	err = y.WriteGedcom(file)
+	if err != nil {
+		file.Close()
+		return errors.Annotatef(err, "error writing gedcom file %s", filename)
+	}
+	return file.Close()
+}
+
+// WriteGedcom writes the database in GEDCOM 5.5.1 format using UTF-8. Families are built from the parents of every
+// person and from the partners given.
+func (y *MemoryDatabase) WriteGedcom(w io.Writer) error {
+	e := gedcomExporter{
+		database:       y,
+		g:              &gedcomWriter{w: bufio.NewWriter(w)},
+		persons:        make(map[*FlatPerson]string),
+		familiesByKey:  make(map[string]*gedcomFamily),
+		spouseFamilies: make(map[*FlatPerson][]*gedcomFamily),
+		childFamilies:  make(map[*FlatPerson][]*gedcomFamily),
+		sources:        make(map[string]string),
+	}
+	for _, p := range y.Persons {
+		if p == nil || p.IsDummy() {
+			continue
+		}
+		e.persons[p] = "@I" + strconv.Itoa(len(e.persons)+1) + "@"
+	}
+	for i, s := range y.Sources {
+		e.sources[s.ID] = "@S" + strconv.Itoa(i+1) + "@"
+	}
+	e.buildFamilies()
+
+	e.writeHeader()
+	for _, p := range y.Persons {
+		if _, ok := e.persons[p]; ok {
+			e.writePerson(p)
+		}
+	}
+	for _, f := range e.families {
+		e.writeFamily(f)
+	}
+	for _, s := range y.Sources {
+		e.writeSource(s)
+	}
+	for i, r := range e.repositories {
+		e.g.line(0, "@R"+strconv.Itoa(i+1)+"@", "REPO", "")
+		e.g.line(1, "", "NAME", r)
+	}
+	e.g.line(0, "@U1@", "SUBM", "")
+	e.g.line(1, "", "NAME", "generations")
+	e.g.line(0, "", "TRLR", "")
+
+	if e.g.err != nil {
+		return errors.Annotate(e.g.err, "error writing gedcom data")
+	}
+	return errors.Annotate(e.g.w.Flush(), "error writing gedcom data")
+}
+
+func (e *gedcomExporter) getPerson(ID string) *FlatPerson {
+	p := e.database.getIndex().getByIDUUID(ID)
+	if _, ok := e.persons[p]; !ok {
+		return nil
+	}
+	return p
+}
+
+// getFamily returns the family of the given spouses, creating it if needed
+func (e *gedcomExporter) getFamily(husband, wife *FlatPerson) *gedcomFamily {
+	key := e.persons[husband] + "+" + e.persons[wife]
+	if f, ok := e.familiesByKey[key]; ok {
+		return f
+	}
+	f := &gedcomFamily{
+		XRef:    "@F" + strconv.Itoa(len(e.families)+1) + "@",
+		Husband: husband,
+		Wife:    wife,
+	}
+	e.families = append(e.families, f)
+	e.familiesByKey[key] = f
+	for _, spouse := range []*FlatPerson{husband, wife} {
+		if spouse != nil {
+			e.spouseFamilies[spouse] = append(e.spouseFamilies[spouse], f)
+		}
+	}
+	return f
+}
+
+func (e *gedcomExporter) buildFamilies() {
+	for _, p := range e.database.Persons {
+		if _, ok := e.persons[p]; !ok {
+			continue
+		}
+		for _, r := range p.Partners {
+			partner := e.getPerson(r.PartnerID)
+			if partner == nil {
+				continue
+			}
+			husband, wife := p, partner
+			if p.GetGender() == GenderFemale || partner.GetGender() == GenderMale {
+				husband, wife = partner, p
+			}
+			f := e.getFamily(husband, wife)
+			if f.Relationship.PartnerID == "" {
+				f.Relationship = r
+				continue
+			}
+			// both partners list the relationship, data of the first entry wins
+			f.Relationship.Engagement = mergeDatePlace(f.Relationship.Engagement, r.Engagement)
+			f.Relationship.Marriage = mergeDatePlace(f.Relationship.Marriage, r.Marriage)
+			f.Relationship.Divorce = mergeDatePlace(f.Relationship.Divorce, r.Divorce)
+		}
+	}
+
+	// one family per parent link type of every child
+	for _, child := range e.database.Persons {
+		if _, ok := e.persons[child]; !ok {
+			continue
+		}
+		var (
+			types    []ParentLinkType
+			families = make(map[ParentLinkType]*gedcomChildFamily)
+		)
+		for _, link := range child.GetParentLinks() {
+			parent := e.getPerson(link.ID)
+			if parent == nil {
+				continue
+			}
+			cf, ok := families[link.Type]
+			if !ok {
+				cf = &gedcomChildFamily{}
+				families[link.Type] = cf
+				types = append(types, link.Type)
+			}
+			cf.add(link, parent)
+		}
+		for _, t := range types {
+			cf := families[t]
+			f := e.getFamily(cf.Dad, cf.Mom)
+			f.Children = append(f.Children, gedcomChild{
+				Person:    child,
+				DadType:   t,
+				MomType:   t,
+				DadPeriod: cf.DadPeriod,
+				MomPeriod: cf.MomPeriod,
+			})
+			e.childFamilies[child] = append(e.childFamilies[child], f)
+		}
+	}
+}
+
+// gedcomChildFamily collects the parents of a child linked by the same link type
+type gedcomChildFamily struct {
+	Dad, Mom             *FlatPerson
+	DadPeriod, MomPeriod Period
+}
+
+// add sets the parent of the link as dad or mom by its role, its gender or the place still free. Further parents with
+// the same role are left out.
+func (cf *gedcomChildFamily) add(link ParentLink, parent *FlatPerson) {
+	role := link.Role
+	if role == 0 {
+		switch {
+		case parent.GetGender() == GenderMale:
+			role = ParentRoleDad
+		case parent.GetGender() == GenderFemale:
+			role = ParentRoleMom
+		case cf.Dad == nil:
+			role = ParentRoleDad
+		default:
+			role = ParentRoleMom
+		}
+	}
+	switch {
+	case role == ParentRoleDad && cf.Dad == nil:
+		cf.Dad, cf.DadPeriod = parent, link.Period
+	case role == ParentRoleMom && cf.Mom == nil:
+		cf.Mom, cf.MomPeriod = parent, link.Period
+	}
+}
+
+// mergeDatePlace fills the empty fields of d from other and adds the references of other that d is missing
+func mergeDatePlace(d, other DatePlace) DatePlace {
+	if d.Date == "" {
+		d.Date = other.Date
+	}
+	if d.Place == "" {
+		d.Place = other.Place
+	}
+	sources := append([]Reference{}, d.Sources...)
+	for _, o := range other.Sources {
+		found := false
+		for _, r := range d.Sources {
+			if r == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sources = append(sources, o)
+		}
+	}
+	if len(sources) > 0 {
+		d.Sources = sources
+	}
+	return d
+}
+
+func (e *gedcomExporter) writeHeader() {
+	g := e.g
+	g.line(0, "", "HEAD", "")
+	g.line(1, "", "SOUR", "generations")
+	g.line(2, "", "NAME", "generations")
+	g.pointer(1, "SUBM", "@U1@")
+	g.line(1, "", "GEDC", "")
+	g.line(2, "", "VERS", "5.5.1")
+	g.line(2, "", "FORM", "LINEAGE-LINKED")
+	g.line(1, "", "CHAR", "UTF-8")
+}
+
+func (e *gedcomExporter) writePerson(p *FlatPerson) {
+	g := e.g
+	g.line(0, e.persons[p], "INDI", "")
+	e.writeName(p.Name)
+	switch p.GetGender() {
+	case GenderMale:
+		g.line(1, "", "SEX", "M")
+	case GenderFemale:
+		g.line(1, "", "SEX", "F")
+	default:
+		g.line(1, "", "SEX", "U")
+	}
+	e.writeDatePlace(1, "BIRT", p.Birth)
+	e.writeDatePlace(1, "CHR", p.Baptism)
+	e.writeDatePlace(1, "DEAT", p.Death)
+	e.writeDatePlace(1, "BURI", p.Burial)
+	for _, job := range p.Jobs {
+		g.line(1, "", "OCCU", job.Title)
+		e.writePeriodPlace(2, job.Period, job.Place, job.Sources)
+	}
+	for _, residence := range p.Residences {
+		g.line(1, "", "RESI", "")
+		e.writePeriodPlace(2, residence.Period, residence.Place, residence.Sources)
+	}
+	for _, element := range p.BiographyElements {
+		switch element.Type {
+		case BiographyElementTypeEducation:
+			g.line(1, "", "EDUC", element.Description)
+		case BiographyElementTypeMilitary:
+			g.line(1, "", "_MILT", element.Description)
+		case BiographyElementTypeEmigration:
+			g.line(1, "", "EMIG", element.Description)
+		default:
+			g.line(1, "", "EVEN", "")
+			g.line(2, "", "TYPE", element.Description)
+		}
+		e.writePeriodPlace(2, element.Period, element.Place, element.Sources)
+	}
+	if p.Floruit != "" {
+		g.line(1, "", "_FLOR", p.Floruit)
+	}
+	if p.ImageFilename != "" {
+		g.line(1, "", "OBJE", "")
+		g.line(2, "", "FILE", p.ImageFilename)
+		g.line(3, "", "FORM", strings.ToLower(strings.TrimPrefix(filepath.Ext(p.ImageFilename), ".")))
+	}
+	if p.Comment != "" {
+		g.line(1, "", "NOTE", p.Comment)
+	}
+	for _, attr := range p.Attributes {
+		g.line(1, "", "_ATTR", attr)
+	}
+	if p.ChildNumber != 0 {
+		g.line(1, "", "_CHNUM", strconv.Itoa(p.ChildNumber))
+	}
+	e.writeReferences(1, p.Sources)
+	if p.ID != "" {
+		g.line(1, "", "REFN", p.ID)
+	}
+	if p.UUID != "" {
+		g.line(1, "", "_UID", p.UUID)
+	}
+
+	for _, f := range e.childFamilies[p] {
+		g.pointer(1, "FAMC", f.XRef)
+		for _, c := range f.Children {
+			if c.Person != p {
+				continue
+			}
+			switch c.DadType {
+			case ParentLinkTypeAdoptive:
+				g.line(2, "", "PEDI", "adopted")
+			case ParentLinkTypeFoster:
+				g.line(2, "", "PEDI", "foster")
+			}
+			// the periods of the links to both parents
+			if !c.DadPeriod.Empty() && f.Husband != nil {
+				g.line(2, "", "_FDATE", formatGedcomPeriod(c.DadPeriod))
+			}
+			if !c.MomPeriod.Empty() && f.Wife != nil {
+				g.line(2, "", "_MDATE", formatGedcomPeriod(c.MomPeriod))
+			}
+		}
+	}
+	for _, f := range e.spouseFamilies[p] {
+		g.pointer(1, "FAMS", f.XRef)
+	}
+}
+
+func (e *gedcomExporter) writeName(n Name) {
+	if n.Empty() {
+		return
+	}
+	g := e.g
+	given := strings.Join(n.First, " ")
+	g.line(1, "", "NAME", strings.TrimSpace(given+" /"+strings.TrimSpace(n.Particle+" "+n.Last)+"/"))
+	if n.Title != "" {
+		g.line(2, "", "NPFX", n.Title)
+	}
+	if given != "" {
+		g.line(2, "", "GIVN", given)
+	}
+	if n.Particle != "" {
+		g.line(2, "", "SPFX", n.Particle)
+	}
+	if n.Last != "" {
+		g.line(2, "", "SURN", n.Last)
+	}
+	if n.Nick != "" {
+		g.line(2, "", "NICK", n.Nick)
+	}
+	if n.Used != "" {
+		g.line(2, "", "_RUFNAME", n.Used)
+	}
+	if n.Patronymic != "" {
+		g.line(2, "", "_PATR", n.Patronymic)
+	}
+	if n.Order != 0 {
+		g.line(2, "", "_ORDER", n.Order.String())
+	}
+	if n.Birth != "" {
+		g.line(1, "", "NAME", strings.TrimSpace(given+" /"+n.Birth+"/"))
+		g.line(2, "", "TYPE", "birth")
+	}
+	if n.Alias != "" {
+		g.line(1, "", "NAME", n.Alias)
+		g.line(2, "", "TYPE", "aka")
+	}
+}
+
+func (e *gedcomExporter) writeDatePlace(level int, tag string, d DatePlace) {
+	if d.Empty() && len(d.Sources) == 0 {
+		return
+	}
+	e.g.line(level, "", tag, "")
+	if !d.Date.Empty() {
+		e.g.line(level+1, "", "DATE", formatGedcomDate(d.Date))
+	}
+	if d.Place != "" {
+		e.g.line(level+1, "", "PLAC", d.Place)
+	}
+	e.writeReferences(level+1, d.Sources)
+}
+
+func (e *gedcomExporter) writePeriodPlace(level int, period Period, place string, references []Reference) {
+	if !period.Empty() {
+		e.g.line(level, "", "DATE", formatGedcomPeriod(period))
+	}
+	if place != "" {
+		e.g.line(level, "", "PLAC", place)
+	}
+	e.writeReferences(level, references)
+}
+
+func (e *gedcomExporter) writeReferences(level int, references []Reference) {
+	g := e.g
+	for _, r := range references {
+		xref, ok := e.sources[r.SourceID]
+		if !ok {
+			continue
+		}
+		g.pointer(level, "SOUR", xref)
+		if r.Page != "" {
+			g.line(level+1, "", "PAGE", r.Page)
+		}
+		if r.Transcript != "" {
+			g.line(level+1, "", "DATA", "")
+			g.line(level+2, "", "TEXT", r.Transcript)
+		}
+		if r.Quality != 0 {
+			g.line(level+1, "", "QUAY", strconv.Itoa(int(r.Quality)-1))
+		}
+	}
+}
+
+func (e *gedcomExporter) writeFamily(f *gedcomFamily) {
+	g := e.g
+	g.line(0, f.XRef, "FAM", "")
+	if f.Husband != nil {
+		g.pointer(1, "HUSB", e.persons[f.Husband])
+	}
+	if f.Wife != nil {
+		g.pointer(1, "WIFE", e.persons[f.Wife])
+	}
+	e.writeDatePlace(1, "ENGA", f.Relationship.Engagement)
+	e.writeDatePlace(1, "MARR", f.Relationship.Marriage)
+	e.writeDatePlace(1, "DIV", f.Relationship.Divorce)
+	for _, c := range f.Children {
+		g.pointer(1, "CHIL", e.persons[c.Person])
+		// step children can't be expressed by PEDI
+		if c.DadType == ParentLinkTypeStep && f.Husband != nil {
+			g.line(2, "", "_FREL", "Step")
+		}
+		if c.MomType == ParentLinkTypeStep && f.Wife != nil {
+			g.line(2, "", "_MREL", "Step")
+		}
+	}
+}
+
+func (e *gedcomExporter) writeSource(s Source) {
+	g := e.g
+	g.line(0, e.sources[s.ID], "SOUR", "")
+	if s.Title != "" {
+		g.line(1, "", "TITL", s.Title)
+	}
+	if s.Author != "" {
+		g.line(1, "", "AUTH", s.Author)
+	}
+	if s.Repository != "" || s.CallNumber != "" {
+		xref := ""
+		if s.Repository != "" {
+			xref = e.getRepository(s.Repository)
+		}
+		g.pointer(1, "REPO", xref)
+		if s.CallNumber != "" {
+			g.line(2, "", "CALN", s.CallNumber)
+		}
+	}
+	if s.Archive != "" {
+		g.line(1, "", "_ARCH", s.Archive)
+	}
+	if s.URL != "" {
+		g.line(1, "", "_URL", s.URL)
+	}
+	g.line(1, "", "REFN", s.ID)
+}
+
+func (e *gedcomExporter) getRepository(name string) string {
+	for i, r := range e.repositories {
+		if r == name {
+			return "@R" + strconv.Itoa(i+1) + "@"
+		}
+	}
+	e.repositories = append(e.repositories, name)
+	return "@R" + strconv.Itoa(len(e.repositories)) + "@"
+}
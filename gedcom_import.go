@@ -0,0 +1,652 @@
+package generations
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+type gedcomImporter struct {
+	database *MemoryDatabase
+	issues   []GedcomIssue
+
+	// records by cross reference
+	notes        map[string]*gedcomNode
+	objects      map[string]*gedcomNode
+	repositories map[string]*gedcomNode
+	persons      map[string]*FlatPerson
+	personOrder  []string
+	sourceIDs    map[string]string
+	// childLinks holds the links given by FAMC per child and family
+	childLinks map[string]map[string]gedcomChildLink
+}
+
+// gedcomChildLink is the link of a child to the parents of a family, its type is given by PEDI
+type gedcomChildLink struct {
+	Type      ParentLinkType
+	DadPeriod Period
+	MomPeriod Period
+}
+
+// ParseGedcomFile imports persons, families and sources from a GEDCOM 5.5.1 file. Parts of the file that can't be
+// represented in the database are returned as issues.
+func (y *MemoryDatabase) ParseGedcomFile(filename string) ([]GedcomIssue, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Annotatef(err, "error reading gedcom file %s", filename)
+	}
+	defer file.Close()
+
+	issues, err := y.ParseGedcom(file)
+	if err != nil {
+		return issues, errors.Annotatef(err, "error parsing gedcom file %s", filename)
+	}
+	return issues, nil
+}
+
+// ParseGedcom imports persons, families and sources from GEDCOM 5.5.1 data in UTF-8
+func (y *MemoryDatabase) ParseGedcom(r io.Reader) ([]GedcomIssue, error) {
+	records, err := parseGedcom(r)
+	if err != nil {
+		return nil, err
+	}
+
+	i := gedcomImporter{
+		database:     y,
+		notes:        make(map[string]*gedcomNode),
+		objects:      make(map[string]*gedcomNode),
+		repositories: make(map[string]*gedcomNode),
+		persons:      make(map[string]*FlatPerson),
+		sourceIDs:    make(map[string]string),
+		childLinks:   make(map[string]map[string]gedcomChildLink),
+	}
+
+	// referenced records first, so that they can be resolved no matter where they are in the file
+	for _, record := range records {
+		switch record.Tag {
+		case "NOTE":
+			i.notes[record.XRef] = record
+		case "OBJE":
+			i.objects[record.XRef] = record
+		case "REPO":
+			i.repositories[record.XRef] = record
+		case "SOUR":
+			i.sourceIDs[record.XRef] = record.childValue("REFN")
+			if i.sourceIDs[record.XRef] == "" {
+				i.sourceIDs[record.XRef] = strings.ToLower(strings.Trim(record.XRef, "@"))
+			}
+		}
+	}
+	for _, record := range records {
+		switch record.Tag {
+		case "HEAD":
+			i.importHeader(record)
+		case "SOUR":
+			i.importSource(record)
+		case "INDI":
+			i.importPerson(record)
+		case "NOTE", "OBJE", "REPO", "FAM", "SUBM", "SUBN", "TRLR":
+		default:
+			i.report(record, record.Tag, "record not imported")
+		}
+	}
+	i.makeIDs()
+	for _, record := range records {
+		if record.Tag == "FAM" {
+			i.importFamily(record)
+		}
+	}
+
+	for _, xref := range i.personOrder {
+		p := i.persons[xref]
+		p.Database = y
+		y.Persons = append(y.Persons, p)
+	}
+	y.Reindex()
+
+	return i.issues, nil
+}
+
+func (i *gedcomImporter) report(n *gedcomNode, path string, message string) {
+	i.issues = append(i.issues, GedcomIssue{
+		Line:    n.Line,
+		Path:    path,
+		Message: message,
+	})
+}
+
+func (i *gedcomImporter) reportUnmapped(n *gedcomNode, path string) {
+	i.report(n, path+" "+n.Tag, "tag not imported")
+}
+
+func recordPath(n *gedcomNode) string {
+	return n.Tag + " " + n.XRef
+}
+
+func (i *gedcomImporter) importHeader(n *gedcomNode) {
+	if charset := n.childValue("CHAR"); charset != "" && !strings.EqualFold(charset, "UTF-8") {
+		i.report(n.child("CHAR"), "HEAD CHAR", "only UTF-8 is supported, characters may be wrong")
+	}
+}
+
+func (i *gedcomImporter) importSource(n *gedcomNode) {
+	source := Source{
+		ID: i.sourceIDs[n.XRef],
+	}
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "TITL":
+			source.Title = c.Value
+		case "AUTH":
+			source.Author = c.Value
+		case "WWW", "_URL":
+			source.URL = c.Value
+		case "CHAN", "REFN":
+		case "_ARCH":
+			source.Archive = c.Value
+		case "REPO":
+			source.CallNumber = c.childValue("CALN")
+			if repo, ok := i.repositories[c.Value]; ok {
+				source.Repository = repo.childValue("NAME")
+			} else if !c.isPointer() {
+				source.Repository = c.Value
+			}
+		default:
+			i.reportUnmapped(c, recordPath(n))
+		}
+	}
+	i.database.Sources = append(i.database.Sources, source)
+}
+
+func (i *gedcomImporter) importReference(n *gedcomNode, path string) (Reference, bool) {
+	sourceID, ok := i.sourceIDs[n.Value]
+	if !ok {
+		i.report(n, path+" SOUR", "only references to source records are supported")
+		return Reference{}, false
+	}
+	reference := Reference{SourceID: sourceID}
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "PAGE":
+			reference.Page = c.Value
+		case "QUAY":
+			quality, err := strconv.Atoi(c.Value)
+			if err != nil || quality < 0 || quality > 3 {
+				i.report(c, path+" SOUR QUAY", "invalid quality "+c.Value)
+				continue
+			}
+			reference.Quality = ReferenceQuality(quality + 1)
+		case "DATA":
+			reference.Transcript = c.childValue("TEXT")
+		default:
+			i.reportUnmapped(c, path+" SOUR")
+		}
+	}
+	return reference, true
+}
+
+func (i *gedcomImporter) importDate(n *gedcomNode, path string) Date {
+	date, ok := parseGedcomDate(n.Value)
+	if !ok {
+		i.report(n, path+" DATE", "date format not understood, imported as is: "+n.Value)
+	}
+	return date
+}
+
+func (i *gedcomImporter) importPeriod(n *gedcomNode, path string) Period {
+	period, ok := parseGedcomPeriod(n.Value)
+	if !ok {
+		i.report(n, path+" DATE", "date format not understood, imported as is: "+n.Value)
+		return Period{From: Date(n.Value)}
+	}
+	return period
+}
+
+func (i *gedcomImporter) importNote(n *gedcomNode) string {
+	if note, ok := i.notes[n.Value]; ok {
+		return note.Value
+	}
+	return n.Value
+}
+
+func (i *gedcomImporter) importDatePlace(n *gedcomNode, path string) DatePlace {
+	path += " " + n.Tag
+	var result DatePlace
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "DATE":
+			result.Date = i.importDate(c, path)
+		case "PLAC":
+			result.Place = c.Value
+		case "SOUR":
+			if reference, ok := i.importReference(c, path); ok {
+				result.Sources = append(result.Sources, reference)
+			}
+		default:
+			i.reportUnmapped(c, path)
+		}
+	}
+	// "1 DEAT Y" states that the event happened without giving details
+	if n.Value != "" && result.Empty() {
+		if strings.EqualFold(n.Value, "Y") {
+			result.Date = "?"
+		} else {
+			i.report(n, path, "event description not imported: "+n.Value)
+		}
+	}
+	return result
+}
+
+func (i *gedcomImporter) importPerson(n *gedcomNode) {
+	path := recordPath(n)
+	p := &FlatPerson{}
+	names := 0
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "NAME":
+			i.importName(c, path, p, names)
+			names++
+		case "SEX":
+			switch strings.ToUpper(c.Value) {
+			case "M":
+				p.Gender = GenderMale.String()
+			case "F":
+				p.Gender = GenderFemale.String()
+			}
+		case "BIRT":
+			p.Birth = i.importDatePlace(c, path)
+		case "CHR", "BAPM":
+			p.Baptism = i.importDatePlace(c, path)
+		case "DEAT":
+			p.Death = i.importDatePlace(c, path)
+		case "BURI":
+			p.Burial = i.importDatePlace(c, path)
+		case "OCCU":
+			p.Jobs = append(p.Jobs, i.importJob(c, path))
+		case "RESI":
+			p.Residences = append(p.Residences, i.importResidence(c, path))
+		case "NOTE":
+			if p.Comment != "" {
+				p.Comment += "\n"
+			}
+			p.Comment += i.importNote(c)
+		case "OBJE":
+			i.importImage(c, path, p)
+		case "SOUR":
+			if reference, ok := i.importReference(c, path); ok {
+				p.Sources = append(p.Sources, reference)
+			}
+		case "EDUC", "_MILT", "EMIG", "EVEN":
+			p.BiographyElements = append(p.BiographyElements, i.importBiographyElement(c, path))
+		case "_UID", "UID":
+			if p.UUID == "" {
+				p.UUID = c.Value
+			}
+		case "REFN":
+			p.ID = c.Value
+		case "_FLOR":
+			p.Floruit = c.Value
+		case "_ATTR":
+			p.Attributes = append(p.Attributes, c.Value)
+		case "_CHNUM":
+			childNumber, err := strconv.Atoi(c.Value)
+			if err != nil {
+				i.report(c, path+" _CHNUM", "invalid child number "+c.Value)
+				continue
+			}
+			p.ChildNumber = childNumber
+		case "FAMC":
+			i.importChildLink(c, n.XRef, path)
+		case "FAMS", "CHAN":
+		default:
+			i.reportUnmapped(c, path)
+		}
+	}
+	i.persons[n.XRef] = p
+	i.personOrder = append(i.personOrder, n.XRef)
+}
+
+// importName reads names given like "Carl Friedrich /Gauß/". Names after the first one can give the birth name.
+func (i *gedcomImporter) importName(n *gedcomNode, path string, p *FlatPerson, index int) {
+	path += " NAME"
+	given, surname := splitGedcomName(n.Value)
+	if v := n.childValue("GIVN"); v != "" {
+		given = v
+	}
+	if v := n.childValue("SURN"); v != "" {
+		surname = v
+	}
+	nameType := strings.ToLower(n.childValue("TYPE"))
+
+	if index > 0 {
+		switch nameType {
+		case "birth", "maiden":
+			p.Name.Birth = surname
+		case "married":
+			if p.Name.Birth == "" {
+				p.Name.Birth = p.Name.Last
+			}
+			p.Name.Last = surname
+		case "aka":
+			p.Name.Alias = strings.Join(strings.Fields(strings.Replace(n.Value, "/", " ", -1)), " ")
+		default:
+			i.report(n, path, "additional name not imported: "+n.Value)
+		}
+		return
+	}
+
+	p.Name.First = strings.Fields(given)
+	p.Name.Last = surname
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "GIVN", "SURN", "TYPE":
+		case "NPFX":
+			p.Name.Title = c.Value
+		case "SPFX":
+			p.Name.Particle = c.Value
+			// the surname of the name line starts with the particle
+			if n.child("SURN") == nil {
+				p.Name.Last = strings.TrimSpace(strings.TrimPrefix(surname, c.Value))
+			}
+		case "_PATR":
+			p.Name.Patronymic = c.Value
+		case "_ORDER":
+			order, err := ParseNameOrder(c.Value)
+			if err != nil {
+				i.report(c, path+" _ORDER", "invalid name order "+c.Value)
+				continue
+			}
+			p.Name.Order = order
+		case "NICK":
+			p.Name.Nick = c.Value
+		case "_RUFNAME":
+			p.Name.Used = c.Value
+		case "_MARNM":
+			p.Name.Birth = p.Name.Last
+			p.Name.Last = c.Value
+		default:
+			i.reportUnmapped(c, path)
+		}
+	}
+	if nameType == "married" {
+		i.report(n, path+" TYPE", "birth name unknown for married name")
+	}
+}
+
+func splitGedcomName(value string) (given, surname string) {
+	start := strings.Index(value, "/")
+	if start < 0 {
+		return strings.TrimSpace(value), ""
+	}
+	end := strings.Index(value[start+1:], "/")
+	if end < 0 {
+		return strings.TrimSpace(value[:start]), strings.TrimSpace(value[start+1:])
+	}
+	end += start + 1
+	given = strings.TrimSpace(value[:start] + " " + value[end+1:])
+	return given, strings.TrimSpace(value[start+1 : end])
+}
+
+func (i *gedcomImporter) importJob(n *gedcomNode, path string) Job {
+	path += " OCCU"
+	job := Job{Title: n.Value}
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "DATE":
+			job.Period = i.importPeriod(c, path)
+		case "PLAC":
+			job.Place = c.Value
+		case "SOUR":
+			if reference, ok := i.importReference(c, path); ok {
+				job.Sources = append(job.Sources, reference)
+			}
+		default:
+			i.reportUnmapped(c, path)
+		}
+	}
+	return job
+}
+
+var gedcomBiographyElementTypes = map[string]BiographyElementType{
+	"EDUC":  BiographyElementTypeEducation,
+	"_MILT": BiographyElementTypeMilitary,
+	"EMIG":  BiographyElementTypeEmigration,
+	"EVEN":  BiographyElementTypeEvent,
+}
+
+func (i *gedcomImporter) importBiographyElement(n *gedcomNode, path string) BiographyElement {
+	path += " " + n.Tag
+	element := BiographyElement{
+		Type:        gedcomBiographyElementTypes[n.Tag],
+		Description: n.Value,
+	}
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "TYPE":
+			if element.Description == "" {
+				element.Description = c.Value
+			}
+		case "DATE":
+			element.Period = i.importPeriod(c, path)
+		case "PLAC":
+			element.Place = c.Value
+		case "SOUR":
+			if reference, ok := i.importReference(c, path); ok {
+				element.Sources = append(element.Sources, reference)
+			}
+		default:
+			i.reportUnmapped(c, path)
+		}
+	}
+	return element
+}
+
+func (i *gedcomImporter) importResidence(n *gedcomNode, path string) Residence {
+	path += " RESI"
+	var residence Residence
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "DATE":
+			residence.Period = i.importPeriod(c, path)
+		case "PLAC":
+			residence.Place = c.Value
+		case "SOUR":
+			if reference, ok := i.importReference(c, path); ok {
+				residence.Sources = append(residence.Sources, reference)
+			}
+		default:
+			i.reportUnmapped(c, path)
+		}
+	}
+	return residence
+}
+
+func (i *gedcomImporter) importImage(n *gedcomNode, path string, p *FlatPerson) {
+	object := n
+	if n.isPointer() {
+		var ok bool
+		if object, ok = i.objects[n.Value]; !ok {
+			i.report(n, path+" OBJE", "object record not found: "+n.Value)
+			return
+		}
+	}
+	filename := object.childValue("FILE")
+	if filename == "" {
+		i.report(n, path+" OBJE", "object without file not imported")
+		return
+	}
+	if p.ImageFilename != "" {
+		i.report(n, path+" OBJE", "only the first image is imported, skipped "+filename)
+		return
+	}
+	p.ImageFilename = filename
+}
+
+func (i *gedcomImporter) importChildLink(n *gedcomNode, childXRef string, path string) {
+	link := gedcomChildLink{Type: ParentLinkTypeBiological}
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "PEDI":
+			switch strings.ToLower(c.Value) {
+			case "birth":
+			case "adopted":
+				link.Type = ParentLinkTypeAdoptive
+			case "foster":
+				link.Type = ParentLinkTypeFoster
+			default:
+				i.report(c, path+" FAMC PEDI", "unsupported pedigree "+c.Value+", linked as biological")
+			}
+		case "_FDATE":
+			link.DadPeriod = i.importPeriod(c, path+" FAMC")
+		case "_MDATE":
+			link.MomPeriod = i.importPeriod(c, path+" FAMC")
+		default:
+			i.reportUnmapped(c, path+" FAMC")
+		}
+	}
+	if _, ok := i.childLinks[childXRef]; !ok {
+		i.childLinks[childXRef] = make(map[string]gedcomChildLink)
+	}
+	i.childLinks[childXRef][n.Value] = link
+}
+
+// makeIDs assigns unique IDs to all imported persons without REFN using GetFourFourYearID
+func (i *gedcomImporter) makeIDs() {
+	used := make(map[string]struct{})
+	for _, p := range i.database.Persons {
+		used[p.GetBestID()] = struct{}{}
+	}
+	for _, xref := range i.personOrder {
+		p := i.persons[xref]
+		id := p.ID
+		if id == "" {
+			id, _ = GetFourFourYearID(p)
+		}
+		if id == "" {
+			id = p.UUID
+		}
+		if id == "" {
+			id = strings.ToLower(strings.Trim(xref, "@"))
+		}
+		unique := id
+		for n := 2; ; n++ {
+			if _, ok := used[unique]; !ok {
+				break
+			}
+			unique = id + "-" + strconv.Itoa(n)
+		}
+		used[unique] = struct{}{}
+		p.ID = unique
+	}
+}
+
+func (i *gedcomImporter) importFamily(n *gedcomNode) {
+	path := recordPath(n)
+	var (
+		relationship FlatRelationship
+		husband      *FlatPerson
+		wife         *FlatPerson
+		children     []*gedcomNode
+	)
+	for _, c := range n.Children {
+		switch c.Tag {
+		case "HUSB":
+			husband = i.getPerson(c, path)
+		case "WIFE":
+			wife = i.getPerson(c, path)
+		case "CHIL":
+			children = append(children, c)
+		case "MARR":
+			relationship.Marriage = i.importDatePlace(c, path)
+		case "ENGA":
+			relationship.Engagement = i.importDatePlace(c, path)
+		case "DIV":
+			relationship.Divorce = i.importDatePlace(c, path)
+		case "CHAN":
+		default:
+			i.reportUnmapped(c, path)
+		}
+	}
+
+	if husband != nil && wife != nil {
+		relationship.PartnerID = wife.GetID()
+		husband.Partners = append(husband.Partners, relationship)
+	} else if !relationship.Marriage.Empty() || !relationship.Engagement.Empty() || !relationship.Divorce.Empty() {
+		i.report(n, path, "family events without both partners not imported")
+	}
+
+	for _, c := range children {
+		child := i.getPerson(c, path)
+		if child == nil {
+			continue
+		}
+		link, ok := i.childLinks[c.Value][n.XRef]
+		if !ok {
+			link.Type = ParentLinkTypeBiological
+		}
+		dadLinkType, momLinkType := link.Type, link.Type
+		for _, rel := range c.Children {
+			switch rel.Tag {
+			case "_FREL":
+				dadLinkType = i.parseRelation(rel, path, dadLinkType)
+			case "_MREL":
+				momLinkType = i.parseRelation(rel, path, momLinkType)
+			default:
+				i.reportUnmapped(rel, path+" CHIL")
+			}
+		}
+		if husband != nil {
+			linkParent(child, husband, ParentRoleDad, dadLinkType, link.DadPeriod)
+		}
+		if wife != nil {
+			linkParent(child, wife, ParentRoleMom, momLinkType, link.MomPeriod)
+		}
+	}
+}
+
+func (i *gedcomImporter) getPerson(n *gedcomNode, path string) *FlatPerson {
+	p, ok := i.persons[n.Value]
+	if !ok {
+		i.report(n, path+" "+n.Tag, "person not found: "+n.Value)
+		return nil
+	}
+	return p
+}
+
+// parseRelation reads the relationship of a child to one of its parents as exported by some applications
+func (i *gedcomImporter) parseRelation(n *gedcomNode, path string, fallback ParentLinkType) ParentLinkType {
+	switch strings.ToLower(n.Value) {
+	case "natural", "birth":
+		return ParentLinkTypeBiological
+	case "adopted":
+		return ParentLinkTypeAdoptive
+	case "step":
+		return ParentLinkTypeStep
+	case "foster":
+		return ParentLinkTypeFoster
+	}
+	i.report(n, path+" CHIL "+n.Tag, "unsupported relationship "+n.Value)
+	return fallback
+}
+
+func linkParent(child, parent *FlatPerson, role ParentRole, linkType ParentLinkType, period Period) {
+	if linkType == ParentLinkTypeBiological && period.Empty() {
+		switch {
+		case role == ParentRoleMom && child.Mom == "":
+			child.Mom = parent.GetID()
+			return
+		case role == ParentRoleDad && child.Dad == "":
+			child.Dad = parent.GetID()
+			return
+		}
+	}
+	child.Parents = append(child.Parents, ParentLink{
+		Period: period,
+		ID:     parent.GetID(),
+		Type:   linkType,
+		Role:   role,
+	})
+}
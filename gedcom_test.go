@@ -0,0 +1,260 @@
+package generations
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseGedcomDate(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected Date
+		OK       bool
+	}{
+		{Input: "", Expected: "", OK: true},
+		{Input: "1850", Expected: "1850", OK: true},
+		{Input: "MAR 1850", Expected: "1850-03", OK: true},
+		{Input: "12 Mar 1850", Expected: "1850-03-12", OK: true},
+		{Input: "ABT 1850", Expected: "about 1850", OK: true},
+		{Input: "CAL 1850", Expected: "about 1850", OK: true},
+		{Input: "EST 1850", Expected: "est 1850", OK: true},
+		{Input: "BEF 3 JAN 1850", Expected: "before 1850-01-03", OK: true},
+		{Input: "AFT 1850", Expected: "after 1850", OK: true},
+		{Input: "BET 1850 AND 1855", Expected: "between 1850 and 1855", OK: true},
+		{Input: "FROM 1850 TO 1855", Expected: "between 1850 and 1855", OK: true},
+		{Input: "12.03.1850", Expected: "12.03.1850", OK: true},
+		{Input: "31 FOO 1850", Expected: "31 FOO 1850", OK: false},
+		{Input: "(im Winter)", Expected: "im Winter", OK: false},
+		{Input: "(?)", Expected: "?", OK: true},
+	}
+
+	for _, test := range tests {
+		result, ok := parseGedcomDate(test.Input)
+		assert.Equal(t, string(test.Expected), string(result), test.Input)
+		assert.Equal(t, test.OK, ok, test.Input)
+	}
+
+	period, ok := parseGedcomPeriod("FROM 1807 TO 1855")
+	assert.True(t, ok)
+	assert.Equal(t, Period{From: "1807", To: "1855"}, period)
+	period, ok = parseGedcomPeriod("TO 1855")
+	assert.True(t, ok)
+	assert.Equal(t, Period{To: "1855"}, period)
+}
+
+func TestParseGedcomFile(t *testing.T) {
+	db := NewMemoryDatabase()
+	issues, err := db.ParseGedcomFile("testdata/gedcom/family.ged")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"GaußFrie1777", "GaußJoha1780", "GaußJose1806", "GaußMinn", "GaußJose1806-2"}, getPersonSliceIDs(personsOf(db)))
+
+	// person data
+	gauss, err := db.GetByID("6F2A0B3C")
+	assert.Nil(t, err)
+	assert.Equal(t, Name{First: []string{"Carl", "Friedrich"}, Used: "Friedrich", Last: "Gauß"}, gauss.GetName())
+	assert.Equal(t, GenderMale, gauss.GetGender())
+	assert.Equal(t, DatePlace{
+		Date:    "1777-04-30",
+		Place:   "Braunschweig",
+		Sources: []Reference{{SourceID: "s1", Page: "123", Quality: ReferenceQualityPrimary}},
+	}, gauss.GetBirth())
+	assert.Equal(t, Jobs{{Period: Period{From: "1807", To: "1855"}, Title: "Mathematiker", Place: "Göttingen"}}, gauss.GetJobs())
+	assert.Equal(t, "Princeps\nmathematicorum", db.Persons[0].Comment)
+	assert.Equal(t, "gauss.jpg", gauss.GetImageFilename())
+
+	johanna, err := db.GetByID("GaußJoha1780")
+	assert.Nil(t, err)
+	assert.Equal(t, "Osthoff", johanna.GetName().Birth)
+	assert.Equal(t, Date("between 1809 and 1810"), johanna.GetDeath().Date)
+
+	// families
+	assert.Equal(t, []FlatRelationship{{PartnerID: "GaußJoha1780", Marriage: DatePlace{Date: "1805-10-09", Place: "Braunschweig"}}}, db.Persons[0].Partners)
+	children, err := gauss.GetChildren()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"GaußJose1806"}, getPersonSliceIDs(children.GetPersons()))
+	children, err = johanna.GetChildrenByType(ParentLinkTypeAdoptive)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"GaußMinn"}, getPersonSliceIDs(children.GetPersons()))
+
+	// sources
+	assert.Equal(t, []Source{{ID: "s1", Title: "Kirchenbuch St. Katharinen", CallNumber: "KB 1777-1810", Repository: "Landeskirchliches Archiv Braunschweig"}}, db.Sources)
+
+	// unmapped data is reported
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.String()
+	}
+	assert.Equal(t, []string{
+		"line 30: INDI @I1@ CREM: tag not imported",
+		"line 60: INDI @I5@ DEAT DATE: date format not understood, imported as is: im Winter",
+		"line 77: _PLAC: record not imported",
+	}, messages)
+
+	// errors
+	_, err = db.ParseGedcomFile("testdata/gedcom/_invalid_filename_.ged")
+	assert.NotNil(t, err)
+	_, err = NewMemoryDatabase().ParseGedcom(strings.NewReader("0 HEAD\n2 CHAR UTF-8\n"))
+	assert.NotNil(t, err)
+}
+
+func personsOf(db *MemoryDatabase) []Person {
+	result := make([]Person, len(db.Persons))
+	for i, p := range db.Persons {
+		result[i] = p
+	}
+	return result
+}
+
+func TestParseGedcomEventWithoutDetails(t *testing.T) {
+	db := NewMemoryDatabase()
+	issues, err := db.ParseGedcom(strings.NewReader("0 HEAD\n1 CHAR UTF-8\n" +
+		"0 @I1@ INDI\n1 NAME Carl /Gauß/\n1 DEAT Y\n1 BURI Friedhof\n0 TRLR\n"))
+	assert.Nil(t, err)
+	assert.Len(t, db.Persons, 1)
+	assert.Equal(t, DatePlace{Date: "?"}, db.Persons[0].Death)
+	assert.False(t, db.Persons[0].GetDeath().Empty())
+	assert.True(t, db.Persons[0].Burial.Empty())
+	assert.Len(t, issues, 1)
+	assert.Equal(t, "line 6: INDI @I1@ BURI: event description not imported: Friedhof", issues[0].String())
+}
+
+func TestParseGedcomContinuation(t *testing.T) {
+	records, err := parseGedcom(strings.NewReader("0 @I1@ INDI\r\n" +
+		"  1 NOTE Erste \r\n" +
+		"2 CONC Zeile  \r\n" +
+		"2 CONT \r\n" +
+		"2 CONT zweite\r\n" +
+		"2 CONC  Zeile\r\n"))
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "Erste Zeile  \n\nzweite Zeile", records[0].childValue("NOTE"))
+}
+
+func TestFormatGedcomDate(t *testing.T) {
+	tests := []struct {
+		Input    Date
+		Expected string
+	}{
+		{Input: "", Expected: ""},
+		{Input: "?", Expected: "(?)"},
+		{Input: "1850-03-12", Expected: "12 MAR 1850"},
+		{Input: "um 1850-03", Expected: "ABT MAR 1850"},
+		{Input: "1850?", Expected: "EST 1850"},
+		{Input: "/1850", Expected: "BEF 1850"},
+		{Input: "1850/", Expected: "AFT 1850"},
+		{Input: "(1850~1855)", Expected: "BET 1850 AND 1855"},
+		{Input: "im Winter", Expected: "(im Winter)"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, formatGedcomDate(test.Input), string(test.Input))
+	}
+
+	assert.Equal(t, "FROM 1850 TO 1855", formatGedcomPeriod(Period{From: "1850", To: "1855"}))
+	assert.Equal(t, "TO 1855", formatGedcomPeriod(Period{To: "1855"}))
+	assert.Equal(t, "(FROM um 1850 TO 1855)", formatGedcomPeriod(Period{From: "um 1850", To: "1855"}))
+	assert.Equal(t, "1850", formatGedcomPeriod(Period{From: "1850", To: "1850"}))
+}
+
+func TestWriteGedcomLines(t *testing.T) {
+	var buffer bytes.Buffer
+	g := gedcomWriter{w: bufio.NewWriter(&buffer)}
+	g.line(1, "", "NOTE", strings.Repeat("a", 200)+" "+strings.Repeat("b", 10)+"\nzweite Zeile")
+	g.w.Flush()
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, []string{
+		"1 NOTE " + strings.Repeat("a", 199),
+		"2 CONC a " + strings.Repeat("b", 10),
+		"2 CONT zweite Zeile",
+	}, lines)
+}
+
+func TestSplitGedcomValue(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected []string
+	}{
+		{Input: "kurz", Expected: []string{"kurz"}},
+		{Input: strings.Repeat("a", 200), Expected: []string{strings.Repeat("a", 200)}},
+		{Input: strings.Repeat("a", 201), Expected: []string{strings.Repeat("a", 200), "a"}},
+		{Input: strings.Repeat("a", 199) + "ä", Expected: []string{strings.Repeat("a", 199), "ä"}},
+		{Input: strings.Repeat("a", 198) + "ä b", Expected: []string{strings.Repeat("a", 198), "ä b"}},
+		{Input: strings.Repeat(" ", 250), Expected: []string{strings.Repeat(" ", 200), strings.Repeat(" ", 50)}},
+	}
+
+	for _, test := range tests {
+		chunks := splitGedcomValue(test.Input)
+		assert.Equal(t, test.Expected, chunks)
+		for _, chunk := range chunks {
+			assert.True(t, utf8.ValidString(chunk), chunk)
+		}
+	}
+}
+
+func TestWriteGedcomEscaping(t *testing.T) {
+	var buffer bytes.Buffer
+	g := gedcomWriter{w: bufio.NewWriter(&buffer)}
+	g.line(0, "@I1@", "INDI", "")
+	g.line(1, "", "NOTE", "@gauss@ schrieb an gauss@example.org")
+	g.pointer(1, "FAMS", "@F1@")
+	g.w.Flush()
+	assert.Equal(t, "0 @I1@ INDI\n1 NOTE @@gauss@@ schrieb an gauss@@example.org\n1 FAMS @F1@\n", buffer.String())
+
+	records, err := parseGedcom(&buffer)
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "@gauss@ schrieb an gauss@example.org", records[0].childValue("NOTE"))
+	assert.False(t, records[0].child("NOTE").isPointer())
+	assert.Equal(t, "@F1@", records[0].childValue("FAMS"))
+	assert.True(t, records[0].child("FAMS").isPointer())
+}
+
+func TestWriteGedcomFamilyMerge(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/gedcom/partners.yml")
+	assert.Nil(t, err)
+
+	var buffer bytes.Buffer
+	err = db.WriteGedcom(&buffer)
+	assert.Nil(t, err)
+	output := buffer.String()
+	assert.Equal(t, 1, strings.Count(output, " FAM\n"))
+	assert.Contains(t, output, "1 MARR\n2 DATE 9 OCT 1805\n2 PLAC Braunschweig\n")
+	assert.Contains(t, output, "1 DIV\n2 DATE BEF 1809\n")
+}
+
+func TestWriteGedcomRoundTrip(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/gedcom/roundtrip.yml")
+	assert.Nil(t, err)
+
+	var buffer bytes.Buffer
+	err = db.WriteGedcom(&buffer)
+	assert.Nil(t, err)
+	output := buffer.String()
+	assert.True(t, strings.HasPrefix(output, "0 HEAD\n"))
+	assert.Contains(t, output, "1 CHAR UTF-8\n")
+	assert.Contains(t, output, "2 VERS 5.5.1\n")
+	assert.True(t, strings.HasSuffix(output, "0 TRLR\n"))
+	for _, line := range strings.Split(output, "\n") {
+		assert.True(t, len(line) <= 255, line)
+	}
+
+	imported := NewMemoryDatabase()
+	issues, err := imported.ParseGedcom(&buffer)
+	assert.Nil(t, err)
+	assert.Empty(t, issues)
+
+	expected, err := yaml.Marshal(YamlDatabase{Persons: db.Persons, Sources: db.Sources})
+	assert.Nil(t, err)
+	actual, err := yaml.Marshal(YamlDatabase{Persons: imported.Persons, Sources: imported.Sources})
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
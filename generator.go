@@ -2,102 +2,112 @@ package generations
 
 import (
 	"bytes"
-	"io/ioutil"
 	"regexp"
 	"strconv"
 	"strings"
-	"text/template"
 
-	"github.com/jojomi/strtpl"
 	"github.com/juju/errors"
 )
 
-func RenderGenealogytree(p Person, o RenderTreeOptions) ([]byte, error) {
-	o.SetDefaults()
+type treeData struct {
+	ParentTree      LaTeX
+	ChildTree       LaTeX
+	SiblingsYounger LaTeX
+	SiblingsOlder   LaTeX
+	// EdgeStyle is the style of the edges of the sandclock family
+	EdgeStyle string
+	Options   RenderTreeOptions
+	// Implex holds the persons appearing several times in the tree, nil if detection is disabled
+	Implex *ImplexStatistics
+}
 
-	var err error
-	parentTree, err := renderFullParentTree(p, o, true)
+// RenderGenealogytree renders the graph selected by o.GraphType for the given person
+func RenderGenealogytree(p Person, o RenderTreeOptions) ([]byte, error) {
+	m, err := BuildTree(p, o)
 	if err != nil {
-		return []byte{}, errors.Annotate(err, "could not render parent subtree")
+		return []byte{}, err
 	}
-	childTree, err := renderFullChildTree(p, o)
+	return m.Genealogytree()
+}
+
+// Genealogytree renders the model using the genealogytree templates set in its options
+func (m *TreeModel) Genealogytree() ([]byte, error) {
+	o := m.Options
+	// parse errors are reported before anything is rendered
+	err := Templates.Load(o.TemplateFilenames()...)
 	if err != nil {
-		return []byte{}, errors.Annotate(err, "could not render child subtree")
+		return []byte{}, err
 	}
-
-	var (
-		siblingsOlder   string
-		siblingsYounger string
-	)
-	if o.MaxParentSiblingsGenerations != GenerationsNone {
-		mom, err := p.GetMom()
+	switch m.Type {
+	case GraphTypeParent:
+		parentTree, err := m.Parent.genealogytree(o, true)
 		if err != nil {
-			return []byte{}, err
+			return []byte{}, errors.Annotate(err, "could not render parent tree")
 		}
-		dad, err := p.GetDad()
+		return renderTreeTemplate(m.Root, o.TemplateFilenameTreeParent, treeData{
+			ParentTree: LaTeX(parentTree),
+			Options:    o,
+		})
+	case GraphTypeChild, GraphTypeConnection:
+		childTree, err := m.Child.genealogytree(o, true)
 		if err != nil {
-			return []byte{}, err
+			return []byte{}, errors.Annotate(err, "could not render child tree")
 		}
-		siblings := NewPersonList(nil)
-		if !dad.IsDummy() {
-			siblings, err = dad.GetChildrenWith(mom)
-			if err != nil {
-				return []byte{}, err
-			}
-		} else if !mom.IsDummy() {
-			siblings, err = mom.GetChildrenWith(NewDummyFlatPerson())
-			if err != nil {
-				return []byte{}, err
-			}
+		return renderTreeTemplate(m.Root, o.TemplateFilenameTreeChild, treeData{
+			ChildTree: LaTeX(childTree),
+			Options:   o,
+		})
+	default:
+		parentTree, err := m.Parent.genealogytree(o, true)
+		if err != nil {
+			return []byte{}, errors.Annotate(err, "could not render parent subtree")
 		}
-
-		// apply ignore rules
-		siblings = nonIgnored(siblings, o)
-
-		// split older and younger
-		younger, older := SplitPersons(siblings, p)
-		opts := *o.RenderPersonOptions
-		opts.NodeType = NodeTypeC
-		opts = *opts.HideImageByLevel(o, 0)
-		siblingsOlder, err = renderPersonSlice(older, opts)
+		childTree, err := m.Child.genealogytree(o, true)
+		if err != nil {
+			return []byte{}, errors.Annotate(err, "could not render child subtree")
+		}
+		siblingsOlder, err := renderPersonNodes(m.SiblingsOlder)
 		if err != nil {
 			return []byte{}, err
 		}
-		siblingsYounger, err = renderPersonSlice(younger, opts)
+		siblingsYounger, err := renderPersonNodes(m.SiblingsYounger)
 		if err != nil {
 			return []byte{}, err
 		}
+		return renderTreeTemplate(m.Root, o.TemplateFilenameTree, treeData{
+			ParentTree:      LaTeX(parentTree),
+			ChildTree:       LaTeX(childTree),
+			SiblingsOlder:   siblingsOlder,
+			SiblingsYounger: siblingsYounger,
+			EdgeStyle:       m.Parent.EdgeStyle,
+			Options:         o,
+		})
 	}
+}
 
-	result, err := RenderTemplateFile(o.TemplateFilenameTree, struct {
-		ParentTree      string
-		ChildTree       string
-		SiblingsYounger string
-		SiblingsOlder   string
-		Options         RenderTreeOptions
-	}{
-		ParentTree:      string(parentTree),
-		ChildTree:       string(childTree),
-		SiblingsOlder:   siblingsOlder,
-		SiblingsYounger: siblingsYounger,
-		Options:         o,
-	})
+func renderTreeTemplate(p Person, templateFilename string, data treeData) ([]byte, error) {
+	data.Implex = data.Options.Implex
+	result, err := RenderTemplateFile(templateFilename, data)
 	if err != nil {
-		return []byte{}, errors.Annotatef(err, "could not render genealogytree template %s for person %s", o.TemplateFilenameTree, p)
+		return []byte{}, errors.Annotatef(err, "could not render genealogytree template %s for person %s", templateFilename, p)
 	}
 	return withoutEmptyLines(result), nil
 }
 
-func renderPersonSlice(personList PersonList, renderPersonOptions RenderPersonOptions) (string, error) {
+func renderPersonNodes(nodes []PersonNode) (LaTeX, error) {
 	var outputBuffer bytes.Buffer
-	for _, person := range personList.GetPersons() {
-		personData, err := renderPerson(person, renderPersonOptions)
+	for _, n := range nodes {
+		personData, err := n.genealogytree()
 		if err != nil {
 			return "", errors.Annotate(err, "could not render siblings")
 		}
 		outputBuffer.Write(personData)
 	}
-	return outputBuffer.String(), nil
+	return LaTeX(outputBuffer.String()), nil
+}
+
+func (n PersonNode) genealogytree() ([]byte, error) {
+	return renderPerson(n.Person, n.Options)
 }
 
 func renderPerson(p Person, o RenderPersonOptions) ([]byte, error) {
@@ -162,21 +172,8 @@ func latexify(input int) string {
 	return strings.Repeat("I", input)
 }
 
+// RenderTemplateFile executes the template in the given file of the registry Templates. Printed values are escaped for
+// LaTeX unless they are of type LaTeX or marked with the template function raw.
 func RenderTemplateFile(filename string, data interface{}) ([]byte, error) {
-	templateContent, err := ioutil.ReadFile(filename)
-	if err != nil {
-		return []byte{}, err
-	}
-	result, err := strtpl.EvalWithFuncMap(string(templateContent), template.FuncMap{
-		"noEmptyLines":           withoutEmptyLines,
-		"noEmptyLinesString":     withoutEmptyLinesString,
-		"toString":               toString,
-		"join":                   strings.Join,
-		"getFilteredStringSlice": getFilteredStringSlice,
-		"latexify":               latexify,
-	}, data)
-	if err != nil {
-		return []byte{}, err
-	}
-	return []byte(result), nil
+	return Templates.Render(filename, data)
 }
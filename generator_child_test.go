@@ -24,6 +24,7 @@ func TestRenderFullChildTree(t *testing.T) {
 		DatabaseFilename    string
 		ID                  string
 		MaxChildGenerations int
+		ParentLinkTypes     []ParentLinkType
 		Expected            string
 	}{
 		// alone
@@ -95,6 +96,28 @@ func TestRenderFullChildTree(t *testing.T) {
 			}`,
 		},
 
+		// parent link types
+		{
+			Name:             "adoption-biological",
+			DatabaseFilename: "adoption",
+			ID:               "adoptiv-mama",
+			Expected: `child{
+				g[id=adoptiv-mama,]{}
+				p[id=adoptiv-papa,]{}
+			}`,
+		},
+		{
+			Name:             "adoption-adoptive",
+			DatabaseFilename: "adoption",
+			ID:               "adoptiv-mama",
+			ParentLinkTypes:  []ParentLinkType{ParentLinkTypeBiological, ParentLinkTypeAdoptive},
+			Expected: `child[edges={foreground={link-adoptive}}]{
+				g[id=adoptiv-mama,]{}
+				p[id=adoptiv-papa,]{}
+				c[id=gauss,]{}
+			}`,
+		},
+
 		// MaxChildGenerations
 		{
 			Name:                "MaxChildGenerations",
@@ -119,6 +142,7 @@ func TestRenderFullChildTree(t *testing.T) {
 		person, err := database.GetByID(test.ID)
 		assert.Nil(t, err)
 		renderOptions.MaxChildGenerations = test.MaxChildGenerations
+		renderOptions.ParentLinkTypes = test.ParentLinkTypes
 		renderOptions.SetDefaults()
 		result, err := renderFullChildTree(person, renderOptions)
 		if err != nil {
@@ -128,3 +152,24 @@ func TestRenderFullChildTree(t *testing.T) {
 		assertOutputSemantic(t, test.Expected, string(result), fmt.Sprintf("Test %s failed", test.Name))
 	}
 }
+
+func TestRenderChildTreeCycle(t *testing.T) {
+	renderOptions := RenderTreeOptions{
+		GraphType: GraphTypeChild,
+	}
+	addTestTemplates(&renderOptions)
+	renderOptions.SetDefaults()
+
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "validate", "cycle.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("onkel")
+	assert.Nil(t, err)
+	_, err = renderFullChildTree(person, renderOptions)
+	assert.Nil(t, err)
+
+	person, err = database.GetByID("gauss")
+	assert.Nil(t, err)
+	_, err = renderFullChildTree(person, renderOptions)
+	assert.EqualError(t, err, "cycle in ancestry: gauss → opa → papa → gauss")
+}
@@ -5,110 +5,172 @@ import (
 )
 
 func renderFullChildTree(p Person, o RenderTreeOptions) ([]byte, error) {
-	return renderChildTree(p, o, NodeTypeG, 0)
-}
-
-func renderChildrenWithPartner(person, partner Person, o RenderTreeOptions, level int) (string, error) {
-	var buffer bytes.Buffer
-	children, err := person.GetChildrenWith(partner)
+	n, err := buildFullChildTree(p, o)
 	if err != nil {
-		return "", err
-	}
-	for _, child := range children.GetPersons() {
-		if child == nil {
-			continue
-		}
-		// recursive call
-		childData, err := renderChildTree(child, o, NodeTypeC, level+1)
-		if err != nil {
-			return "", err
-		}
-		buffer.Write(childData)
-		buffer.WriteString("\n")
+		return []byte{}, err
 	}
-	return buffer.String(), nil
+	return n.genealogytree(o, true)
+}
+
+func buildFullChildTree(p Person, o RenderTreeOptions) (*ChildNode, error) {
+	return buildChildTree(p, o, NodeTypeG, 0, nil)
 }
 
-func renderChildTree(p Person, o RenderTreeOptions, baseNodeType NodeType, level int) ([]byte, error) {
+// buildChildTree builds p and its descendants, the result is nil if p is ignored
+func buildChildTree(p Person, o RenderTreeOptions, baseNodeType NodeType, level int, path ancestryPath) (*ChildNode, error) {
 	var (
 		err error
 	)
 
 	// ignored?
 	if isPersonIgnored(p, o) {
-		return []byte{}, nil
+		return nil, nil
+	}
+	path, err = path.with(p)
+	if err != nil {
+		return nil, err
 	}
 
-	data := struct {
-		FamilyID string
+	// persons already in the tree are shown once only, without their descendants
+	n := &ChildNode{}
+	gOpts, implex := o.Implex.visit(p, *o.RenderPersonOptions)
+	if implex {
+		gOpts.NodeType = baseNodeType
+		n.G = PersonNode{Person: p, Options: *gOpts.HideImageByLevel(o, -level)}
+		return n, nil
+	}
 
-		G        string
-		Parent   string
-		Children string
-		Unions   string
+	// children by partner
+	partnerList, err := getPartners(p, o)
+	if err != nil {
+		return nil, err
+	}
+	partners := nonIgnored(partnerList, o)
+	for i, partner := range partners.GetPersons() {
+		union, err := buildUnion(p, partner, o, level, path)
+		if err != nil {
+			return nil, err
+		}
+		// special case: first partner -> no union allowed!
+		if i == 0 {
+			n.Partner = union.Partner
+			n.Children = union.Children
+			n.EdgeStyle = union.EdgeStyle
+			continue
+		}
+		n.Unions = append(n.Unions, union)
+	}
 
-		SiblingsYounger string
-		SiblingsOlder   string
-	}{}
+	// g node for anchor
+	gOpts.NodeType = baseNodeType
+	if !n.IsLeaf() {
+		gOpts.NodeType = NodeTypeG
+	}
+	if level == 0 && !gOpts.HideRootNodeHighlighting {
+		rootNodeKey := "rootnode"
+		p.AddAttribute(rootNodeKey)
+	}
+	n.G = PersonNode{Person: p, Options: *gOpts.HideImageByLevel(o, -level)}
+	if !o.HideFamilyIDs {
+		n.ID = "family-" + p.GetID()
+	}
+	return n, nil
+}
 
-	// render children by partner
-	partnerList, err := p.GetPartners()
+// buildUnion builds partner and the children of person with partner
+func buildUnion(person, partner Person, o RenderTreeOptions, level int, path ancestryPath) (*UnionNode, error) {
+	children, err := person.GetChildrenWithByType(partner, o.ParentLinkTypes...)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
+	}
+
+	u := &UnionNode{}
+	if !partner.IsDummy() && level < o.MaxChildPartnersGenerations {
+		opts, _ := o.Implex.visit(partner, *o.RenderPersonOptions)
+		opts.NodeType = NodeTypeP
+		u.Partner = &PersonNode{Person: partner, Options: *opts.HideImageByLevel(o, -level)}
+		if !o.HideFamilyIDs {
+			u.ID = "family-" + partner.GetID()
+		}
 	}
-	partners := nonIgnored(partnerList, o)
 
-	// unions (other partners)
-	if partners.Count() > 0 {
-		var unionBuffer bytes.Buffer
-		for i, partner := range partners.GetPersons() {
-			// special case: first partner -> no union allowed!
-			if i == 0 {
-				uData, err := renderUnionData(p, partner, o, level)
-				if err != nil {
-					return nil, err
-				}
-				data.Parent = uData.Parent
-				data.Children = uData.Children
+	if level < o.MaxChildGenerations {
+		parents := []PersonNode{{Person: person}}
+		if u.Partner != nil {
+			parents = append(parents, *u.Partner)
+		}
+		var childNodes []PersonNode
+		for _, child := range children.GetPersons() {
+			if child == nil {
 				continue
 			}
-
-			data, err := renderUnion(p, partner, o, level)
+			// recursive call
+			childNode, err := buildChildTree(child, o, NodeTypeC, level+1, path)
 			if err != nil {
 				return nil, err
 			}
-
-			unionBuffer.Write(data)
-			unionBuffer.WriteString("\n")
+			if childNode != nil {
+				childNode.G.Options.Links = parentNodeLinks(childNode.G, parents...)
+				u.Children = append(u.Children, childNode)
+				childNodes = append(childNodes, childNode.G)
+			}
 		}
-		data.Unions = unionBuffer.String()
+		u.EdgeStyle = familyEdgeStyle(childNodes, parents)
 	}
+	return u, nil
+}
 
-	// render g node for anchor
-	mainNodeType := baseNodeType
-	if data.Children != "" || data.Unions != "" || data.Parent != "" {
-		mainNodeType = NodeTypeG
+// genealogytree renders the node, leafs are rendered as single person nodes unless they are the root of the tree
+func (n *ChildNode) genealogytree(o RenderTreeOptions, root bool) ([]byte, error) {
+	if n == nil {
+		return []byte{}, nil
 	}
-	opts := *o.RenderPersonOptions
-	opts.NodeType = mainNodeType
-	opts = *opts.HideImageByLevel(o, -level)
-	if level == 0 && !opts.HideRootNodeHighlighting {
-		rootNodeKey := "rootnode"
-		p.AddAttribute(rootNodeKey)
+	g, err := n.G.genealogytree()
+	if err != nil {
+		return nil, err
+	}
+	if n.IsLeaf() && !root {
+		return g, nil
 	}
 
-	g, err := renderPerson(p, opts)
+	first := UnionNode{
+		EdgeStyle: n.EdgeStyle,
+		Partner:   n.Partner,
+		Children:  n.Children,
+	}
+	uData, err := first.genealogytreeData(o)
 	if err != nil {
 		return nil, err
 	}
-	data.G = string(g)
-	if !o.HideFamilyIDs {
-		data.FamilyID = "family-" + p.GetID()
+	data := struct {
+		FamilyID  string
+		EdgeStyle string
+
+		G        LaTeX
+		Parent   LaTeX
+		Children LaTeX
+		Unions   LaTeX
+
+		SiblingsYounger LaTeX
+		SiblingsOlder   LaTeX
+	}{
+		FamilyID:  n.ID,
+		EdgeStyle: uData.EdgeStyle,
+		G:         LaTeX(g),
+		Parent:    uData.Parent,
+		Children:  uData.Children,
 	}
 
-	if data.Children == "" && data.Unions == "" && data.Parent == "" && level > 0 {
-		return []byte(data.G), nil
+	var unionBuffer bytes.Buffer
+	for _, union := range n.Unions {
+		unionOutput, err := union.genealogytree(o)
+		if err != nil {
+			return nil, err
+		}
+		unionBuffer.Write(unionOutput)
+		unionBuffer.WriteString("\n")
 	}
+	data.Unions = LaTeX(unionBuffer.String())
 
 	templateFile := o.TemplateFilenameChildTree
 	result, err := RenderTemplateFile(templateFile, data)
@@ -119,57 +181,42 @@ func renderChildTree(p Person, o RenderTreeOptions, baseNodeType NodeType, level
 }
 
 type unionData struct {
-	FamilyID string
-	Parent   string
-	Children string
+	FamilyID  string
+	EdgeStyle string
+	Parent    LaTeX
+	Children  LaTeX
 }
 
-func renderUnionData(person, partner Person, o RenderTreeOptions, level int) (unionData, error) {
-	var buffer bytes.Buffer
-	children, err := person.GetChildrenWith(partner)
-	if err != nil {
-		return unionData{}, err
+func (u *UnionNode) genealogytreeData(o RenderTreeOptions) (unionData, error) {
+	data := unionData{
+		FamilyID:  u.ID,
+		EdgeStyle: u.EdgeStyle,
 	}
-
-	var data unionData
-
-	if !partner.IsDummy() && level < o.MaxChildPartnersGenerations {
-		opts := *o.RenderPersonOptions
-		opts.NodeType = NodeTypeP
-		opts = *opts.HideImageByLevel(o, -level)
-		parentData, err := renderPerson(partner, opts)
+	if u.Partner != nil {
+		parentData, err := u.Partner.genealogytree()
 		if err != nil {
 			return data, err
 		}
-		data.Parent = string(parentData)
-		if !o.HideFamilyIDs {
-			data.FamilyID = "family-" + partner.GetID()
-		}
+		data.Parent = LaTeX(parentData)
 	}
 
-	if level < o.MaxChildGenerations {
-		for _, child := range children.GetPersons() {
-			if child == nil {
-				continue
-			}
-			// recursive call
-			childData, err := renderChildTree(child, o, NodeTypeC, level+1)
-			if err != nil {
-				return data, err
-			}
-			buffer.Write(childData)
-			buffer.WriteString("\n")
+	var buffer bytes.Buffer
+	for _, child := range u.Children {
+		childData, err := child.genealogytree(o, false)
+		if err != nil {
+			return data, err
 		}
-		data.Children = buffer.String()
+		buffer.Write(childData)
+		buffer.WriteString("\n")
 	}
-
+	data.Children = LaTeX(buffer.String())
 	return data, nil
 }
 
-func renderUnion(person, partner Person, o RenderTreeOptions, level int) ([]byte, error) {
-	data, err := renderUnionData(person, partner, o, level)
+func (u *UnionNode) genealogytree(o RenderTreeOptions) ([]byte, error) {
+	data, err := u.genealogytreeData(o)
 	if err != nil {
-		return []byte{}, nil
+		return []byte{}, err
 	}
 
 	templateFile := o.TemplateFilenameUnionTree
@@ -180,6 +227,23 @@ func renderUnion(person, partner Person, o RenderTreeOptions, level int) ([]byte
 	return withoutEmptyLines(result), nil
 }
 
+// getPartners returns the partners of a person including those that are parents of children linked by any of the
+// link types to follow
+func getPartners(p Person, o RenderTreeOptions) (PersonList, error) {
+	partners, err := p.GetPartners()
+	if err != nil {
+		return partners, err
+	}
+	if len(o.ParentLinkTypes) == 1 && o.ParentLinkTypes[0] == ParentLinkTypeBiological {
+		return partners, nil
+	}
+	childrenParents, err := p.GetChildrenParentsByType(o.ParentLinkTypes...)
+	if err != nil {
+		return partners, err
+	}
+	return *partners.AddList(&childrenParents).RemoveDuplicates(), nil
+}
+
 func isPersonIgnored(p Person, oTree RenderTreeOptions) bool {
 	for _, id := range oTree.IgnoreIDs {
 		if p.MatchesSearch(id) {
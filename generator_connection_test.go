@@ -0,0 +1,163 @@
+package generations
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderConnectionTree(t *testing.T) {
+	renderOptions := RenderTreeOptions{
+		GraphType:     GraphTypeConnection,
+		HideFamilyIDs: true,
+	}
+	addTestTemplates(&renderOptions)
+	renderOptions.SetDefaults()
+	renderOptions.RenderPersonOptions.HideAllData()
+	renderOptions.RenderPersonOptions.HideRootNodeHighlighting = true
+
+	tests := []struct {
+		Name     string
+		From, To string
+		Siblings bool
+		Expected string
+	}{
+		{
+			Name: "cousins",
+			From: "gauss",
+			To:   "cousin",
+			Expected: `child{
+				g[id=grossvater,]{}
+				p[id=grossmutter,]{}
+				child{
+					g[id=vater,]{}
+					p[id=mutter,]{}
+					c[id=gauss,]{}
+				}
+				child{
+					g[id=onkel,]{}
+					p[id=angeheiratete-tante,]{}
+					c[id=cousin,]{}
+				}
+			}`,
+		},
+		{
+			Name:     "cousins with siblings",
+			From:     "gauss",
+			To:       "cousin",
+			Siblings: true,
+			Expected: `child{
+				g[id=grossvater,]{}
+				p[id=grossmutter,]{}
+				child{
+					g[id=vater,]{}
+					p[id=mutter,]{}
+					c[id=gauss,]{}
+					c[id=bruder,]{}
+				}
+				child{
+					g[id=onkel,]{}
+					p[id=angeheiratete-tante,]{}
+					c[id=cousin,]{}
+				}
+			}`,
+		},
+		{
+			Name: "half siblings",
+			From: "gauss",
+			To:   "halbschwester",
+			Expected: `child{
+				g[id=mutter,]{}
+				p[id=vater,]{}
+				c[id=gauss,]{}
+				union{
+					p[id=anderer-mann,]{}
+					c[id=halbschwester,]{}
+				}
+			}`,
+		},
+		{
+			Name: "descendant",
+			From: "grossvater",
+			To:   "gauss",
+			Expected: `child{
+				g[id=grossvater,]{}
+				p[id=grossmutter,]{}
+				child{
+					g[id=vater,]{}
+					p[id=mutter,]{}
+					c[id=gauss,]{}
+				}
+			}`,
+		},
+		{
+			Name: "unknown parent",
+			From: "cousin-vater",
+			To:   "grossvater",
+			Expected: `child{
+				g[id=urgrossvater,]{}
+				p[id=urgrossmutter,]{}
+				c[id=grossvater,]{}
+				child{
+					g[id=grosstante,]{}
+					c[id=cousin-vater,]{}
+				}
+			}`,
+		},
+	}
+
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "relationship.yml"))
+	assert.Nil(t, err)
+	for _, test := range tests {
+		a, err := database.GetByID(test.From)
+		assert.Nil(t, err)
+		b, err := database.GetByID(test.To)
+		assert.Nil(t, err)
+		renderOptions.ShowConnectionSiblings = test.Siblings
+		result, err := renderConnectionTree(a, b, renderOptions)
+		assert.Nil(t, err, test.Name)
+		assertOutputSemantic(t, test.Expected, string(result), test.Name)
+	}
+
+	a, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+	b, err := database.GetByID("frau")
+	assert.Nil(t, err)
+	_, err = renderConnectionTree(a, b, renderOptions)
+	assert.EqualError(t, err, "no common ancestor found for gauss and frau")
+}
+
+func TestConnectionProbandLevel(t *testing.T) {
+	tests := []struct {
+		From, To string
+		Expected int
+	}{
+		{From: "gauss", To: "cousin", Expected: 2},
+		{From: "cousin", To: "gauss", Expected: 2},
+		{From: "gauss", To: "halbschwester", Expected: 1},
+		{From: "grossvater", To: "gauss", Expected: 0},
+		{From: "gauss", To: "grossvater", Expected: 2},
+	}
+
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "relationship.yml"))
+	assert.Nil(t, err)
+	for _, test := range tests {
+		a, err := database.GetByID(test.From)
+		assert.Nil(t, err)
+		b, err := database.GetByID(test.To)
+		assert.Nil(t, err)
+		level, err := ConnectionProbandLevel(a, b)
+		assert.Nil(t, err)
+		assert.Equal(t, test.Expected, level, test.From+" to "+test.To)
+	}
+
+	a, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+	b, err := database.GetByID("frau")
+	assert.Nil(t, err)
+	_, err = ConnectionProbandLevel(a, b)
+	assert.EqualError(t, err, "no common ancestor found for gauss and frau")
+}
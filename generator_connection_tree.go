@@ -0,0 +1,218 @@
+package generations
+
+import (
+	"github.com/juju/errors"
+)
+
+// RenderConnectionTree renders the nearest common ancestors of a and b and the lines of descent down to both of them
+func RenderConnectionTree(a, b Person, o RenderTreeOptions) ([]byte, error) {
+	m, err := BuildConnectionTree(a, b, o)
+	if err != nil {
+		return []byte{}, err
+	}
+	return m.Genealogytree()
+}
+
+// BuildConnectionTree builds the nearest common ancestors of a and b and the lines of descent down to both of them
+func BuildConnectionTree(a, b Person, o RenderTreeOptions) (*TreeModel, error) {
+	o.SetDefaults()
+
+	child, err := buildConnectionTree(a, b, o)
+	if err != nil {
+		return nil, errors.Annotate(err, "could not build connection tree")
+	}
+	return &TreeModel{
+		Type:    GraphTypeConnection,
+		Root:    a,
+		Child:   child,
+		Options: o,
+	}, nil
+}
+
+// ConnectionProbandLevel returns the proband level of the connection graph of a and b. The common ancestors are the root
+// of the graph and a is some levels below, levels of ancestors are higher.
+func ConnectionProbandLevel(a, b Person) (int, error) {
+	r, ok, err := findBloodRelationship(a, b)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.Errorf("no common ancestor found for %s and %s", a.GetBestID(), b.GetBestID())
+	}
+	return r.Up, nil
+}
+
+func renderConnectionTree(a, b Person, o RenderTreeOptions) ([]byte, error) {
+	n, err := buildConnectionTree(a, b, o)
+	if err != nil {
+		return []byte{}, err
+	}
+	return n.genealogytree(o, true)
+}
+
+func buildConnectionTree(a, b Person, o RenderTreeOptions) (*ChildNode, error) {
+	r, ok, err := findBloodRelationship(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("no common ancestor found for %s and %s", a.GetBestID(), b.GetBestID())
+	}
+
+	// both lines start at the common ancestor
+	lineA := make([]Person, 0, r.Up+1)
+	for i := r.Up; i >= 0; i-- {
+		lineA = append(lineA, r.Persons[i])
+	}
+	lineB := r.Persons[r.Up:]
+
+	if !o.RenderPersonOptions.HideRootNodeHighlighting {
+		a.AddAttribute("rootnode")
+		b.AddAttribute("rootnode")
+	}
+	c := connectionBuilder{
+		options: o,
+		lines:   [][]Person{lineA[1:], lineB[1:]},
+		up:      r.Up,
+	}
+
+	partners := append([]Person{}, r.CommonAncestors[1:]...)
+	for _, line := range c.lines {
+		if len(line) == 0 {
+			continue
+		}
+		otherParent, err := getOtherParent(line[0], r.CommonAncestors[0])
+		if err != nil {
+			return nil, err
+		}
+		partners = appendPersonOnce(partners, otherParent)
+	}
+	return c.family(r.CommonAncestors[0], partners, 0)
+}
+
+type connectionBuilder struct {
+	options RenderTreeOptions
+	// lines are the persons below the common ancestor down to each of the probands
+	lines [][]Person
+	// up is the number of generations from the first proband to the common ancestor
+	up int
+}
+
+// family builds person with the children of each of the partners, depth is the number of generations below the
+// common ancestor
+func (c connectionBuilder) family(person Person, partners []Person, depth int) (*ChildNode, error) {
+	opts := *c.options.RenderPersonOptions
+	opts.NodeType = NodeTypeG
+	n := &ChildNode{}
+	n.G = PersonNode{Person: person, Options: *opts.HideImageByLevel(c.options, c.up-depth)}
+	if !c.options.HideFamilyIDs {
+		n.ID = "family-" + person.GetBestID()
+	}
+	if len(partners) == 0 {
+		partners = []Person{NewDummyFlatPerson()}
+	}
+
+	for i, partner := range partners {
+		u, err := c.union(person, partner, depth)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			n.Partner = u.Partner
+			n.Children = u.Children
+			n.EdgeStyle = u.EdgeStyle
+			continue
+		}
+		n.Unions = append(n.Unions, u)
+	}
+	return n, nil
+}
+
+// union builds the partner and the children of person with partner that are on one of the lines or siblings of them
+// if requested
+func (c connectionBuilder) union(person, partner Person, depth int) (*UnionNode, error) {
+	u := &UnionNode{}
+	if !partner.IsDummy() {
+		opts := *c.options.RenderPersonOptions
+		opts.NodeType = NodeTypeP
+		u.Partner = &PersonNode{Person: partner, Options: *opts.HideImageByLevel(c.options, c.up-depth)}
+		if !c.options.HideFamilyIDs {
+			u.ID = "family-" + partner.GetBestID()
+		}
+	}
+
+	children, err := person.GetChildrenWith(partner)
+	if err != nil {
+		return nil, err
+	}
+	parents := []PersonNode{{Person: person}}
+	if u.Partner != nil {
+		parents = append(parents, *u.Partner)
+	}
+	var childNodes []PersonNode
+	for _, child := range children.GetPersons() {
+		line := c.getLine(child, depth)
+		if line == nil && (!c.options.ShowConnectionSiblings || isPersonIgnored(child, c.options)) {
+			continue
+		}
+		if len(line) > 1 {
+			otherParent, err := getOtherParent(line[1], child)
+			if err != nil {
+				return nil, err
+			}
+			childNode, err := c.family(child, []Person{otherParent}, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			childNode.G.Options.Links = parentNodeLinks(childNode.G, parents...)
+			u.Children = append(u.Children, childNode)
+			childNodes = append(childNodes, childNode.G)
+			continue
+		}
+		opts := *c.options.RenderPersonOptions
+		opts.NodeType = NodeTypeC
+		g := PersonNode{Person: child, Options: *opts.HideImageByLevel(c.options, c.up-depth-1)}
+		g.Options.Links = parentNodeLinks(g, parents...)
+		u.Children = append(u.Children, &ChildNode{
+			FamilyNode: FamilyNode{G: g},
+		})
+		childNodes = append(childNodes, g)
+	}
+	u.EdgeStyle = familyEdgeStyle(childNodes, parents)
+	return u, nil
+}
+
+// getLine returns the rest of the line starting with child if it is part of one at the given depth
+func (c connectionBuilder) getLine(child Person, depth int) []Person {
+	for _, line := range c.lines {
+		if depth < len(line) && line[depth] == child {
+			return line[depth:]
+		}
+	}
+	return nil
+}
+
+// getOtherParent returns the biological parent of child that is not parent, a dummy if it is unknown
+func getOtherParent(child, parent Person) (Person, error) {
+	mom, err := child.GetMom()
+	if err != nil {
+		return nil, err
+	}
+	dad, err := child.GetDad()
+	if err != nil {
+		return nil, err
+	}
+	if parent.MatchesIDUUID(mom.GetID(), mom.GetUUID()) {
+		return dad, nil
+	}
+	return mom, nil
+}
+
+func appendPersonOnce(persons []Person, p Person) []Person {
+	for _, existing := range persons {
+		if p.IsDummy() && existing.IsDummy() || !p.IsDummy() && existing.MatchesIDUUID(p.GetID(), p.GetUUID()) {
+			return persons
+		}
+	}
+	return append(persons, p)
+}
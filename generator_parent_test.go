@@ -25,6 +25,7 @@ func TestRenderFullParentTree(t *testing.T) {
 		Headless             bool
 		GenderOrder          GenderOrder
 		MaxParentGenerations int
+		ParentLinkTypes      []ParentLinkType
 		Expected             string
 	}{
 		// alone
@@ -115,6 +116,29 @@ g[id=gauss,]{}
 }`,
 		},
 
+		// parent link types
+		{
+			Name:             "adoption-biological",
+			DatabaseFilename: "adoption",
+			ID:               "gauss",
+			Expected: `parent{
+g[id=gauss,]{}
+p[id=papa,]{}
+p[id=mama,]{}
+}`,
+		},
+		{
+			Name:             "adoption-adoptive",
+			DatabaseFilename: "adoption",
+			ID:               "gauss",
+			ParentLinkTypes:  []ParentLinkType{ParentLinkTypeAdoptive},
+			Expected: `parent[edges={foreground={link-adoptive}}]{
+g[id=gauss,]{}
+p[id=adoptiv-papa,]{}
+p[id=adoptiv-mama,]{}
+}`,
+		},
+
 		// headless tests
 		{
 			DatabaseFilename: "parents",
@@ -171,6 +195,7 @@ p[id=papa,]{}
 		assert.Nil(t, err)
 		renderOptions.MaxParentGenerations = test.MaxParentGenerations
 		renderOptions.GenderOrder = 0
+		renderOptions.ParentLinkTypes = test.ParentLinkTypes
 		if test.GenderOrder != 0 {
 			renderOptions.GenderOrder = test.GenderOrder
 		}
@@ -188,3 +213,19 @@ p[id=papa,]{}
 		}
 	}
 }
+
+func TestRenderParentTreeCycle(t *testing.T) {
+	renderOptions := RenderTreeOptions{
+		GraphType: GraphTypeParent,
+	}
+	addTestTemplates(&renderOptions)
+	renderOptions.SetDefaults()
+
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "validate", "cycle.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("papa")
+	assert.Nil(t, err)
+	_, err = renderFullParentTree(person, renderOptions, false)
+	assert.EqualError(t, err, "cycle in ancestry: papa → opa → gauss → papa")
+}
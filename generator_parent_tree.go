@@ -7,35 +7,60 @@ import (
 )
 
 func renderFullParentTree(p Person, o RenderTreeOptions, headless bool) ([]byte, error) {
-	return renderParentTree(p, o, NodeTypeG, 0, headless)
+	n, err := buildFullParentTree(p, o, headless)
+	if err != nil {
+		return []byte{}, err
+	}
+	return n.genealogytree(o, true)
 }
 
-func renderParentTree(p Person, o RenderTreeOptions, baseNodeType NodeType, level int, headless bool) ([]byte, error) {
+func buildFullParentTree(p Person, o RenderTreeOptions, headless bool) (*ParentNode, error) {
+	return buildParentTree(p, o, NodeTypeG, 0, 1, headless, nil)
+}
+
+// buildParentTree builds p and its ancestors, kekule is the Kekulé number of p. The result is nil if p is ignored or
+// unknown.
+func buildParentTree(p Person, o RenderTreeOptions, baseNodeType NodeType, level, kekule int, headless bool, path ancestryPath) (*ParentNode, error) {
 	// ignored?
 	if isPersonIgnored(p, o) {
-		return []byte{}, nil
+		return nil, nil
 	}
 	if p.IsDummy() {
-		return []byte{}, nil
+		return nil, nil
+	}
+	path, err := path.with(p)
+	if err != nil {
+		return nil, err
 	}
 
-	data := struct {
-		FamilyID        string
-		G               string
-		Parents         string
-		SiblingsYounger string
-		SiblingsOlder   string
-	}{}
+	// the root of sandclock graphs is part of the child tree
+	gOpts := *o.RenderPersonOptions
+	implex := false
+	if !headless || level > 0 {
+		gOpts, implex = o.Implex.visit(p, gOpts)
+	}
+	if o.ShowKekule {
+		gOpts.Kekule = kekule
+	}
+	n := &ParentNode{
+		Headless: headless,
+	}
+	// persons already in the tree are shown once only, without their ancestors
+	if implex {
+		gOpts.NodeType = baseNodeType
+		n.G = PersonNode{Person: p, Options: *gOpts.HideImageByLevel(o, level)}
+		return n, nil
+	}
 
-	// render parents
+	// parents
 	if level < o.MaxParentGenerations {
-		mom, err := p.GetMom()
+		mom, err := p.GetMomByType(o.ParentLinkTypes...)
 		if err != nil {
-			return []byte{}, err
+			return nil, err
 		}
-		dad, err := p.GetDad()
+		dad, err := p.GetDadByType(o.ParentLinkTypes...)
 		if err != nil {
-			return []byte{}, err
+			return nil, err
 		}
 		var parents []Person
 
@@ -45,33 +70,32 @@ func renderParentTree(p Person, o RenderTreeOptions, baseNodeType NodeType, leve
 		case GenderOrderMaleFirst:
 			parents = []Person{dad, mom}
 		}
-		var buffer bytes.Buffer
 		for _, parent := range parents {
 			if parent.IsDummy() {
 				continue
 			}
 			// recursive call
-			parentData, err := renderParentTree(parent, o, NodeTypeP, level+1, false)
+			parentNode, err := buildParentTree(parent, o, NodeTypeP, level+1, kekuleParent(kekule, parent == mom), false, path)
 			if err != nil {
 				return nil, err
 			}
-			buffer.Write(parentData)
-			buffer.WriteString("\n")
+			if parentNode != nil {
+				n.Parents = append(n.Parents, parentNode)
+			}
 		}
-		data.Parents = buffer.String()
 
-		// render siblings
-		if level <= o.MaxParentSiblingsGenerations {
+		// siblings, not shown for headless families and leafs
+		if level <= o.MaxParentSiblingsGenerations && !headless && (level == 0 || !n.IsLeaf()) {
 			var siblings PersonList
 			if !mom.IsDummy() {
-				siblings, err = mom.GetChildrenWith(dad)
+				siblings, err = mom.GetChildrenWithByType(dad, o.ParentLinkTypes...)
 				if err != nil {
-					return []byte{}, err
+					return nil, err
 				}
 			} else if !dad.IsDummy() {
-				siblings, err = dad.GetChildrenWith(NewDummyFlatPerson())
+				siblings, err = dad.GetChildrenWithByType(NewDummyFlatPerson(), o.ParentLinkTypes...)
 				if err != nil {
-					return []byte{}, err
+					return nil, err
 				}
 			}
 
@@ -82,53 +106,76 @@ func renderParentTree(p Person, o RenderTreeOptions, baseNodeType NodeType, leve
 			opts := *o.RenderPersonOptions
 			opts.NodeType = NodeTypeC
 			opts = *opts.HideImageByLevel(o, level)
-			siblingsOlder, err := renderPersonSlice(older, opts)
-			if err != nil {
-				return []byte{}, err
-			}
-			siblingsYounger, err := renderPersonSlice(younger, opts)
-			if err != nil {
-				return []byte{}, err
-			}
-			data.SiblingsOlder = siblingsOlder
-			data.SiblingsYounger = siblingsYounger
+			n.SiblingsOlder = buildPersonNodes(older, opts, o)
+			n.SiblingsYounger = buildPersonNodes(younger, opts, o)
 		}
 	}
 
-	// render g node for anchor
-	mainNodeType := baseNodeType
-	if data.Parents != "" {
-		mainNodeType = NodeTypeG
-	}
-	opts := *o.RenderPersonOptions
-	opts.NodeType = mainNodeType
-	opts = *opts.HideImageByLevel(o, level)
-	g, err := renderPerson(p, opts)
-	if err != nil {
-		return nil, errors.Annotatef(err, "could not render g node for %s", p)
+	// g node for anchor
+	gOpts.NodeType = baseNodeType
+	if !n.IsLeaf() {
+		gOpts.NodeType = NodeTypeG
 	}
-	data.G = string(g)
+	n.G = PersonNode{Person: p, Options: *gOpts.HideImageByLevel(o, level)}
+	parentNodes := n.parentNodes()
+	n.G.Options.Links = parentNodeLinks(n.G, parentNodes...)
+	linkParents(n.SiblingsOlder, parentNodes)
+	linkParents(n.SiblingsYounger, parentNodes)
+	children := append(append([]PersonNode{n.G}, n.SiblingsOlder...), n.SiblingsYounger...)
+	n.EdgeStyle = familyEdgeStyle(children, parentNodes)
 	if !o.HideFamilyIDs {
-		var id string
-		if pID := p.GetID(); pID != "" {
-			id = pID
-		} else {
-			if pUUID := p.GetUUID(); pUUID != "" {
-				id = pUUID
-			}
-		}
-		data.FamilyID = "family-" + id
+		n.ID = "family-" + p.GetBestID()
+	}
+	return n, nil
+}
+
+// genealogytree renders the node, leafs are rendered as single person nodes unless they are the root of the tree
+func (n *ParentNode) genealogytree(o RenderTreeOptions, root bool) ([]byte, error) {
+	if n == nil {
+		return []byte{}, nil
+	}
+	g, err := n.G.genealogytree()
+	if err != nil {
+		return nil, errors.Annotatef(err, "could not render g node for %s", n.G.Person)
+	}
+	if n.IsLeaf() && !root {
+		return g, nil
 	}
 
-	if data.Parents == "" && level > 0 {
-		return []byte(data.G), nil
+	data := struct {
+		FamilyID        string
+		EdgeStyle       string
+		G               LaTeX
+		Parents         LaTeX
+		SiblingsYounger LaTeX
+		SiblingsOlder   LaTeX
+	}{
+		FamilyID:  n.ID,
+		EdgeStyle: n.EdgeStyle,
+		G:         LaTeX(g),
+	}
+	var buffer bytes.Buffer
+	for _, parent := range n.Parents {
+		parentData, err := parent.genealogytree(o, false)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(parentData)
+		buffer.WriteString("\n")
+	}
+	data.Parents = LaTeX(buffer.String())
+	data.SiblingsOlder, err = renderPersonNodes(n.SiblingsOlder)
+	if err != nil {
+		return []byte{}, err
+	}
+	data.SiblingsYounger, err = renderPersonNodes(n.SiblingsYounger)
+	if err != nil {
+		return []byte{}, err
 	}
 
-	var templateFile string
-	if headless {
+	templateFile := o.TemplateFilenameParentTree
+	if n.Headless {
 		templateFile = o.TemplateFilenameParentTreeHeadless
-	} else {
-		templateFile = o.TemplateFilenameParentTree
 	}
 	result, err := RenderTemplateFile(templateFile, data)
 	if err != nil {
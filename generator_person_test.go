@@ -3,6 +3,7 @@ package generations
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,7 +39,7 @@ func TestRenderPerson(t *testing.T) {
 		Death:         DatePlace{Date: "um 1900"},
 		Burial:        DatePlace{Place: "Hannover Hauptfriedhof"},
 		Floruit:       "Hannover, Berlin",
-		Jobs:          "Mathematician, Priest",
+		Jobs:          Jobs{{Title: "Mathematician"}, {Title: "Priest"}},
 		ImageFilename: "images/gauss.jpg",
 		Comment:       "Famous.",
 		Partners: []FlatRelationship{
@@ -107,12 +108,14 @@ func TestRenderPerson(t *testing.T) {
 				sex = male,
 				name = { \pref{Johann}\ \middlename{Carl}\ \middlename{Friedrich}\ \surn{Gauss} \surnbirth{Hauser}\ },
 				birth = {1827}{Hannover},
-				baptism = {10.10.1827}{Hannover},
-				death- = {um 1900},
+				baptism = {1827-10-10}{Hannover},
+				death- = {ca1900},
+				deathage = {\textasciitilde{}72–73},
 				burial = {-}{Hannover Hauptfriedhof},
-				engagement = {10/1854}{Prag},
+				engagement = {1854-10}{Prag},
 				marriage = {1855}{München},
-				divorce = {um 1866}{ebd.},
+				marriageage = {27–28},
+				divorce = {ca1866}{ebd.},
 				floruit = {-}{Hannover, Berlin},
 				profession = {Mathematician, Priest},
 				image = {images/gauss.jpg},
@@ -144,12 +147,48 @@ func TestRenderPerson(t *testing.T) {
 			Expected: `g[dead]{
 				birth- = {1821},
 				baptism- = {},
-				death- = {um 1842},
+				death- = {ca1842},
+				deathage = {\textasciitilde{}20–21},
 				engagement- = {1839},
 				marriage- = {},
 				divorce- = {},
 			}`,
 		},
+		{
+			Name:          "Sources",
+			RenderOptions: defaultRenderOptions,
+			Person: &FlatPerson{
+				Sources: []Reference{{SourceID: "chronik"}},
+				Birth:   DatePlace{Date: "1821", Sources: []Reference{{SourceID: "kb", Page: "12"}, {SourceID: "chronik"}}},
+			},
+			Expected: `g[]{
+				birth- = {1821},
+				sources = {chronik,kb},
+			}`,
+		},
+		{
+			Name: "Residences and current job",
+			RenderOptions: RenderPersonOptions{
+				TemplateFilename: defaultTemplate,
+				NodeType:         NodeTypeG,
+				CurrentJobOnly:   true,
+				Date:             time.Date(1810, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			Person: &FlatPerson{
+				Jobs: Jobs{
+					{Title: "Landvermesser", Period: Period{To: "1807"}},
+					{Title: "Professor", Period: Period{From: "1807"}},
+				},
+				Residences: Residences{
+					{Place: "Braunschweig", Period: Period{To: "1807"}},
+					{Place: "Göttingen", Period: Period{From: "1807", To: "1855"}},
+				},
+			},
+			Expected: `g[]{
+				floruit = {1807/1855}{Braunschweig, Göttingen},
+				profession = {Professor},
+			}`,
+		},
 		{
 			Name:          "Filter HideMiddleNames",
 			RenderOptions: hiddenMiddleNamesRenderPersonOptions,
@@ -162,6 +201,16 @@ func TestRenderPerson(t *testing.T) {
 				},
 			}`,
 		},
+		{
+			Name:          "name order and particle",
+			RenderOptions: defaultRenderOptions,
+			Person: &FlatPerson{
+				Name: Name{Title: "Dr.", First: []string{"Béla"}, Particle: "de", Last: "Bartók", Birth: "Kovács", Order: NameOrderSurnameGiven},
+			},
+			Expected: `g[]{
+				name = { \titlename{Dr.}\ de\ \surn{Bartók}\ \pref{Béla} \surnbirth{Kovács}\ },
+			}`,
+		},
 	}
 
 	for i, test := range tests {
@@ -22,6 +22,7 @@ func TestRenderGenealogytree(t *testing.T) {
 		Name                 string
 		DatabaseFilename     string
 		ID                   string
+		GraphType            GraphType
 		MaxParentGenerations int
 		MaxChildGenerations  int
 		Expected             string
@@ -63,6 +64,49 @@ func TestRenderGenealogytree(t *testing.T) {
 	child {
 		g[id=gauss,]{}
 	}
+}`,
+		},
+		// parent graph
+		{
+			Name:             "parent graph alone",
+			DatabaseFilename: "single",
+			ID:               "gauss",
+			GraphType:        GraphTypeParent,
+			Expected:         `parent{g[id=gauss,]{}}`,
+		},
+		{
+			Name:             "parent graph grandparents",
+			DatabaseFilename: "grandparents",
+			ID:               "gauss",
+			GraphType:        GraphTypeParent,
+			Expected: `parent{
+	g[id=gauss,]{}
+	parent{
+		g[id=papa,]{}
+		p[id=opa,]{}
+		p[id=oma,]{}
+	}
+	p[id=mama,]{}
+}`,
+		},
+		// child graph
+		{
+			Name:             "child graph alone",
+			DatabaseFilename: "single",
+			ID:               "gauss",
+			GraphType:        GraphTypeChild,
+			Expected:         `child{g[id=gauss,]{}}`,
+		},
+		{
+			Name:             "child graph children",
+			DatabaseFilename: "children",
+			ID:               "gauss",
+			GraphType:        GraphTypeChild,
+			Expected: `child{
+	g[id=gauss,]{}
+	p[id=frau-gauss,]{uuid=frau-gauss,}
+	c[id=sohn,]{}
+	c[id=tochter,]{}
 }`,
 		},
 	}
@@ -76,6 +120,7 @@ func TestRenderGenealogytree(t *testing.T) {
 		assert.Nil(t, err, test.Name)
 		person, err := database.GetByID(test.ID)
 		assert.Nil(t, err, test.Name)
+		renderOptions.GraphType = test.GraphType
 		renderOptions.MaxParentGenerations = test.MaxParentGenerations
 		renderOptions.MaxChildGenerations = test.MaxChildGenerations
 		result, err := RenderGenealogytree(person, renderOptions)
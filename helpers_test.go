@@ -32,6 +32,8 @@ func semantic(input string) string {
 
 func addTestTemplates(o *RenderTreeOptions) {
 	o.TemplateFilenameTree = "cmd/templates/tree.tpl"
+	o.TemplateFilenameTreeParent = "cmd/templates/tree_parent.tpl"
+	o.TemplateFilenameTreeChild = "cmd/templates/tree_child.tpl"
 	o.TemplateFilenamePerson = "cmd/templates/person.tpl"
 	o.TemplateFilenameParentTree = "cmd/templates/parent_tree.tpl"
 	o.TemplateFilenameParentTreeHeadless = "cmd/templates/parent_tree_headless.tpl"
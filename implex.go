@@ -0,0 +1,68 @@
+package generations
+
+import "fmt"
+
+// ImplexStatistics collects the persons appearing several times in a tree (pedigree collapse)
+type ImplexStatistics struct {
+	// Nodes is the number of person nodes in the tree
+	Nodes int
+	// Repeated are the persons appearing several times in order of their first repetition
+	Repeated []ImplexPerson
+
+	// seen maps the IDs of persons in the tree to their index in Repeated, -1 if they appeared once only
+	seen map[string]int
+}
+
+// ImplexPerson is a person appearing several times in a tree
+type ImplexPerson struct {
+	Person Person
+	// ID is the genealogytree id of the full node, the reference nodes use ID-implex-2, ID-implex-3 and so on
+	ID          string
+	Occurrences int
+}
+
+// Persons returns the number of distinct persons in the tree
+func (s *ImplexStatistics) Persons() int {
+	return len(s.seen)
+}
+
+// Percentage returns the share of nodes referencing a person already in the tree
+func (s *ImplexStatistics) Percentage() float64 {
+	if s.Nodes == 0 {
+		return 0
+	}
+	return 100 * float64(s.Nodes-s.Persons()) / float64(s.Nodes)
+}
+
+func (s *ImplexStatistics) reset() {
+	*s = ImplexStatistics{}
+}
+
+// visit records a node for p and returns the options to render it with, compact ones referencing the first node if p
+// is in the tree already. Nothing is recorded if s is nil.
+func (s *ImplexStatistics) visit(p Person, opts RenderPersonOptions) (RenderPersonOptions, bool) {
+	id := p.GetBestID()
+	if s == nil || id == "" || p.IsDummy() {
+		return opts, false
+	}
+	if s.seen == nil {
+		s.seen = make(map[string]int)
+	}
+	s.Nodes++
+	index, ok := s.seen[id]
+	if !ok {
+		s.seen[id] = -1
+		return opts, false
+	}
+	if index < 0 {
+		index = len(s.Repeated)
+		s.seen[id] = index
+		s.Repeated = append(s.Repeated, ImplexPerson{Person: p, ID: id, Occurrences: 1})
+	}
+	s.Repeated[index].Occurrences++
+
+	opts.Compact()
+	opts.ImplexOf = id
+	opts.ImplexID = fmt.Sprintf("%s-implex-%d", id, s.Repeated[index].Occurrences)
+	return opts, true
+}
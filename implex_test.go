@@ -0,0 +1,116 @@
+package generations
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImplex(t *testing.T) {
+	renderOptions := RenderTreeOptions{
+		GenderOrder:   GenderOrderMaleFirst,
+		HideFamilyIDs: true,
+		// siblings would be repeated persons themselves
+		MaxParentSiblingsGenerations: -1,
+	}
+	addTestTemplates(&renderOptions)
+	renderOptions.SetDefaults()
+	renderOptions.RenderPersonOptions.HideAllData()
+	renderOptions.RenderPersonOptions.HideAttributes = nil
+	renderOptions.RenderPersonOptions.HideRootNodeHighlighting = true
+
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "implex.yml"))
+	assert.Nil(t, err)
+
+	// parent tree
+	person, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+	renderOptions.Implex = &ImplexStatistics{}
+	result, err := renderFullParentTree(person, renderOptions, false)
+	assert.Nil(t, err)
+	assertOutputSemantic(t, `parent{
+		g[id=gauss,]{}
+		parent{
+			g[id=vater,]{}
+			parent{
+				g[id=grossvater,]{}
+				p[id=urgrossvater,]{}
+				p[id=urgrossmutter,]{}
+			}
+		}
+		parent{
+			g[id=mutter,]{}
+			parent{
+				g[id=grossmutter,]{}
+				p[id=urgrossvater-implex-2,implex]{}
+				p[id=urgrossmutter-implex-2,implex]{}
+			}
+		}
+	}`, string(result), "parent tree")
+	assert.Equal(t, 9, renderOptions.Implex.Nodes)
+	assert.Equal(t, 7, renderOptions.Implex.Persons())
+	assert.InDelta(t, 22.2, renderOptions.Implex.Percentage(), 0.1)
+	assert.Equal(t, []string{"urgrossvater", "urgrossmutter"}, implexIDs(renderOptions.Implex))
+
+	// the reference nodes link to the nodes of the persons
+	renderOptions.Implex = &ImplexStatistics{}
+	model, err := BuildTree(person, renderOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, []NodeLink{
+		{From: "urgrossvater-implex-2", To: "urgrossvater", Style: "link-implex"},
+		{From: "urgrossmutter-implex-2", To: "urgrossmutter", Style: "link-implex"},
+	}, model.Links())
+
+	// child tree
+	person, err = database.GetByID("urgrossvater")
+	assert.Nil(t, err)
+	renderOptions.Implex = &ImplexStatistics{}
+	result, err = renderFullChildTree(person, renderOptions)
+	assert.Nil(t, err)
+	assertOutputSemantic(t, `child{
+		g[id=urgrossvater,]{}
+		p[id=urgrossmutter,]{}
+		child{
+			g[id=grossvater,]{}
+			child{
+				g[id=vater,]{}
+				p[id=mutter,]{}
+				c[id=gauss,]{}
+			}
+		}
+		child{
+			g[id=grossmutter,]{}
+			c[id=mutter-implex-2,implex]{}
+		}
+	}`, string(result), "child tree")
+	assert.Equal(t, []string{"mutter"}, implexIDs(renderOptions.Implex))
+
+	// sandclock graph, siblings of the root are no repeated persons
+	person, err = database.GetByID("grossvater")
+	assert.Nil(t, err)
+	renderOptions.Implex = &ImplexStatistics{}
+	renderOptions.MaxParentSiblingsGenerations = 0
+	renderOptions.GraphType = GraphTypeSandclock
+	_, err = BuildTree(person, renderOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, renderOptions.Implex.Nodes)
+	assert.Empty(t, implexIDs(renderOptions.Implex))
+	renderOptions.MaxParentSiblingsGenerations = -1
+
+	// detection can be disabled
+	renderOptions.Implex = &ImplexStatistics{}
+	renderOptions.HideImplex = true
+	_, err = RenderGenealogytree(person, renderOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, renderOptions.Implex.Nodes)
+}
+
+func implexIDs(s *ImplexStatistics) []string {
+	result := make([]string, len(s.Repeated))
+	for i, r := range s.Repeated {
+		result[i] = r.ID
+	}
+	return result
+}
@@ -0,0 +1,10 @@
+package generations
+
+//go:generate go-enum -f=language.go --marshal
+
+// Language selects the language of generated texts
+/* ENUM(
+en = 1
+de
+*/
+type Language int
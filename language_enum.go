@@ -0,0 +1,59 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// LanguageEn is a Language of type En
+	LanguageEn Language = iota + 1
+	// LanguageDe is a Language of type De
+	LanguageDe
+)
+
+const _LanguageName = "ende"
+
+var _LanguageMap = map[Language]string{
+	1: _LanguageName[0:2],
+	2: _LanguageName[2:4],
+}
+
+// String implements the Stringer interface.
+func (x Language) String() string {
+	if str, ok := _LanguageMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("Language(%d)", x)
+}
+
+var _LanguageValue = map[string]Language{
+	_LanguageName[0:2]: 1,
+	_LanguageName[2:4]: 2,
+}
+
+// ParseLanguage attempts to convert a string to a Language
+func ParseLanguage(name string) (Language, error) {
+	if x, ok := _LanguageValue[name]; ok {
+		return x, nil
+	}
+	return Language(0), fmt.Errorf("%s is not a valid Language", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x Language) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *Language) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseLanguage(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
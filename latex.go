@@ -0,0 +1,101 @@
+package generations
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// LaTeX is code that is written to LaTeX templates as it is, all other values are escaped
+type LaTeX string
+
+// names of the escaping functions in templates
+const (
+	latexEscapeFunc = "escape"
+	latexRawFunc    = "raw"
+)
+
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`^`, `\textasciicircum{}`,
+	`~`, `\textasciitilde{}`,
+)
+
+// EscapeLaTeX returns s as LaTeX that prints s, characters without special meaning (including Unicode) are kept
+func EscapeLaTeX(s string) LaTeX {
+	return LaTeX(latexReplacer.Replace(s))
+}
+
+// escapeLaTeXValue formats a template value as LaTeX, values of type LaTeX are not escaped
+func escapeLaTeXValue(value interface{}) LaTeX {
+	switch v := value.(type) {
+	case LaTeX:
+		return v
+	case string:
+		return EscapeLaTeX(v)
+	case nil:
+		return ""
+	}
+	return EscapeLaTeX(fmt.Sprint(value))
+}
+
+// rawLaTeX marks a template value as LaTeX so that it is not escaped, e.g. custom styles from the config
+func rawLaTeX(value interface{}) LaTeX {
+	switch v := value.(type) {
+	case LaTeX:
+		return v
+	case string:
+		return LaTeX(v)
+	case nil:
+		return ""
+	}
+	return LaTeX(fmt.Sprint(value))
+}
+
+// escapeTemplate makes all actions of t that print a value escape it for LaTeX
+func escapeTemplate(t *template.Template) {
+	for _, tpl := range t.Templates() {
+		if tpl.Tree != nil {
+			escapeNode(tpl.Tree.Root)
+		}
+	}
+}
+
+func escapeNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeNode(child)
+		}
+	case *parse.ActionNode:
+		// declarations and assignments print nothing
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(latexEscapeFunc).SetTree(nil).SetPos(n.Pos)},
+		})
+	case *parse.IfNode:
+		escapeNode(n.List)
+		escapeNode(n.ElseList)
+	case *parse.RangeNode:
+		escapeNode(n.List)
+		escapeNode(n.ElseList)
+	case *parse.WithNode:
+		escapeNode(n.List)
+		escapeNode(n.ElseList)
+	}
+}
@@ -0,0 +1,119 @@
+package generations
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLaTeX(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected LaTeX
+	}{
+		{Input: "", Expected: ""},
+		{Input: "Gauss", Expected: "Gauss"},
+		{Input: `\`, Expected: `\textbackslash{}`},
+		{Input: "{", Expected: `\{`},
+		{Input: "}", Expected: `\}`},
+		{Input: "$", Expected: `\$`},
+		{Input: "&", Expected: `\&`},
+		{Input: "#", Expected: `\#`},
+		{Input: "%", Expected: `\%`},
+		{Input: "_", Expected: `\_`},
+		{Input: "^", Expected: `\textasciicircum{}`},
+		{Input: "~", Expected: `\textasciitilde{}`},
+		{Input: "Smith & Sons", Expected: `Smith \& Sons`},
+		{Input: `\input{/etc/passwd}`, Expected: `\textbackslash{}input\{/etc/passwd\}`},
+		{Input: "100% of $5_000 #1 ^~", Expected: `100\% of \$5\_000 \#1 \textasciicircum{}\textasciitilde{}`},
+		{Input: "Müller-Lüdenscheidt, Straße", Expected: "Müller-Lüdenscheidt, Straße"},
+		{Input: "Αθήνα, 東京, Łódź – “quoted”", Expected: "Αθήνα, 東京, Łódź – “quoted”"},
+		{Input: "née Brontë ✝", Expected: "née Brontë ✝"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, EscapeLaTeX(test.Input), test.Input)
+	}
+}
+
+func TestRenderTemplateFileEscaping(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Template string
+		Data     interface{}
+		Expected string
+	}{
+		{
+			Name:     "strings are escaped",
+			Template: `\surn{ {{- . -}} }`,
+			Data:     "Smith & Sons 100%",
+			Expected: `\surn{Smith \& Sons 100\%}`,
+		},
+		{
+			Name:     "LaTeX is not escaped",
+			Template: `{{ . }}`,
+			Data:     LaTeX(`\textbf{bold}`),
+			Expected: `\textbf{bold}`,
+		},
+		{
+			Name:     "raw is not escaped",
+			Template: `{{ raw . }}, {{ . | raw }}`,
+			Data:     `\gtrset{show id}`,
+			Expected: `\gtrset{show id}, \gtrset{show id}`,
+		},
+		{
+			Name:     "other values are formatted and escaped",
+			Template: `{{ .Number }} {{ .Date }} {{ .Gender }}`,
+			Data: struct {
+				Number int
+				Date   Date
+				Gender Gender
+			}{Number: 42, Date: "~1850", Gender: GenderFemale},
+			Expected: `42 \textasciitilde{}1850 female`,
+		},
+		{
+			Name:     "nested actions are escaped",
+			Template: `{{ range . }}{{ if . }}{{ with . }}{{ . }};{{ end }}{{ else }}-{{ end }}{{ end }}`,
+			Data:     []string{"a_b", "", `\input{x}`},
+			Expected: `a\_b;-\textbackslash{}input\{x\};`,
+		},
+		{
+			Name:     "variables are escaped when printed",
+			Template: `{{ $name := . }}{{ $name = printf "%s$" $name }}{{ $name }}`,
+			Data:     "#1",
+			Expected: `\#1\$`,
+		},
+		{
+			Name:     "escaping is idempotent",
+			Template: `{{ escape . }}`,
+			Data:     "&",
+			Expected: `\&`,
+		},
+		{
+			Name:     "defined templates are escaped",
+			Template: `{{ define "name" }}{{ . }}{{ end }}{{ template "name" . }}`,
+			Data:     "{}",
+			Expected: `\{\}`,
+		},
+		{
+			Name:     "genealogytree dates are not escaped",
+			Template: `birth- = { {{- .Genealogytree -}} }`,
+			Data:     Date("between 1850 and 1855"),
+			Expected: `birth- = {(1850~1855)}`,
+		},
+	}
+
+	for _, test := range tests {
+		tempFile, err := ioutil.TempFile("", "generations-test-")
+		assert.Nil(t, err)
+		tempFile.WriteString(test.Template)
+		tempFile.Close()
+
+		result, err := RenderTemplateFile(tempFile.Name(), test.Data)
+		os.Remove(tempFile.Name())
+		assert.Nil(t, err, test.Name)
+		assert.Equal(t, test.Expected, string(result), test.Name)
+	}
+}
@@ -0,0 +1,189 @@
+package generations
+
+import (
+	"strings"
+	"time"
+)
+
+//go:generate go-enum -f=life_event.go --marshal
+
+/* ENUM(
+education = 1
+military
+emigration
+event
+*/
+type BiographyElementType int
+
+// Period is a time span given by two (possibly fuzzy) dates, both of which are optional
+type Period struct {
+	From Date `yaml:"from,omitempty"`
+	To   Date `yaml:"to,omitempty"`
+}
+
+// Empty returns true iff neither beginning nor end of the period are known
+func (p Period) Empty() bool {
+	return p.From.Empty() && p.To.Empty()
+}
+
+// Contains returns true iff the period could include the given point of time. Unknown bounds are treated as open.
+func (p Period) Contains(t time.Time) bool {
+	if from, err := p.From.Parse(); err == nil {
+		if earliest, ok := from.Earliest(); ok && t.Before(earliest) {
+			return false
+		}
+	}
+	if to, err := p.To.Parse(); err == nil {
+		if latest, ok := to.Latest(); ok && t.After(latest) {
+			return false
+		}
+	}
+	return true
+}
+
+// Genealogytree formats the period in the date syntax of the genealogytree LaTeX package (e.g. 1850/1860)
+func (p Period) Genealogytree() LaTeX {
+	if p.Empty() {
+		return ""
+	}
+	return p.From.Genealogytree() + "/" + p.To.Genealogytree()
+}
+
+// String formats the period for display (e.g. 1850–1860), the beginning only if the end is unknown or the same (as for
+// single events)
+func (p Period) String() string {
+	switch {
+	case p.Empty():
+		return ""
+	case p.From == p.To, p.To.Empty():
+		return string(p.From)
+	}
+	return string(p.From) + "–" + string(p.To)
+}
+
+// Job is an occupation of a person
+type Job struct {
+	Period `yaml:",inline"`
+
+	Title   string      `yaml:"title,omitempty"`
+	Place   string      `yaml:"place,omitempty"`
+	Sources []Reference `yaml:"sources,omitempty"`
+}
+
+// UnmarshalYAML allows jobs to be given by their title only
+func (j *Job) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var title string
+	if err := unmarshal(&title); err == nil {
+		*j = Job{Title: title}
+		return nil
+	}
+	type plain Job
+	return unmarshal((*plain)(j))
+}
+
+// Jobs is the list of occupations of a person
+type Jobs []Job
+
+// UnmarshalYAML allows a plain string for jobs (as used by older database files)
+func (j *Jobs) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var title string
+	if err := unmarshal(&title); err == nil {
+		*j = Jobs{{Title: title}}
+		return nil
+	}
+	var jobs []Job
+	if err := unmarshal(&jobs); err != nil {
+		return err
+	}
+	*j = jobs
+	return nil
+}
+
+// At returns the jobs that could have been held at the given point of time. A zero time matches all jobs.
+func (j Jobs) At(t time.Time) Jobs {
+	if t.IsZero() {
+		return j
+	}
+	result := make(Jobs, 0, len(j))
+	for _, job := range j {
+		if job.Contains(t) {
+			result = append(result, job)
+		}
+	}
+	return result
+}
+
+// CurrentAt returns the job held at the given point of time (the one started last if there are multiple), empty
+// if there is none
+func (j Jobs) CurrentAt(t time.Time) Jobs {
+	candidates := j.At(t)
+	if len(candidates) == 0 {
+		return Jobs{}
+	}
+	current := candidates[0]
+	for _, job := range candidates[1:] {
+		if current.From.Before(job.From) {
+			current = job
+		}
+	}
+	return Jobs{current}
+}
+
+// Format displays the job titles separated by comma
+func (j Jobs) Format() string {
+	titles := make([]string, 0, len(j))
+	for _, job := range j {
+		if job.Title == "" {
+			continue
+		}
+		titles = append(titles, job.Title)
+	}
+	return strings.Join(titles, ", ")
+}
+
+// Residence is a place a person lived at
+type Residence struct {
+	Period `yaml:",inline"`
+
+	Place   string      `yaml:"place,omitempty"`
+	Sources []Reference `yaml:"sources,omitempty"`
+}
+
+// Residences is the list of places a person lived at
+type Residences []Residence
+
+// Period returns the time span covering all residences
+func (r Residences) Period() Period {
+	var result Period
+	for _, residence := range r {
+		if !residence.From.Empty() && (result.From.Empty() || residence.From.Before(result.From)) {
+			result.From = residence.From
+		}
+		if !residence.To.Empty() && (result.To.Empty() || result.To.Before(residence.To)) {
+			result.To = residence.To
+		}
+	}
+	return result
+}
+
+// Places returns the places of all residences separated by comma
+func (r Residences) Places() string {
+	places := make([]string, 0, len(r))
+	for _, residence := range r {
+		if residence.Place == "" {
+			continue
+		}
+		places = append(places, residence.Place)
+	}
+	return strings.Join(places, ", ")
+}
+
+// BiographyElement is an event in the life of a person like education, military service or emigration
+type BiographyElement struct {
+	Period `yaml:",inline"`
+
+	Type        BiographyElementType `yaml:"type,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	Place       string               `yaml:"place,omitempty"`
+	Sources     []Reference          `yaml:"sources,omitempty"`
+}
@@ -0,0 +1,67 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// BiographyElementTypeEducation is a BiographyElementType of type Education
+	BiographyElementTypeEducation BiographyElementType = iota + 1
+	// BiographyElementTypeMilitary is a BiographyElementType of type Military
+	BiographyElementTypeMilitary
+	// BiographyElementTypeEmigration is a BiographyElementType of type Emigration
+	BiographyElementTypeEmigration
+	// BiographyElementTypeEvent is a BiographyElementType of type Event
+	BiographyElementTypeEvent
+)
+
+const _BiographyElementTypeName = "educationmilitaryemigrationevent"
+
+var _BiographyElementTypeMap = map[BiographyElementType]string{
+	1: _BiographyElementTypeName[0:9],
+	2: _BiographyElementTypeName[9:17],
+	3: _BiographyElementTypeName[17:27],
+	4: _BiographyElementTypeName[27:32],
+}
+
+// String implements the Stringer interface.
+func (x BiographyElementType) String() string {
+	if str, ok := _BiographyElementTypeMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("BiographyElementType(%d)", x)
+}
+
+var _BiographyElementTypeValue = map[string]BiographyElementType{
+	_BiographyElementTypeName[0:9]:   1,
+	_BiographyElementTypeName[9:17]:  2,
+	_BiographyElementTypeName[17:27]: 3,
+	_BiographyElementTypeName[27:32]: 4,
+}
+
+// ParseBiographyElementType attempts to convert a string to a BiographyElementType
+func ParseBiographyElementType(name string) (BiographyElementType, error) {
+	if x, ok := _BiographyElementTypeValue[name]; ok {
+		return x, nil
+	}
+	return BiographyElementType(0), fmt.Errorf("%s is not a valid BiographyElementType", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x BiographyElementType) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *BiographyElementType) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseBiographyElementType(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -0,0 +1,68 @@
+package generations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLifeEvents(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/database/life-events.yml")
+	assert.Nil(t, err)
+
+	// old files with a plain jobs string
+	legacy, err := db.GetByID("legacy")
+	assert.Nil(t, err)
+	assert.Equal(t, Jobs{{Title: "Mathematician, Priest"}}, legacy.GetJobs())
+
+	gauss, err := db.GetByID("gauss")
+	assert.Nil(t, err)
+	jobs := gauss.GetJobs()
+	assert.Len(t, jobs, 3)
+	assert.Equal(t, "Landvermesser", jobs[0].Title)
+	assert.Equal(t, Date("1807"), jobs[1].From)
+	assert.Equal(t, "Göttingen", jobs[1].Place)
+
+	assert.Len(t, gauss.GetResidences(), 2)
+	assert.Len(t, gauss.GetBiographyElements(), 2)
+	education := gauss.GetBiographyElementsByType(BiographyElementTypeEducation)
+	assert.Len(t, education, 1)
+	assert.Equal(t, "Collegium Carolinum", education[0].Description)
+}
+
+func TestJobsAt(t *testing.T) {
+	jobs := Jobs{
+		{Title: "Landvermesser"},
+		{Title: "Professor", Period: Period{From: "1807", To: "1855-02-23"}},
+		{Title: "Sternwartendirektor", Period: Period{From: "1816"}},
+	}
+
+	assert.Equal(t, "Landvermesser, Professor, Sternwartendirektor", jobs.At(time.Time{}).Format())
+	assert.Equal(t, "Landvermesser, Professor", jobs.At(time.Date(1810, 1, 1, 0, 0, 0, 0, time.UTC)).Format())
+	assert.Equal(t, "Landvermesser, Sternwartendirektor", jobs.At(time.Date(1860, 1, 1, 0, 0, 0, 0, time.UTC)).Format())
+
+	assert.Equal(t, "Professor", jobs.CurrentAt(time.Date(1810, 1, 1, 0, 0, 0, 0, time.UTC)).Format())
+	assert.Equal(t, "Sternwartendirektor", jobs.CurrentAt(time.Date(1820, 1, 1, 0, 0, 0, 0, time.UTC)).Format())
+	assert.Equal(t, "", Jobs{}.CurrentAt(time.Date(1820, 1, 1, 0, 0, 0, 0, time.UTC)).Format())
+}
+
+func TestResidences(t *testing.T) {
+	residences := Residences{
+		{Place: "Braunschweig", Period: Period{To: "1807"}},
+		{Place: "Göttingen", Period: Period{From: "1807", To: "1855"}},
+	}
+	assert.Equal(t, "Braunschweig, Göttingen", residences.Places())
+	assert.Equal(t, Period{From: "1807", To: "1855"}, residences.Period())
+	assert.Equal(t, LaTeX("1807/1855"), residences.Period().Genealogytree())
+	assert.Equal(t, LaTeX(""), Residences{}.Period().Genealogytree())
+}
+
+func TestPeriodString(t *testing.T) {
+	assert.Equal(t, "1792–1795", Period{From: "1792", To: "1795"}.String())
+	assert.Equal(t, "1801-12", Period{From: "1801-12", To: "1801-12"}.String())
+	assert.Equal(t, "1807", Period{From: "1807"}.String())
+	assert.Equal(t, "–1807", Period{To: "1807"}.String())
+	assert.Equal(t, "", Period{}.String())
+}
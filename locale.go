@@ -0,0 +1,103 @@
+package generations
+
+import (
+	"embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultLanguage is the language of a Locale without one
+const DefaultLanguage = LanguageDe
+
+//go:embed locales/*.yml
+var builtinCatalogFiles embed.FS
+
+var (
+	builtinCatalogsOnce sync.Once
+	builtinCatalogs     map[Language]map[string]string
+)
+
+// Locale selects the language of generated texts and the messages to use for it. The zero value is German with the
+// built-in messages.
+type Locale struct {
+	Language Language
+	// Messages override the built-in messages of the language
+	Messages map[string]string
+}
+
+// NewLocale returns the locale for language, later message catalogs override earlier ones
+func NewLocale(language Language, catalogs ...map[string]string) Locale {
+	l := Locale{Language: language, Messages: make(map[string]string)}
+	for _, catalog := range catalogs {
+		for key, message := range catalog {
+			l.Messages[key] = message
+		}
+	}
+	return l
+}
+
+// ParseMessages parses a message catalog in YAML syntax, a map from keys to messages
+func ParseMessages(data []byte) (map[string]string, error) {
+	messages := make(map[string]string)
+	err := yaml.Unmarshal(data, &messages)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid message catalog")
+	}
+	return messages, nil
+}
+
+// GetLanguage returns the language of the locale, DefaultLanguage if it is not set
+func (l Locale) GetLanguage() Language {
+	if l.Language == 0 {
+		return DefaultLanguage
+	}
+	return l.Language
+}
+
+// T returns the message for key. Messages missing in the locale are taken from the built-in catalog of its language,
+// then from the English one. Unknown keys are returned unchanged.
+func (l Locale) T(key string) string {
+	if message, ok := l.Messages[key]; ok {
+		return message
+	}
+	catalogs := getBuiltinCatalogs()
+	if message, ok := catalogs[l.GetLanguage()][key]; ok {
+		return message
+	}
+	if message, ok := catalogs[LanguageEn][key]; ok {
+		return message
+	}
+	return key
+}
+
+// Tf returns the message for key with the placeholders {0}, {1}, … replaced by args
+func (l Locale) Tf(key string, args ...interface{}) string {
+	replacements := make([]string, 0, 2*len(args))
+	for i, arg := range args {
+		replacements = append(replacements, "{"+strconv.Itoa(i)+"}", fmt.Sprint(arg))
+	}
+	return strings.NewReplacer(replacements...).Replace(l.T(key))
+}
+
+func getBuiltinCatalogs() map[Language]map[string]string {
+	builtinCatalogsOnce.Do(func() {
+		builtinCatalogs = make(map[Language]map[string]string)
+		for _, language := range []Language{LanguageEn, LanguageDe} {
+			data, err := builtinCatalogFiles.ReadFile("locales/" + language.String() + ".yml")
+			if err != nil {
+				panic(err)
+			}
+			messages, err := ParseMessages(data)
+			if err != nil {
+				panic(errors.Annotatef(err, "built-in catalog %s", language))
+			}
+			builtinCatalogs[language] = messages
+		}
+	})
+	return builtinCatalogs
+}
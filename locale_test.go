@@ -0,0 +1,54 @@
+package generations
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		Locale   Locale
+		Key      string
+		Expected string
+	}{
+		{Locale: Locale{}, Key: "name.born", Expected: "geb."},
+		{Locale: Locale{Language: LanguageDe}, Key: "babel", Expected: "ngerman"},
+		{Locale: Locale{Language: LanguageEn}, Key: "name.born", Expected: "née"},
+		{Locale: NewLocale(LanguageEn, map[string]string{"name.born": "born"}), Key: "name.born", Expected: "born"},
+		{Locale: NewLocale(LanguageEn, map[string]string{"x": "1"}, map[string]string{"x": "2"}), Key: "x", Expected: "2"},
+		{Locale: NewLocale(LanguageDe, nil), Key: "sources", Expected: "Quellen"},
+		{Locale: Locale{Language: LanguageDe}, Key: "unknown.key", Expected: "unknown.key"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, test.Locale.T(test.Key), test.Key)
+	}
+
+	l := Locale{Language: LanguageEn}
+	assert.Equal(t, "between 1850 and 1855", l.Tf("date.between", 1850, "1855"))
+	assert.Equal(t, "see {0}", l.Tf("see"))
+}
+
+func TestBuiltinCatalogs(t *testing.T) {
+	keys := func(messages map[string]string) []string {
+		result := make([]string, 0, len(messages))
+		for key := range messages {
+			result = append(result, key)
+		}
+		sort.Strings(result)
+		return result
+	}
+	catalogs := getBuiltinCatalogs()
+	assert.NotEmpty(t, catalogs[LanguageEn])
+	assert.Equal(t, keys(catalogs[LanguageEn]), keys(catalogs[LanguageDe]), "all messages are translated")
+}
+
+func TestLocalizedNames(t *testing.T) {
+	name := Name{First: []string{"Emily", "Jane"}, Last: "Heger", Birth: "Brontë"}
+	en := Locale{Language: LanguageEn}
+	assert.Equal(t, "Emily Jane Heger, geb. Brontë", name.FormatFull())
+	assert.Equal(t, "Emily Jane Heger, née Brontë", name.FormatFullIn(en))
+	assert.Equal(t, "Heger, Emily Jane, née Brontë", name.FormatFullInverseIn(en))
+	assert.Equal(t, "Emily Heger, born Brontë", name.FormatFullNoMiddleIn(NewLocale(LanguageEn, map[string]string{"name.born": "born"})))
+}
@@ -1,11 +1,5 @@
 package generations
 
-import (
-	"time"
-
-	age "github.com/bearbin/go-age"
-)
-
 //go:generate go-enum -f=models.go
 
 // Gender x ENUM(
@@ -21,29 +15,14 @@ type Marriage struct {
 }
 
 type DatePlace struct {
-	// need to support things like "before 1932" here
-	Date  string `yaml:"date,omitempty"`
-	Place string `yaml:"place,omitempty"`
+	Date    Date        `yaml:"date,omitempty"`
+	Place   string      `yaml:"place,omitempty"`
+	Sources []Reference `yaml:"sources,omitempty"`
 }
 
-func (d DatePlace) GetAgeBegin(other DatePlace) int {
-	if other.Empty() || len(other.Date) != 10 {
-		return -1
-	}
-	otherTime, err := time.Parse("2006-01-02", other.Date)
-	if err != nil {
-		return -1
-	}
-
-	if d.Empty() || len(d.Date) != 10 {
-		return -1
-	}
-	dateTime, err := time.Parse("2006-01-02", d.Date)
-	if err != nil {
-		return -1
-	}
-
-	return age.AgeAt(otherTime, dateTime)
+// GetAgeBegin returns the age of a person born at other at the time of this event
+func (d DatePlace) GetAgeBegin(other DatePlace) AgeRange {
+	return AgeBetween(other.Date, d.Date)
 }
 
 func (g Gender) IsUnknown() bool {
@@ -51,11 +30,11 @@ func (g Gender) IsUnknown() bool {
 }
 
 func (d DatePlace) Empty() bool {
-	return d.Date == "" && d.Place == ""
+	return d.Date.Empty() && d.Place == ""
 }
 
 func (n Name) Empty() bool {
-	return len(n.First) == 0 && n.Last == "" && n.Birth == ""
+	return len(n.First) == 0 && n.Patronymic == "" && n.Last == "" && n.Birth == ""
 }
 
 func SplitPersons(personList PersonList, split Person) (younger PersonList, older PersonList) {
@@ -1,16 +1,106 @@
 package generations
 
-import "github.com/jojomi/strtpl"
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+//go:generate go-enum -f=name.go --marshal
+
+// NameOrder is the order of the given names and the surname
+/* ENUM(
+given-surname = 1
+surname-given
+*/
+type NameOrder int
+
+// NamePartKind is the kind of a part of a formatted name
+/* ENUM(
+title = 1
+preferred
+given
+used
+patronymic
+nick
+particle
+surname
+birth
+alias
+*/
+type NamePartKind int
 
 type Name struct {
 	Title string   `yaml:"title,omitempty"`
 	First []string `yaml:"first,omitempty"`
 	// If the used first name is different from the first element in the .First slice, it can be set using .Used
-	Used  string `yaml:"used,omitempty"`
-	Last  string `yaml:"last,omitempty"`
-	Birth string `yaml:"birth,omitempty"`
-	Alias string `yaml:"alias,omitempty"`
-	Nick  string `yaml:"nick,omitempty"`
+	Used string `yaml:"used,omitempty"`
+	// Patronymic is the name derived from the father's name, e.g. "Mikhailovich"
+	Patronymic string `yaml:"patronymic,omitempty"`
+	// Particle is the nobility particle of the last name, e.g. "von" or "van der". It is ignored when sorting.
+	Particle string `yaml:"particle,omitempty"`
+	Last     string `yaml:"last,omitempty"`
+	Birth    string `yaml:"birth,omitempty"`
+	Alias    string `yaml:"alias,omitempty"`
+	Nick     string `yaml:"nick,omitempty"`
+	// Order of the given names and the surname if it differs from the name format, e.g. surname-given for Hungarian names
+	Order NameOrder `yaml:"order,omitempty"`
+}
+
+// NameFormat is a profile for formatting names
+type NameFormat struct {
+	// Order of the given names and the surname, given-surname by default. Names with an order of their own keep it.
+	Order NameOrder `yaml:"order,omitempty"`
+	// Inverse puts the surname first, separated by a comma (e.g. "Goethe, Johann Wolfgang von")
+	Inverse bool `yaml:"inverse,omitempty"`
+	// ParticleWithSurname keeps the particle in front of the surname in inverse names (e.g. "van der Berg, Jan")
+	ParticleWithSurname bool `yaml:"particle-with-surname,omitempty"`
+	// LastnamePolicy selects the surnames shown, the current one and the maiden name by default
+	LastnamePolicy LastnamePolicy `yaml:"lastname-policy,omitempty"`
+
+	HideTitle       bool `yaml:"hide-title,omitempty"`
+	HideMiddleNames bool `yaml:"hide-middle-names,omitempty"`
+	HidePatronymic  bool `yaml:"hide-patronymic,omitempty"`
+	HideNick        bool `yaml:"hide-nick,omitempty"`
+	HideAlias       bool `yaml:"hide-alias,omitempty"`
+}
+
+// Names of the built-in name formats
+const (
+	NameFormatFull     = "full"
+	NameFormatInverse  = "inverse"
+	NameFormatNoMiddle = "no-middle"
+	NameFormatTree     = "tree"
+)
+
+// DefaultNameFormats returns the built-in name formats by name. Only the tree format shows titles and aliases.
+func DefaultNameFormats() map[string]NameFormat {
+	return map[string]NameFormat{
+		NameFormatFull:     {HideTitle: true, HideAlias: true},
+		NameFormatInverse:  {Inverse: true, HideTitle: true, HideAlias: true},
+		NameFormatNoMiddle: {HideMiddleNames: true, HideTitle: true, HideAlias: true},
+		NameFormatTree:     {},
+	}
+}
+
+// NameFormats are the name formats available by name, initially the built-in ones
+var NameFormats = DefaultNameFormats()
+
+// GetNameFormat returns the name format with the given name
+func GetNameFormat(name string) (NameFormat, error) {
+	f, ok := NameFormats[name]
+	if !ok {
+		return NameFormat{}, errors.NotFoundf("name format %s", name)
+	}
+	return f, nil
+}
+
+// NamePart is a part of a formatted name
+type NamePart struct {
+	Kind NamePartKind
+	Text string
+	// Separator separates the part from the previous one, it is empty for the first part
+	Separator string
 }
 
 // GetUsedFirst returns the used name if it is given, the first of the first names otherwise
@@ -24,40 +114,155 @@ func (n Name) GetUsedFirst() string {
 	return n.First[0]
 }
 
-// FormatFullInverse displays the full name, lastname before firstname
+// Parts returns the parts of the name shown by the name format in display order
+func (n Name) Parts(f NameFormat) []NamePart {
+	var title, given, surname []NamePart
+	add := func(parts *[]NamePart, kind NamePartKind, text string) {
+		if text != "" {
+			*parts = append(*parts, NamePart{Kind: kind, Text: text})
+		}
+	}
+
+	if !f.HideTitle {
+		add(&title, NamePartKindTitle, n.Title)
+	}
+	matched := false
+	for i, first := range n.First {
+		switch {
+		case n.Used == "" && i == 0, n.Used != "" && first == n.Used && !matched:
+			matched = n.Used != ""
+			add(&given, NamePartKindPreferred, first)
+		case !f.HideMiddleNames:
+			add(&given, NamePartKindGiven, first)
+		}
+	}
+	// the used name is only set apart from the given names if they are shown
+	if n.Used != "" && !matched && f.HideMiddleNames {
+		add(&given, NamePartKindPreferred, n.Used)
+	} else if n.Used != "" && !matched {
+		add(&given, NamePartKindUsed, n.Used)
+	}
+	if !f.HidePatronymic {
+		add(&given, NamePartKindPatronymic, n.Patronymic)
+	}
+	if !f.HideNick {
+		add(&given, NamePartKindNick, n.Nick)
+	}
+
+	last, birth := n.Last, ""
+	switch f.LastnamePolicy {
+	case LastnamePolicyBirth:
+		if n.Birth != "" {
+			last = n.Birth
+		}
+	case LastnamePolicyCurrentAndBirth, 0:
+		birth = n.Birth
+	}
+	if last == n.Last && last != "" && n.Particle != "" {
+		particle := NamePart{Kind: NamePartKindParticle, Text: n.Particle}
+		if f.Inverse && !f.ParticleWithSurname {
+			given = append(given, particle)
+		} else {
+			surname = append(surname, particle)
+		}
+	}
+	add(&surname, NamePartKindSurname, last)
+
+	order := n.Order
+	if order == 0 {
+		order = f.Order
+	}
+	var result []NamePart
+	switch {
+	case f.Inverse:
+		result = joinNameParts(surname, " ")
+		rest := joinNameParts(append(title, given...), " ")
+		if len(result) > 0 && len(rest) > 0 {
+			rest[0].Separator = ", "
+		}
+		result = append(result, rest...)
+	case order == NameOrderSurnameGiven:
+		result = joinNameParts(append(append(title, surname...), given...), " ")
+	default:
+		result = joinNameParts(append(append(title, given...), surname...), " ")
+	}
+
+	if birth != "" {
+		result = append(result, NamePart{Kind: NamePartKindBirth, Text: birth, Separator: ", "})
+	}
+	if !f.HideAlias && n.Alias != "" {
+		result = append(result, NamePart{Kind: NamePartKindAlias, Text: n.Alias, Separator: " "})
+	}
+	if len(result) > 0 {
+		result[0].Separator = ""
+	}
+	return result
+}
+
+// joinNameParts separates all but the first of parts by separator
+func joinNameParts(parts []NamePart, separator string) []NamePart {
+	result := make([]NamePart, len(parts))
+	for i, part := range parts {
+		if i > 0 {
+			part.Separator = separator
+		}
+		result[i] = part
+	}
+	return result
+}
+
+// Format formats the name as plain text using the name format and the words of the locale
+func (n Name) Format(f NameFormat, l Locale) string {
+	return formatNameParts(n.Parts(f), l)
+}
+
+// formatNameParts formats parts of a name as plain text
+func formatNameParts(parts []NamePart, l Locale) string {
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(part.Separator)
+		switch part.Kind {
+		case NamePartKindUsed:
+			b.WriteString(`"` + part.Text + `"`)
+		case NamePartKindNick:
+			b.WriteString(`("` + part.Text + `")`)
+		case NamePartKindBirth:
+			b.WriteString(l.T("name.born") + " " + part.Text)
+		case NamePartKindAlias:
+			b.WriteString("– " + part.Text)
+		default:
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
+
+// FormatFullInverse displays the full name, lastname before firstname, in the default locale
 func (n Name) FormatFullInverse() string {
-	return strtpl.MustEval(
-		`{{- if .Last -}} {{ .Last }}, {{- end -}}
-		{{- range .First }} {{ . }}{{- end -}}
-		{{- if .Used }} " {{- .Used -}} " {{- end -}}
-		{{- if .Nick }} (" {{- .Nick -}} ") {{- end -}}
-		{{- if .Birth -}}, geb. {{ .Birth -}} {{- end -}}
-		`,
-		n,
-	)
-}
-
-// FormatFull displays the full name, firstname before lastname
+	return n.FormatFullInverseIn(Locale{})
+}
+
+// FormatFullInverseIn displays the full name, lastname before firstname, in the given locale
+func (n Name) FormatFullInverseIn(l Locale) string {
+	return n.Format(NameFormats[NameFormatInverse], l)
+}
+
+// FormatFull displays the full name, firstname before lastname, in the default locale
 func (n Name) FormatFull() string {
-	return strtpl.MustEval(
-		`{{- range .First }}{{ . }} {{ end -}}
-		{{- if .Used -}} " {{- .Used -}} " {{ end -}}
-		{{- if .Nick -}} (" {{- .Nick -}} ") {{ end -}}
-		{{- if .Last -}}{{ .Last }} {{- end -}}
-		{{- if .Birth -}}, geb. {{ .Birth -}} {{- end -}}
-		`,
-		n,
-	)
-}
-
-// FormatFullNoMiddle displays the full name, firstname before lastname, withotu middlename(s)
+	return n.FormatFullIn(Locale{})
+}
+
+// FormatFullIn displays the full name, firstname before lastname, in the given locale
+func (n Name) FormatFullIn(l Locale) string {
+	return n.Format(NameFormats[NameFormatFull], l)
+}
+
+// FormatFullNoMiddle displays the full name, firstname before lastname, without middlename(s), in the default locale
 func (n Name) FormatFullNoMiddle() string {
-	return strtpl.MustEval(
-		`{{- if .GetUsedFirst -}} {{ .GetUsedFirst }} {{ end -}}
-		{{- if .Nick -}} (" {{- .Nick -}} ") {{ end -}}
-		{{- if .Last -}}{{ .Last }} {{- end -}}
-		{{- if .Birth -}}, geb. {{ .Birth -}} {{- end -}}
-		`,
-		n,
-	)
+	return n.FormatFullNoMiddleIn(Locale{})
+}
+
+// FormatFullNoMiddleIn displays the full name, firstname before lastname, without middlename(s), in the given locale
+func (n Name) FormatFullNoMiddleIn(l Locale) string {
+	return n.Format(NameFormats[NameFormatNoMiddle], l)
 }
@@ -0,0 +1,142 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// NameOrderGivenSurname is a NameOrder of type GivenSurname
+	NameOrderGivenSurname NameOrder = iota + 1
+	// NameOrderSurnameGiven is a NameOrder of type SurnameGiven
+	NameOrderSurnameGiven
+)
+
+const _NameOrderName = "given-surnamesurname-given"
+
+var _NameOrderMap = map[NameOrder]string{
+	1: _NameOrderName[0:13],
+	2: _NameOrderName[13:26],
+}
+
+// String implements the Stringer interface.
+func (x NameOrder) String() string {
+	if str, ok := _NameOrderMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("NameOrder(%d)", x)
+}
+
+var _NameOrderValue = map[string]NameOrder{
+	_NameOrderName[0:13]:  1,
+	_NameOrderName[13:26]: 2,
+}
+
+// ParseNameOrder attempts to convert a string to a NameOrder
+func ParseNameOrder(name string) (NameOrder, error) {
+	if x, ok := _NameOrderValue[name]; ok {
+		return x, nil
+	}
+	return NameOrder(0), fmt.Errorf("%s is not a valid NameOrder", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x NameOrder) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *NameOrder) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseNameOrder(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// NamePartKindTitle is a NamePartKind of type Title
+	NamePartKindTitle NamePartKind = iota + 1
+	// NamePartKindPreferred is a NamePartKind of type Preferred
+	NamePartKindPreferred
+	// NamePartKindGiven is a NamePartKind of type Given
+	NamePartKindGiven
+	// NamePartKindUsed is a NamePartKind of type Used
+	NamePartKindUsed
+	// NamePartKindPatronymic is a NamePartKind of type Patronymic
+	NamePartKindPatronymic
+	// NamePartKindNick is a NamePartKind of type Nick
+	NamePartKindNick
+	// NamePartKindParticle is a NamePartKind of type Particle
+	NamePartKindParticle
+	// NamePartKindSurname is a NamePartKind of type Surname
+	NamePartKindSurname
+	// NamePartKindBirth is a NamePartKind of type Birth
+	NamePartKindBirth
+	// NamePartKindAlias is a NamePartKind of type Alias
+	NamePartKindAlias
+)
+
+const _NamePartKindName = "titlepreferredgivenusedpatronymicnickparticlesurnamebirthalias"
+
+var _NamePartKindMap = map[NamePartKind]string{
+	1:  _NamePartKindName[0:5],
+	2:  _NamePartKindName[5:14],
+	3:  _NamePartKindName[14:19],
+	4:  _NamePartKindName[19:23],
+	5:  _NamePartKindName[23:33],
+	6:  _NamePartKindName[33:37],
+	7:  _NamePartKindName[37:45],
+	8:  _NamePartKindName[45:52],
+	9:  _NamePartKindName[52:57],
+	10: _NamePartKindName[57:62],
+}
+
+// String implements the Stringer interface.
+func (x NamePartKind) String() string {
+	if str, ok := _NamePartKindMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("NamePartKind(%d)", x)
+}
+
+var _NamePartKindValue = map[string]NamePartKind{
+	_NamePartKindName[0:5]:   1,
+	_NamePartKindName[5:14]:  2,
+	_NamePartKindName[14:19]: 3,
+	_NamePartKindName[19:23]: 4,
+	_NamePartKindName[23:33]: 5,
+	_NamePartKindName[33:37]: 6,
+	_NamePartKindName[37:45]: 7,
+	_NamePartKindName[45:52]: 8,
+	_NamePartKindName[52:57]: 9,
+	_NamePartKindName[57:62]: 10,
+}
+
+// ParseNamePartKind attempts to convert a string to a NamePartKind
+func ParseNamePartKind(name string) (NamePartKind, error) {
+	if x, ok := _NamePartKindValue[name]; ok {
+		return x, nil
+	}
+	return NamePartKind(0), fmt.Errorf("%s is not a valid NamePartKind", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x NamePartKind) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *NamePartKind) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseNamePartKind(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -0,0 +1,73 @@
+package generations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameFormat(t *testing.T) {
+	goethe := Name{Title: "Dr.", First: []string{"Johann", "Wolfgang"}, Particle: "von", Last: "Goethe"}
+	bartok := Name{First: []string{"Béla"}, Last: "Bartók", Order: NameOrderSurnameGiven}
+	dostoevsky := Name{First: []string{"Fyodor"}, Patronymic: "Mikhailovich", Last: "Dostoevsky", Nick: "Fedya"}
+	melanie := Name{First: []string{"Emilia", "Melanie"}, Used: "Melanie", Last: "Göhler", Birth: "Lehnert", Alias: "Mel"}
+	hans := Name{First: []string{"Johann"}, Used: "Hans", Last: "Meier"}
+
+	tests := []struct {
+		Name     Name
+		Format   NameFormat
+		Expected string
+	}{
+		{Name: goethe, Expected: "Dr. Johann Wolfgang von Goethe"},
+		{Name: goethe, Format: NameFormat{HideTitle: true, HideMiddleNames: true}, Expected: "Johann von Goethe"},
+		{Name: goethe, Format: NameFormat{Inverse: true}, Expected: "Goethe, Dr. Johann Wolfgang von"},
+		{Name: goethe, Format: NameFormat{Inverse: true, ParticleWithSurname: true}, Expected: "von Goethe, Dr. Johann Wolfgang"},
+		{Name: goethe, Format: NameFormat{Order: NameOrderSurnameGiven}, Expected: "Dr. von Goethe Johann Wolfgang"},
+		{Name: bartok, Expected: "Bartók Béla"},
+		{Name: bartok, Format: NameFormat{Inverse: true}, Expected: "Bartók, Béla"},
+		{Name: dostoevsky, Expected: `Fyodor Mikhailovich ("Fedya") Dostoevsky`},
+		{Name: dostoevsky, Format: NameFormat{HidePatronymic: true, HideNick: true}, Expected: "Fyodor Dostoevsky"},
+		{Name: melanie, Expected: "Emilia Melanie Göhler, geb. Lehnert – Mel"},
+		{Name: melanie, Format: NameFormat{HideMiddleNames: true, HideAlias: true}, Expected: "Melanie Göhler, geb. Lehnert"},
+		{Name: melanie, Format: NameFormat{LastnamePolicy: LastnamePolicyBirth, HideAlias: true}, Expected: "Emilia Melanie Lehnert"},
+		{Name: melanie, Format: NameFormat{LastnamePolicy: LastnamePolicyCurrent, HideAlias: true}, Expected: "Emilia Melanie Göhler"},
+		{Name: hans, Expected: `Johann "Hans" Meier`},
+		{Name: hans, Format: NameFormat{HideMiddleNames: true}, Expected: "Hans Meier"},
+		{Name: Name{First: []string{"Lena"}, Birth: "Pachowski"}, Expected: "Lena, geb. Pachowski"},
+		{Name: Name{}, Expected: ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, test.Name.Format(test.Format, Locale{}), test.Expected)
+	}
+}
+
+func TestNameFormatBuiltin(t *testing.T) {
+	name := Name{Title: "Dr.", First: []string{"Johann", "Carl"}, Used: "Hans", Last: "Meier", Nick: "Hansi", Alias: "der Alte"}
+	assert.Equal(t, `Johann Carl "Hans" ("Hansi") Meier`, name.FormatFull())
+	assert.Equal(t, `Meier, Johann Carl "Hans" ("Hansi")`, name.FormatFullInverse())
+	assert.Equal(t, `Hans ("Hansi") Meier`, name.FormatFullNoMiddle())
+
+	name = Name{Title: "Dr.", First: []string{"Johann", "Carl"}, Used: "Carl", Last: "Meier", Birth: "Schulze"}
+	assert.Equal(t, "Johann Carl Meier, geb. Schulze", name.FormatFull())
+	assert.Equal(t, "Meier, Johann Carl, geb. Schulze", name.FormatFullInverse())
+	assert.Equal(t, "Carl Meier, geb. Schulze", name.FormatFullNoMiddle())
+}
+
+func TestNameParts(t *testing.T) {
+	name := Name{Title: "Dr.", First: []string{"Anna", "Maria"}, Used: "Maria", Particle: "van der", Last: "Berg", Birth: "Smit"}
+	assert.Equal(t, []NamePart{
+		{Kind: NamePartKindSurname, Text: "Berg"},
+		{Kind: NamePartKindTitle, Text: "Dr.", Separator: ", "},
+		{Kind: NamePartKindGiven, Text: "Anna", Separator: " "},
+		{Kind: NamePartKindPreferred, Text: "Maria", Separator: " "},
+		{Kind: NamePartKindParticle, Text: "van der", Separator: " "},
+		{Kind: NamePartKindBirth, Text: "Smit", Separator: ", "},
+	}, name.Parts(NameFormat{Inverse: true}))
+
+	f, err := RenderPersonOptions{HideMiddleNames: true, LastnamePolicy: LastnamePolicyBirth}.GetNameFormat()
+	assert.Nil(t, err)
+	assert.Equal(t, NameFormat{HideMiddleNames: true, LastnamePolicy: LastnamePolicyBirth}, f)
+	_, err = RenderPersonOptions{NameFormat: "missing"}.GetNameFormat()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,65 @@
+package generations
+
+//go:generate go-enum -f=parent_link.go --marshal
+
+/* ENUM(
+biological = 1
+adoptive
+step
+foster
+*/
+type ParentLinkType int
+
+/* ENUM(
+mom = 1
+dad
+*/
+type ParentRole int
+
+// ParentLink connects a person to one of its parental figures
+type ParentLink struct {
+	Period `yaml:",inline"`
+
+	// ID is the ID or UUID of the parent
+	ID   string         `yaml:"id,omitempty"`
+	Type ParentLinkType `yaml:"type,omitempty"`
+	// Role is derived from the gender of the parent if not given
+	Role ParentRole `yaml:"role,omitempty"`
+}
+
+// GetType returns the type of the link, biological if none is given
+func (l ParentLink) GetType() ParentLinkType {
+	if l.Type == 0 {
+		return ParentLinkTypeBiological
+	}
+	return l.Type
+}
+
+// IsAnyOf returns true iff the link type is contained in the given list
+func (x ParentLinkType) IsAnyOf(types ...ParentLinkType) bool {
+	for _, t := range types {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStyle returns the style of the edges between parents and children connected by this link type in a tree, empty
+// for biological links
+func (x ParentLinkType) GetStyle() string {
+	if x == 0 || x == ParentLinkTypeBiological {
+		return ""
+	}
+	return "link-" + x.String()
+}
+
+func roleMatchesGender(role ParentRole, gender Gender) bool {
+	switch role {
+	case ParentRoleMom:
+		return gender == GenderFemale
+	case ParentRoleDad:
+		return gender == GenderMale
+	}
+	return false
+}
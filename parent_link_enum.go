@@ -0,0 +1,118 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// ParentLinkTypeBiological is a ParentLinkType of type Biological
+	ParentLinkTypeBiological ParentLinkType = iota + 1
+	// ParentLinkTypeAdoptive is a ParentLinkType of type Adoptive
+	ParentLinkTypeAdoptive
+	// ParentLinkTypeStep is a ParentLinkType of type Step
+	ParentLinkTypeStep
+	// ParentLinkTypeFoster is a ParentLinkType of type Foster
+	ParentLinkTypeFoster
+)
+
+const _ParentLinkTypeName = "biologicaladoptivestepfoster"
+
+var _ParentLinkTypeMap = map[ParentLinkType]string{
+	1: _ParentLinkTypeName[0:10],
+	2: _ParentLinkTypeName[10:18],
+	3: _ParentLinkTypeName[18:22],
+	4: _ParentLinkTypeName[22:28],
+}
+
+// String implements the Stringer interface.
+func (x ParentLinkType) String() string {
+	if str, ok := _ParentLinkTypeMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("ParentLinkType(%d)", x)
+}
+
+var _ParentLinkTypeValue = map[string]ParentLinkType{
+	_ParentLinkTypeName[0:10]:  1,
+	_ParentLinkTypeName[10:18]: 2,
+	_ParentLinkTypeName[18:22]: 3,
+	_ParentLinkTypeName[22:28]: 4,
+}
+
+// ParseParentLinkType attempts to convert a string to a ParentLinkType
+func ParseParentLinkType(name string) (ParentLinkType, error) {
+	if x, ok := _ParentLinkTypeValue[name]; ok {
+		return x, nil
+	}
+	return ParentLinkType(0), fmt.Errorf("%s is not a valid ParentLinkType", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x ParentLinkType) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *ParentLinkType) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseParentLinkType(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// ParentRoleMom is a ParentRole of type Mom
+	ParentRoleMom ParentRole = iota + 1
+	// ParentRoleDad is a ParentRole of type Dad
+	ParentRoleDad
+)
+
+const _ParentRoleName = "momdad"
+
+var _ParentRoleMap = map[ParentRole]string{
+	1: _ParentRoleName[0:3],
+	2: _ParentRoleName[3:6],
+}
+
+// String implements the Stringer interface.
+func (x ParentRole) String() string {
+	if str, ok := _ParentRoleMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("ParentRole(%d)", x)
+}
+
+var _ParentRoleValue = map[string]ParentRole{
+	_ParentRoleName[0:3]: 1,
+	_ParentRoleName[3:6]: 2,
+}
+
+// ParseParentRole attempts to convert a string to a ParentRole
+func ParseParentRole(name string) (ParentRole, error) {
+	if x, ok := _ParentRoleValue[name]; ok {
+		return x, nil
+	}
+	return ParentRole(0), fmt.Errorf("%s is not a valid ParentRole", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x ParentRole) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *ParentRole) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseParentRole(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -7,6 +7,10 @@ type Person interface {
 	SetID(id string)
 	GetID() string
 	GetUUID() string
+	// GetBestID returns the ID if set, the UUID otherwise
+	GetBestID() string
+	// GetPosition returns the location of a field (e.g. "mom" or "partners[1]") in the database file
+	GetPosition(field string) Position
 	GetGender() Gender
 	MatchesIDUUID(idUUIDSearches ...string) bool
 	MatchesSearch(search string) bool
@@ -15,16 +19,34 @@ type Person interface {
 	GetBirth() DatePlace
 	GetBaptism() DatePlace
 	GetDeath() DatePlace
-	// GetDeathAge returns the age in years at the given point of time. -1 iff the age can't be determined.
-	GetAge(now time.Time) int
-	// GetDeathAge returns the age in years when the person died. -1 iff the age can't be determined.
-	GetDeathAge() int
+	// GetAge returns the age in years at the given point of time. The result is empty iff the age can't be determined.
+	GetAge(now time.Time) AgeRange
+	// GetDeathAge returns the age in years when the person died. The result is empty iff the age can't be determined.
+	GetDeathAge() AgeRange
 	GetBurial() DatePlace
-	// GetChildren returns all children of this person
+	// GetChildren returns all biological children of this person
 	GetChildren() (PersonList, error)
+	// GetChildrenByType returns all children linked to this person by any of the given types
+	GetChildrenByType(types ...ParentLinkType) (PersonList, error)
 	GetMom() (Person, error)
 	GetDad() (Person, error)
-	GetRelationships() []Relationship
+	// GetMomByType returns the first mother linked by any of the given types
+	GetMomByType(types ...ParentLinkType) (Person, error)
+	// GetDadByType returns the first father linked by any of the given types
+	GetDadByType(types ...ParentLinkType) (Person, error)
+	// GetParentsByType returns all parental figures linked by any of the given types
+	GetParentsByType(types ...ParentLinkType) (PersonList, error)
+	// GetParentLinks returns the links to all parental figures
+	GetParentLinks() []ParentLink
+	// GetParentLinkType returns how the given parent is linked to this person
+	GetParentLinkType(parent Person) (ParentLinkType, bool)
+	// GetRawMom returns the ID or UUID of the mother as given in the database
+	GetRawMom() string
+	SetRawMom(mom string)
+	// GetRawDad returns the ID or UUID of the father as given in the database
+	GetRawDad() string
+	SetRawDad(dad string)
+	GetRelationships() []PartnerRelationship
 	// GetPartners returns the list partners that are known for this person
 	// A partner is a person that
 	// - has been married with this person for any given moment in the past
@@ -33,16 +55,30 @@ type Person interface {
 	GetPartners() (PersonList, error)
 	// GetChildrenWith returns the list of children of this person with a given partner
 	GetChildrenWith(partner Person) (PersonList, error)
+	// GetChildrenWithByType returns the list of children linked by any of the given types with a given partner
+	GetChildrenWithByType(partner Person, types ...ParentLinkType) (PersonList, error)
 	// GetChildrenParents returns the list partners that person has children with (possibly including `nil` iff there is children where no other parent is known)
 	GetChildrenParents() (PersonList, error)
+	// GetChildrenParentsByType returns the list of partners that person has children linked by any of the given types with
+	GetChildrenParentsByType(types ...ParentLinkType) (PersonList, error)
 	GetAttributes() []string
 	AddAttribute(attr string)
 	GetImageFilename() string
 	SetImageFilename(filename string)
 	GetFloruit() string
-	GetJobs() string
-	SetJobs(jobs string)
+	// IsPrivate returns true if the data of the person is to be hidden regardless of its age
+	IsPrivate() bool
+	GetJobs() Jobs
+	SetJobs(jobs Jobs)
+	GetResidences() Residences
+	// GetBiographyElements returns further life events like education, military service or emigration
+	GetBiographyElements() []BiographyElement
+	GetBiographyElementsByType(t BiographyElementType) []BiographyElement
 	GetComment() string
 	SetComment(comment string)
+	// GetSources returns the references for the person as a whole
+	GetSources() []Reference
+	// GetAllReferences returns the references for the person and all of its facts
+	GetAllReferences() []Reference
 	IsDummy() bool
 }
@@ -0,0 +1,125 @@
+package generations
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Position is a location in a database file
+type Position struct {
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+func (p Position) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	return p.File + ":" + strconv.Itoa(p.Line)
+}
+
+// personPosition holds the positions of a person and its fields in a yaml database file
+type personPosition struct {
+	Position
+	// fields maps top level keys to their line
+	fields map[string]int
+	// items maps keys of lists to the lines of their items
+	items map[string][]int
+}
+
+// get returns the position of the given field (e.g. "mom" or "partners[1]"), the position of the person if the
+// field is unknown
+func (p *personPosition) get(field string) Position {
+	if p == nil {
+		return Position{}
+	}
+	result := p.Position
+	if i := strings.Index(field, "["); i > 0 && strings.HasSuffix(field, "]") {
+		index, err := strconv.Atoi(field[i+1 : len(field)-1])
+		items := p.items[field[:i]]
+		if err == nil && index >= 0 && index < len(items) {
+			result.Line = items[index]
+			return result
+		}
+		field = field[:i]
+	}
+	if line, ok := p.fields[field]; ok {
+		result.Line = line
+	}
+	return result
+}
+
+var (
+	yamlListItemRegexp = regexp.MustCompile(`^(\s*)- ?(.*)$`)
+	yamlKeyRegexp      = regexp.MustCompile(`^(\s*)([\w-]+):`)
+)
+
+// scanPersonPositions finds the lines of all persons in a yaml database file, either given as plain list or in the
+// persons key. This is a line based approximation, flow style yaml is only resolved to the person.
+func scanPersonPositions(filename string, data []byte) []*personPosition {
+	lines := strings.Split(string(data), "\n")
+	var (
+		result     []*personPosition
+		current    *personPosition
+		inList     = true
+		itemIndent = -1
+		listKey    string
+		listIndent = -1
+	)
+	for i, line := range lines {
+		lineNumber := i + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		// top level keys switch between the persons list and other data
+		if indent == 0 && !strings.HasPrefix(trimmed, "-") {
+			m := yamlKeyRegexp.FindStringSubmatch(line)
+			inList = m != nil && m[2] == "persons"
+			current = nil
+			itemIndent = -1
+			continue
+		}
+		if !inList {
+			continue
+		}
+
+		content := line
+		if m := yamlListItemRegexp.FindStringSubmatch(line); m != nil {
+			if itemIndent < 0 {
+				itemIndent = len(m[1])
+			}
+			if len(m[1]) == itemIndent {
+				current = &personPosition{
+					Position: Position{File: filename, Line: lineNumber},
+					fields:   make(map[string]int),
+					items:    make(map[string][]int),
+				}
+				result = append(result, current)
+				listKey = ""
+				// the first key of the person is given on the same line as the dash
+				content = strings.Repeat(" ", itemIndent+2) + m[2]
+			} else if current != nil && listKey != "" {
+				if listIndent < 0 {
+					listIndent = len(m[1])
+				}
+				if len(m[1]) == listIndent {
+					current.items[listKey] = append(current.items[listKey], lineNumber)
+				}
+				continue
+			}
+		}
+		if current == nil {
+			continue
+		}
+		if m := yamlKeyRegexp.FindStringSubmatch(content); m != nil && len(m[1]) == itemIndent+2 {
+			current.fields[m[2]] = lineNumber
+			listKey = m[2]
+			listIndent = -1
+		}
+	}
+	return result
+}
@@ -0,0 +1,260 @@
+package generations
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+//go:generate go-enum -f=privacy.go --marshal
+
+// PrivacyAction is what happens to a field of a private person
+/* ENUM(
+keep = 1
+year
+initial
+remove
+*/
+type PrivacyAction int
+
+// PrivacyField is a part of the data of a person
+/* ENUM(
+name = 1
+birth
+baptism
+death
+burial
+engagement
+marriage
+divorce
+jobs
+residences
+biography
+floruit
+comment
+image
+sources
+*/
+type PrivacyField int
+
+// defaultLivingYears is the age up to which persons without death date are considered living
+const defaultLivingYears = 100
+
+// names of the built-in privacy policies
+const (
+	// PrivacyPolicyAnonymize hides most data of living and private persons
+	PrivacyPolicyAnonymize = "anonymize"
+	// PrivacyPolicyPrivateOnly hides the same data of persons marked private only
+	PrivacyPolicyPrivateOnly = "private-only"
+)
+
+// PrivacyPolicy decides which persons are private and which of their data is hidden
+type PrivacyPolicy struct {
+	// LivingYears is the age up to which persons without date of death are considered living, 100 if zero. Living
+	// persons are private, so are their descendants. A negative value makes only persons marked private private.
+	LivingYears int `yaml:"living-years,omitempty"`
+	// Fields are the actions for the data of private persons, fields without an action are removed
+	Fields map[PrivacyField]PrivacyAction `yaml:"fields,omitempty"`
+}
+
+// PrivacyPolicies returns the built-in privacy policies by name
+func PrivacyPolicies() map[string]PrivacyPolicy {
+	fields := map[PrivacyField]PrivacyAction{
+		PrivacyFieldName:     PrivacyActionInitial,
+		PrivacyFieldBirth:    PrivacyActionYear,
+		PrivacyFieldDeath:    PrivacyActionYear,
+		PrivacyFieldMarriage: PrivacyActionYear,
+		PrivacyFieldDivorce:  PrivacyActionYear,
+	}
+	return map[string]PrivacyPolicy{
+		PrivacyPolicyAnonymize: {
+			Fields: fields,
+		},
+		PrivacyPolicyPrivateOnly: {
+			LivingYears: -1,
+			Fields:      fields,
+		},
+	}
+}
+
+// Validate returns an error if an action is not applicable to its field
+func (p PrivacyPolicy) Validate() error {
+	for field, action := range p.Fields {
+		switch action {
+		case PrivacyActionKeep, PrivacyActionRemove:
+			continue
+		case PrivacyActionYear:
+			if field.isDatePlace() {
+				continue
+			}
+		case PrivacyActionInitial:
+			if field == PrivacyFieldName {
+				continue
+			}
+		}
+		return errors.Errorf("privacy action %s is not applicable to %s", action, field)
+	}
+	return nil
+}
+
+// action returns the action for a field, remove if there is none
+func (p PrivacyPolicy) action(field PrivacyField) PrivacyAction {
+	if action, ok := p.Fields[field]; ok {
+		return action
+	}
+	return PrivacyActionRemove
+}
+
+func (x PrivacyField) isDatePlace() bool {
+	switch x {
+	case PrivacyFieldBirth, PrivacyFieldBaptism, PrivacyFieldDeath, PrivacyFieldBurial,
+		PrivacyFieldEngagement, PrivacyFieldMarriage, PrivacyFieldDivorce:
+		return true
+	}
+	return false
+}
+
+// PrivatePersons returns the IDs of the private persons at the given point of time: those marked private, the
+// living and the descendants of persons known to be living by their date of birth
+func (p PrivacyPolicy) PrivatePersons(persons []Person, now time.Time) (map[string]bool, error) {
+	years := p.LivingYears
+	if years == 0 {
+		years = defaultLivingYears
+	}
+	result := make(map[string]bool)
+	var living []Person
+	for _, person := range persons {
+		if person.IsPrivate() {
+			result[person.GetBestID()] = true
+		}
+		if years < 0 || !IsLiving(person, now, years) {
+			continue
+		}
+		result[person.GetBestID()] = true
+		// persons without date of birth are private themselves only, their descendants could be long dead
+		if _, ok := person.GetBirth().Date.Year(); ok {
+			living = append(living, person)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for len(living) > 0 {
+		person := living[0]
+		living = living[1:]
+		if seen[person.GetBestID()] {
+			continue
+		}
+		seen[person.GetBestID()] = true
+		children, err := person.GetChildrenByType(ParentLinkTypeBiological, ParentLinkTypeAdoptive, ParentLinkTypeStep, ParentLinkTypeFoster)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children.GetPersons() {
+			result[child.GetBestID()] = true
+			living = append(living, child)
+		}
+	}
+	return result, nil
+}
+
+// ApplyPrivacy hides the data of the private persons in the database as the policy says. Relationships are treated as
+// private if one of the partners is.
+func (y *MemoryDatabase) ApplyPrivacy(policy PrivacyPolicy, now time.Time) error {
+	err := policy.Validate()
+	if err != nil {
+		return err
+	}
+	private, err := policy.PrivatePersons(y.GetPersons(), now)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range y.Persons {
+		for j, r := range p.Partners {
+			if !private[p.GetBestID()] && !private[y.getPartnerID(r)] {
+				continue
+			}
+			r.Engagement = policy.applyDatePlace(PrivacyFieldEngagement, r.Engagement)
+			r.Marriage = policy.applyDatePlace(PrivacyFieldMarriage, r.Marriage)
+			r.Divorce = policy.applyDatePlace(PrivacyFieldDivorce, r.Divorce)
+			p.Partners[j] = r
+		}
+		if !private[p.GetBestID()] {
+			continue
+		}
+		policy.apply(p)
+	}
+	y.Reindex()
+	return nil
+}
+
+// getPartnerID returns the best ID of the partner in a relationship, the raw ID if it is unknown
+func (y *MemoryDatabase) getPartnerID(r FlatRelationship) string {
+	partner, err := y.GetByID(r.PartnerID)
+	if err != nil {
+		return r.PartnerID
+	}
+	return partner.GetBestID()
+}
+
+func (p PrivacyPolicy) apply(person *FlatPerson) {
+	switch p.action(PrivacyFieldName) {
+	case PrivacyActionKeep:
+	case PrivacyActionInitial:
+		name := person.Name
+		person.Name = Name{}
+		if used := name.GetUsedFirst(); used != "" {
+			person.Name.First = []string{first(used, 1) + "."}
+		}
+	default:
+		person.Name = Name{}
+	}
+	person.Birth = p.applyDatePlace(PrivacyFieldBirth, person.Birth)
+	person.Baptism = p.applyDatePlace(PrivacyFieldBaptism, person.Baptism)
+	person.Death = p.applyDatePlace(PrivacyFieldDeath, person.Death)
+	person.Burial = p.applyDatePlace(PrivacyFieldBurial, person.Burial)
+
+	if p.action(PrivacyFieldJobs) != PrivacyActionKeep {
+		person.Jobs = make(Jobs, 0)
+	}
+	if p.action(PrivacyFieldResidences) != PrivacyActionKeep {
+		person.Residences = make(Residences, 0)
+	}
+	if p.action(PrivacyFieldBiography) != PrivacyActionKeep {
+		person.BiographyElements = make([]BiographyElement, 0)
+	}
+	if p.action(PrivacyFieldFloruit) != PrivacyActionKeep {
+		person.Floruit = ""
+	}
+	if p.action(PrivacyFieldComment) != PrivacyActionKeep {
+		person.Comment = ""
+	}
+	if p.action(PrivacyFieldImage) != PrivacyActionKeep {
+		person.ImageFilename = ""
+	}
+	if p.action(PrivacyFieldSources) != PrivacyActionKeep {
+		person.Sources = make([]Reference, 0)
+	}
+}
+
+func (p PrivacyPolicy) applyDatePlace(field PrivacyField, d DatePlace) DatePlace {
+	switch p.action(field) {
+	case PrivacyActionKeep:
+		return d
+	case PrivacyActionYear:
+		return DatePlace{Date: d.Date.YearOnly()}
+	}
+	return DatePlace{}
+}
+
+// IsLiving returns true if p has no date of death or burial and is not known to be born more than years ago
+func IsLiving(p Person, now time.Time, years int) bool {
+	if !p.GetDeath().Empty() || !p.GetBurial().Empty() {
+		return false
+	}
+	year, ok := p.GetBirth().Date.Year()
+	if !ok {
+		return true
+	}
+	return now.Year()-year <= years
+}
@@ -0,0 +1,170 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// PrivacyActionKeep is a PrivacyAction of type Keep
+	PrivacyActionKeep PrivacyAction = iota + 1
+	// PrivacyActionYear is a PrivacyAction of type Year
+	PrivacyActionYear
+	// PrivacyActionInitial is a PrivacyAction of type Initial
+	PrivacyActionInitial
+	// PrivacyActionRemove is a PrivacyAction of type Remove
+	PrivacyActionRemove
+)
+
+const _PrivacyActionName = "keepyearinitialremove"
+
+var _PrivacyActionMap = map[PrivacyAction]string{
+	1: _PrivacyActionName[0:4],
+	2: _PrivacyActionName[4:8],
+	3: _PrivacyActionName[8:15],
+	4: _PrivacyActionName[15:21],
+}
+
+// String implements the Stringer interface.
+func (x PrivacyAction) String() string {
+	if str, ok := _PrivacyActionMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("PrivacyAction(%d)", x)
+}
+
+var _PrivacyActionValue = map[string]PrivacyAction{
+	_PrivacyActionName[0:4]:   1,
+	_PrivacyActionName[4:8]:   2,
+	_PrivacyActionName[8:15]:  3,
+	_PrivacyActionName[15:21]: 4,
+}
+
+// ParsePrivacyAction attempts to convert a string to a PrivacyAction
+func ParsePrivacyAction(name string) (PrivacyAction, error) {
+	if x, ok := _PrivacyActionValue[name]; ok {
+		return x, nil
+	}
+	return PrivacyAction(0), fmt.Errorf("%s is not a valid PrivacyAction", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x PrivacyAction) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *PrivacyAction) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParsePrivacyAction(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// PrivacyFieldName is a PrivacyField of type Name
+	PrivacyFieldName PrivacyField = iota + 1
+	// PrivacyFieldBirth is a PrivacyField of type Birth
+	PrivacyFieldBirth
+	// PrivacyFieldBaptism is a PrivacyField of type Baptism
+	PrivacyFieldBaptism
+	// PrivacyFieldDeath is a PrivacyField of type Death
+	PrivacyFieldDeath
+	// PrivacyFieldBurial is a PrivacyField of type Burial
+	PrivacyFieldBurial
+	// PrivacyFieldEngagement is a PrivacyField of type Engagement
+	PrivacyFieldEngagement
+	// PrivacyFieldMarriage is a PrivacyField of type Marriage
+	PrivacyFieldMarriage
+	// PrivacyFieldDivorce is a PrivacyField of type Divorce
+	PrivacyFieldDivorce
+	// PrivacyFieldJobs is a PrivacyField of type Jobs
+	PrivacyFieldJobs
+	// PrivacyFieldResidences is a PrivacyField of type Residences
+	PrivacyFieldResidences
+	// PrivacyFieldBiography is a PrivacyField of type Biography
+	PrivacyFieldBiography
+	// PrivacyFieldFloruit is a PrivacyField of type Floruit
+	PrivacyFieldFloruit
+	// PrivacyFieldComment is a PrivacyField of type Comment
+	PrivacyFieldComment
+	// PrivacyFieldImage is a PrivacyField of type Image
+	PrivacyFieldImage
+	// PrivacyFieldSources is a PrivacyField of type Sources
+	PrivacyFieldSources
+)
+
+const _PrivacyFieldName = "namebirthbaptismdeathburialengagementmarriagedivorcejobsresidencesbiographyfloruitcommentimagesources"
+
+var _PrivacyFieldMap = map[PrivacyField]string{
+	1:  _PrivacyFieldName[0:4],
+	2:  _PrivacyFieldName[4:9],
+	3:  _PrivacyFieldName[9:16],
+	4:  _PrivacyFieldName[16:21],
+	5:  _PrivacyFieldName[21:27],
+	6:  _PrivacyFieldName[27:37],
+	7:  _PrivacyFieldName[37:45],
+	8:  _PrivacyFieldName[45:52],
+	9:  _PrivacyFieldName[52:56],
+	10: _PrivacyFieldName[56:66],
+	11: _PrivacyFieldName[66:75],
+	12: _PrivacyFieldName[75:82],
+	13: _PrivacyFieldName[82:89],
+	14: _PrivacyFieldName[89:94],
+	15: _PrivacyFieldName[94:101],
+}
+
+// String implements the Stringer interface.
+func (x PrivacyField) String() string {
+	if str, ok := _PrivacyFieldMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("PrivacyField(%d)", x)
+}
+
+var _PrivacyFieldValue = map[string]PrivacyField{
+	_PrivacyFieldName[0:4]:    1,
+	_PrivacyFieldName[4:9]:    2,
+	_PrivacyFieldName[9:16]:   3,
+	_PrivacyFieldName[16:21]:  4,
+	_PrivacyFieldName[21:27]:  5,
+	_PrivacyFieldName[27:37]:  6,
+	_PrivacyFieldName[37:45]:  7,
+	_PrivacyFieldName[45:52]:  8,
+	_PrivacyFieldName[52:56]:  9,
+	_PrivacyFieldName[56:66]:  10,
+	_PrivacyFieldName[66:75]:  11,
+	_PrivacyFieldName[75:82]:  12,
+	_PrivacyFieldName[82:89]:  13,
+	_PrivacyFieldName[89:94]:  14,
+	_PrivacyFieldName[94:101]: 15,
+}
+
+// ParsePrivacyField attempts to convert a string to a PrivacyField
+func ParsePrivacyField(name string) (PrivacyField, error) {
+	if x, ok := _PrivacyFieldValue[name]; ok {
+		return x, nil
+	}
+	return PrivacyField(0), fmt.Errorf("%s is not a valid PrivacyField", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x PrivacyField) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *PrivacyField) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParsePrivacyField(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
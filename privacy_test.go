@@ -0,0 +1,186 @@
+package generations
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPrivatePersons(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "relationship.yml"))
+	assert.Nil(t, err)
+	set := func(id string, f func(p *FlatPerson)) {
+		p, err := database.GetByID(id)
+		assert.Nil(t, err)
+		f(p.(*FlatPerson))
+	}
+	set("vater", func(p *FlatPerson) { p.Death = DatePlace{Date: "1980"} })
+	set("mutter", func(p *FlatPerson) { p.Birth = DatePlace{Date: "1890"} })
+	set("grossvater", func(p *FlatPerson) { p.Birth = DatePlace{Date: "1850"} })
+	set("grossmutter", func(p *FlatPerson) { p.Birth = DatePlace{Date: "1855"} })
+	set("gauss", func(p *FlatPerson) { p.Birth = DatePlace{Date: "1950"} })
+	set("sohn", func(p *FlatPerson) { p.Death = DatePlace{Date: "1999"} })
+	set("neffe", func(p *FlatPerson) { p.Death = DatePlace{Date: "1990"} })
+	set("fremd", func(p *FlatPerson) {
+		p.Private = true
+		p.Death = DatePlace{Date: "1800"}
+	})
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	private, err := PrivacyPolicy{}.PrivatePersons(database.GetPersons(), now)
+	assert.Nil(t, err)
+	for _, id := range []string{"gauss", "sohn", "enkelin", "bruder", "fremd", "frau"} {
+		assert.True(t, private[id], id)
+	}
+	// neffe is dead and bruder living because of his unknown date of birth only
+	for _, id := range []string{"vater", "mutter", "grossvater", "grossmutter", "neffe"} {
+		assert.False(t, private[id], id)
+	}
+
+	private, err = PrivacyPolicies()[PrivacyPolicyPrivateOnly].PrivatePersons(database.GetPersons(), now)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"fremd": true}, private)
+}
+
+func TestApplyPrivacy(t *testing.T) {
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	load := func() (*MemoryDatabase, *FlatPerson) {
+		database := NewMemoryDatabase()
+		err := database.ParseYamlFile(filepath.Join("testdata", "database", "single-full-details.yml"))
+		assert.Nil(t, err)
+		p := database.Persons[0]
+		p.Birth = DatePlace{Date: "1990-03-04", Place: "Bonn"}
+		p.Baptism = DatePlace{Date: "1990-04-01"}
+		p.Jobs = Jobs{{Title: "Mathematician"}}
+		p.Comment = "Famous."
+		p.ImageFilename = "gauss.jpg"
+		p.Partners = []FlatRelationship{{PartnerID: "gauss", Marriage: DatePlace{Date: "2015-05-05", Place: "Bonn"}}}
+		return database, p
+	}
+
+	database, p := load()
+	database.Anonymize()
+	assert.Equal(t, Name{First: []string{"C."}}, p.Name)
+	assert.Equal(t, DatePlace{Date: "1990"}, p.Birth)
+	assert.Equal(t, DatePlace{}, p.Baptism)
+	assert.Empty(t, p.Jobs)
+	assert.Empty(t, p.Comment)
+	assert.Empty(t, p.ImageFilename)
+	assert.Equal(t, DatePlace{Date: "2015"}, p.Partners[0].Marriage)
+
+	database, p = load()
+	err := database.ApplyPrivacy(PrivacyPolicy{
+		Fields: map[PrivacyField]PrivacyAction{
+			PrivacyFieldName:     PrivacyActionKeep,
+			PrivacyFieldJobs:     PrivacyActionKeep,
+			PrivacyFieldMarriage: PrivacyActionRemove,
+		},
+	}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "Gauss", p.Name.Last)
+	assert.Equal(t, DatePlace{}, p.Birth)
+	assert.Len(t, p.Jobs, 1)
+	assert.Equal(t, DatePlace{}, p.Partners[0].Marriage)
+
+	database, p = load()
+	err = database.ApplyPrivacy(PrivacyPolicies()[PrivacyPolicyPrivateOnly], now)
+	assert.Nil(t, err)
+	assert.Equal(t, "Gauss", p.Name.Last)
+	assert.Equal(t, "Bonn", p.Birth.Place)
+
+	database, _ = load()
+	err = database.ApplyPrivacy(PrivacyPolicy{
+		Fields: map[PrivacyField]PrivacyAction{PrivacyFieldComment: PrivacyActionYear},
+	}, now)
+	assert.NotNil(t, err)
+}
+
+func TestPrivacyPolicyValidate(t *testing.T) {
+	tests := []struct {
+		Field  PrivacyField
+		Action PrivacyAction
+		Valid  bool
+	}{
+		{Field: PrivacyFieldName, Action: PrivacyActionInitial, Valid: true},
+		{Field: PrivacyFieldName, Action: PrivacyActionYear, Valid: false},
+		{Field: PrivacyFieldBirth, Action: PrivacyActionYear, Valid: true},
+		{Field: PrivacyFieldBirth, Action: PrivacyActionInitial, Valid: false},
+		{Field: PrivacyFieldDivorce, Action: PrivacyActionYear, Valid: true},
+		{Field: PrivacyFieldJobs, Action: PrivacyActionKeep, Valid: true},
+		{Field: PrivacyFieldJobs, Action: PrivacyActionYear, Valid: false},
+		{Field: PrivacyFieldSources, Action: PrivacyActionRemove, Valid: true},
+	}
+
+	for _, test := range tests {
+		err := PrivacyPolicy{Fields: map[PrivacyField]PrivacyAction{test.Field: test.Action}}.Validate()
+		assert.Equal(t, test.Valid, err == nil, "%s: %s", test.Field, test.Action)
+	}
+	for name, policy := range PrivacyPolicies() {
+		assert.Nil(t, policy.Validate(), name)
+	}
+}
+
+func TestPrivacyPolicyYaml(t *testing.T) {
+	var policy PrivacyPolicy
+	err := yaml.UnmarshalStrict([]byte(`
+living-years: 110
+fields:
+  name: initial
+  birth: year
+  jobs: keep
+`), &policy)
+	assert.Nil(t, err)
+	assert.Equal(t, PrivacyPolicy{
+		LivingYears: 110,
+		Fields: map[PrivacyField]PrivacyAction{
+			PrivacyFieldName:  PrivacyActionInitial,
+			PrivacyFieldBirth: PrivacyActionYear,
+			PrivacyFieldJobs:  PrivacyActionKeep,
+		},
+	}, policy)
+
+	err = yaml.UnmarshalStrict([]byte(`fields: {nickname: keep}`), &policy)
+	assert.NotNil(t, err)
+}
+
+func TestIsLiving(t *testing.T) {
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		Name     string
+		Person   FlatPerson
+		Expected bool
+	}{
+		{
+			Name:     "no dates",
+			Expected: true,
+		},
+		{
+			Name:     "born recently",
+			Person:   FlatPerson{Birth: DatePlace{Date: "1950-03-01"}},
+			Expected: true,
+		},
+		{
+			Name:     "born long ago",
+			Person:   FlatPerson{Birth: DatePlace{Date: "about 1850"}},
+			Expected: false,
+		},
+		{
+			Name:     "dead",
+			Person:   FlatPerson{Birth: DatePlace{Date: "1950"}, Death: DatePlace{Place: "Berlin"}},
+			Expected: false,
+		},
+		{
+			Name:     "buried",
+			Person:   FlatPerson{Burial: DatePlace{Date: "1990"}},
+			Expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, IsLiving(&test.Person, now, 100), test.Name)
+	}
+}
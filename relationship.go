@@ -1,6 +1,7 @@
 package generations
 
-type Relationship interface {
+// PartnerRelationship is the relationship of a person to one of its partners
+type PartnerRelationship interface {
 	GetPartner() (Person, error)
 	GetEngagement() *DatePlace
 	GetMarriage() *DatePlace
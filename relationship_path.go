@@ -0,0 +1,571 @@
+package generations
+
+import (
+	"fmt"
+	"strings"
+)
+
+//go:generate go-enum -f=relationship_path.go --marshal
+
+// RelationshipKind tells how two persons are connected
+/* ENUM(
+blood = 1
+partner
+step
+inLaw
+*/
+type RelationshipKind int
+
+// RelationshipPath describes how a person b is related to a person a
+type RelationshipPath struct {
+	Kind RelationshipKind
+	// Persons is the path from a to b, each person is a parent, child or partner of the one before
+	Persons []Person
+	// CommonAncestors are the nearest ancestors of both persons of the blood relation
+	CommonAncestors []Person
+	// Up is the number of generations from a (or its partner for in-law relations) to the common ancestors
+	Up int
+	// Down is the number of generations from the common ancestors to b (or its partner for in-law relations)
+	Down int
+	// Half is true iff the blood relation is through one of the common ancestors only
+	Half bool
+}
+
+// Relationship returns how b is related to a. Blood relations are searched along the biological parents, if there
+// is none partners, step relations and in-laws are tried.
+func Relationship(a, b Person) (RelationshipPath, error) {
+	if a.MatchesIDUUID(b.GetID(), b.GetUUID()) {
+		return RelationshipPath{Kind: RelationshipKindBlood, Persons: []Person{a}}, nil
+	}
+
+	// blood relations
+	r, ok, err := findBloodRelationship(a, b)
+	if err != nil || ok {
+		return r, err
+	}
+
+	// partners
+	isPartner, err := arePartners(a, b)
+	if err != nil {
+		return RelationshipPath{}, err
+	}
+	if isPartner {
+		return RelationshipPath{Kind: RelationshipKindPartner, Persons: []Person{a, b}}, nil
+	}
+
+	// step relations
+	r, ok, err = findStepRelationship(a, b)
+	if err != nil || ok {
+		return r, err
+	}
+
+	// in-laws: blood relatives of partners and partners of blood relatives
+	r, ok, err = findInLawRelationship(a, b)
+	if err != nil || ok {
+		return r, err
+	}
+
+	return RelationshipPath{}, fmt.Errorf("no relationship found between %s and %s", a.GetBestID(), b.GetBestID())
+}
+
+// ancestor is a person found while walking up the biological parents
+type ancestor struct {
+	distance int
+	// child is the next person on the way back down
+	child Person
+}
+
+// getAncestors returns all biological ancestors of p (including p itself) with their distance to p
+func getAncestors(p Person) (map[Person]ancestor, []Person, error) {
+	result := map[Person]ancestor{p: {}}
+	order := []Person{p}
+	for i := 0; i < len(order); i++ {
+		current := order[i]
+		mom, err := current.GetMom()
+		if err != nil {
+			return nil, nil, err
+		}
+		dad, err := current.GetDad()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, parent := range []Person{dad, mom} {
+			if parent == nil || parent.IsDummy() {
+				continue
+			}
+			if _, ok := result[parent]; ok {
+				continue
+			}
+			result[parent] = ancestor{distance: result[current].distance + 1, child: current}
+			order = append(order, parent)
+		}
+	}
+	return result, order, nil
+}
+
+func findBloodRelationship(a, b Person) (RelationshipPath, bool, error) {
+	ancestorsA, order, err := getAncestors(a)
+	if err != nil {
+		return RelationshipPath{}, false, err
+	}
+	ancestorsB, _, err := getAncestors(b)
+	if err != nil {
+		return RelationshipPath{}, false, err
+	}
+
+	var (
+		result RelationshipPath
+		found  bool
+	)
+	for _, p := range order {
+		ancestorB, ok := ancestorsB[p]
+		if !ok {
+			continue
+		}
+		up, down := ancestorsA[p].distance, ancestorB.distance
+		switch {
+		case !found || up+down < result.Up+result.Down:
+			result = RelationshipPath{
+				Kind:            RelationshipKindBlood,
+				CommonAncestors: []Person{p},
+				Up:              up,
+				Down:            down,
+			}
+			found = true
+		case up == result.Up && down == result.Down:
+			result.CommonAncestors = append(result.CommonAncestors, p)
+		}
+	}
+	if !found {
+		return result, false, nil
+	}
+
+	// path from a to the first common ancestor and down to b
+	common := result.CommonAncestors[0]
+	var up []Person
+	for p := common; p != a; p = ancestorsA[p].child {
+		up = append(up, p)
+	}
+	result.Persons = []Person{a}
+	for i := len(up) - 1; i >= 0; i-- {
+		result.Persons = append(result.Persons, up[i])
+	}
+	for p := ancestorsB[common].child; p != nil; p = ancestorsB[p].child {
+		result.Persons = append(result.Persons, p)
+	}
+
+	// half relations share one parent only while the other ones are known and different
+	if result.Up > 0 && result.Down > 0 && len(result.CommonAncestors) == 1 {
+		result.Half = hasBothParents(ancestorsA[common].child) && hasBothParents(ancestorsB[common].child)
+	}
+	return result, true, nil
+}
+
+func hasBothParents(p Person) bool {
+	return p.GetRawMom() != "" && p.GetRawDad() != ""
+}
+
+// getAllPartners returns the partners of p including those listing p as partner on their side only
+func getAllPartners(p Person) ([]Person, error) {
+	partners, err := p.GetPartners()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Person, 0, partners.Count())
+	for _, partner := range partners.GetPersons() {
+		if partner != nil {
+			result = append(result, partner)
+		}
+	}
+	flat, ok := p.(*FlatPerson)
+	if !ok || flat.Database == nil {
+		return result, nil
+	}
+outer:
+	for _, other := range flat.Database.getIndex().getListingPartners(flat) {
+		for _, partner := range result {
+			if partner == Person(other) {
+				continue outer
+			}
+		}
+		result = append(result, other)
+	}
+	return result, nil
+}
+
+// arePartners returns true iff a and b are partners
+func arePartners(a, b Person) (bool, error) {
+	partners, err := getAllPartners(a)
+	if err != nil {
+		return false, err
+	}
+	for _, partner := range partners {
+		if partner.MatchesIDUUID(b.GetID(), b.GetUUID()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getParents returns the biological parents of p
+func getParents(p Person) ([]Person, error) {
+	parents, err := p.GetParentsByType(ParentLinkTypeBiological)
+	if err != nil {
+		return nil, err
+	}
+	return parents.GetPersons(), nil
+}
+
+func findStepRelationship(a, b Person) (RelationshipPath, bool, error) {
+	step := func(up, down int, persons ...Person) (RelationshipPath, bool, error) {
+		return RelationshipPath{Kind: RelationshipKindStep, Up: up, Down: down, Persons: persons}, true, nil
+	}
+
+	// explicit step links
+	if linkType, ok := a.GetParentLinkType(b); ok && linkType == ParentLinkTypeStep {
+		return step(1, 0, a, b)
+	}
+	if linkType, ok := b.GetParentLinkType(a); ok && linkType == ParentLinkTypeStep {
+		return step(0, 1, a, b)
+	}
+
+	parentsA, err := getParents(a)
+	if err != nil {
+		return RelationshipPath{}, false, err
+	}
+	parentsB, err := getParents(b)
+	if err != nil {
+		return RelationshipPath{}, false, err
+	}
+
+	// partner of a parent
+	for _, parent := range parentsA {
+		ok, err := arePartners(parent, b)
+		if err != nil {
+			return RelationshipPath{}, false, err
+		}
+		if ok {
+			return step(1, 0, a, parent, b)
+		}
+	}
+	// child of a partner
+	for _, parent := range parentsB {
+		ok, err := arePartners(a, parent)
+		if err != nil {
+			return RelationshipPath{}, false, err
+		}
+		if ok {
+			return step(0, 1, a, parent, b)
+		}
+	}
+	// child of a partner of a parent
+	for _, parentA := range parentsA {
+		for _, parentB := range parentsB {
+			ok, err := arePartners(parentA, parentB)
+			if err != nil {
+				return RelationshipPath{}, false, err
+			}
+			if ok {
+				return step(1, 1, a, parentA, parentB, b)
+			}
+		}
+	}
+	return RelationshipPath{}, false, nil
+}
+
+func findInLawRelationship(a, b Person) (RelationshipPath, bool, error) {
+	var (
+		result RelationshipPath
+		found  bool
+	)
+	use := func(r RelationshipPath) {
+		if !found || r.Up+r.Down < result.Up+result.Down {
+			r.Kind = RelationshipKindInLaw
+			result = r
+			found = true
+		}
+	}
+
+	// blood relatives of partners
+	partners, err := getAllPartners(a)
+	if err != nil {
+		return result, false, err
+	}
+	for _, partner := range partners {
+		r, ok, err := findBloodRelationship(partner, b)
+		if err != nil {
+			return result, false, err
+		}
+		if ok {
+			r.Persons = append([]Person{a}, r.Persons...)
+			use(r)
+		}
+	}
+
+	// partners of blood relatives
+	partners, err = getAllPartners(b)
+	if err != nil {
+		return result, false, err
+	}
+	for _, partner := range partners {
+		r, ok, err := findBloodRelationship(a, partner)
+		if err != nil {
+			return result, false, err
+		}
+		if ok {
+			r.Persons = append(r.Persons, b)
+			use(r)
+		}
+	}
+	return result, found, nil
+}
+
+// String returns the English name of the relationship
+func (r RelationshipPath) String() string {
+	return r.Name(LanguageEn)
+}
+
+// Name returns the name of the relationship, i.e. what b is to a, in the given language
+func (r RelationshipPath) Name(language Language) string {
+	if len(r.Persons) == 0 {
+		return ""
+	}
+	gender := r.Persons[len(r.Persons)-1].GetGender()
+	if language == LanguageDe {
+		return relationshipNameGerman(r, gender)
+	}
+	return relationshipNameEnglish(r, gender)
+}
+
+// Format returns the name of the relationship in the given locale. Messages keyed "relationship." and the English
+// name override it, e.g. "relationship.grandfather: Opa".
+func (r RelationshipPath) Format(l Locale) string {
+	if message, ok := l.Messages["relationship."+r.Name(LanguageEn)]; ok {
+		return message
+	}
+	return r.Name(l.GetLanguage())
+}
+
+// gendered selects the word for the given gender
+func gendered(gender Gender, male, female, neutral string) string {
+	switch gender {
+	case GenderMale:
+		return male
+	case GenderFemale:
+		return female
+	}
+	return neutral
+}
+
+func relationshipNameEnglish(r RelationshipPath, g Gender) string {
+	switch r.Kind {
+	case RelationshipKindPartner:
+		return "partner"
+	case RelationshipKindStep:
+		switch {
+		case r.Up == 1 && r.Down == 0:
+			return gendered(g, "stepfather", "stepmother", "stepparent")
+		case r.Up == 0 && r.Down == 1:
+			return gendered(g, "stepson", "stepdaughter", "stepchild")
+		default:
+			return gendered(g, "stepbrother", "stepsister", "stepsibling")
+		}
+	case RelationshipKindInLaw:
+		r.Kind = RelationshipKindBlood
+		name := relationshipNameEnglish(r, g)
+		if r.Up+r.Down == 1 || (r.Up == 1 && r.Down == 1) {
+			return name + "-in-law"
+		}
+		return name + " by marriage"
+	}
+
+	up, down := r.Up, r.Down
+	var name string
+	switch {
+	case up == 0 && down == 0:
+		return "self"
+	case down == 0:
+		if up == 1 {
+			return gendered(g, "father", "mother", "parent")
+		}
+		return greatPrefixEnglish(up-2) + gendered(g, "grandfather", "grandmother", "grandparent")
+	case up == 0:
+		if down == 1 {
+			return gendered(g, "son", "daughter", "child")
+		}
+		return greatPrefixEnglish(down-2) + gendered(g, "grandson", "granddaughter", "grandchild")
+	case up == 1 && down == 1:
+		name = gendered(g, "brother", "sister", "sibling")
+	case down == 1:
+		name = prefixAlternatives(greatPrefixEnglish(up-2), gendered(g, "uncle", "aunt", "uncle or aunt"), " or ")
+	case up == 1:
+		name = prefixAlternatives(greatPrefixEnglish(down-2), gendered(g, "nephew", "niece", "nephew or niece"), " or ")
+	default:
+		degree, removed := cousinDegree(up, down)
+		name = ordinalEnglish(degree) + " cousin"
+		switch removed {
+		case 0:
+		case 1:
+			name += " once removed"
+		case 2:
+			name += " twice removed"
+		default:
+			name += fmt.Sprintf(" %d times removed", removed)
+		}
+	}
+	if r.Half {
+		name = prefixAlternatives("half-", name, " or ")
+	}
+	return name
+}
+
+func greatPrefixEnglish(count int) string {
+	switch {
+	case count <= 0:
+		return ""
+	case count <= 2:
+		return strings.Repeat("great-", count)
+	}
+	return numericOrdinalEnglish(count) + " great-"
+}
+
+func ordinalEnglish(n int) string {
+	words := []string{"", "first", "second", "third"}
+	if n < len(words) {
+		return words[n]
+	}
+	return numericOrdinalEnglish(n)
+}
+
+func numericOrdinalEnglish(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+func relationshipNameGerman(r RelationshipPath, g Gender) string {
+	switch r.Kind {
+	case RelationshipKindPartner:
+		return gendered(g, "Partner", "Partnerin", "Partner")
+	case RelationshipKindStep:
+		switch {
+		case r.Up == 1 && r.Down == 0:
+			return gendered(g, "Stiefvater", "Stiefmutter", "Stiefelternteil")
+		case r.Up == 0 && r.Down == 1:
+			return gendered(g, "Stiefsohn", "Stieftochter", "Stiefkind")
+		default:
+			return gendered(g, "Stiefbruder", "Stiefschwester", "Stiefgeschwister")
+		}
+	case RelationshipKindInLaw:
+		switch {
+		case r.Up == 1 && r.Down == 0:
+			return gendered(g, "Schwiegervater", "Schwiegermutter", "Schwiegerelternteil")
+		case r.Up == 0 && r.Down == 1:
+			return gendered(g, "Schwiegersohn", "Schwiegertochter", "Schwiegerkind")
+		case r.Up == 1 && r.Down == 1 && !r.Half:
+			return gendered(g, "Schwager", "Schwägerin", "Schwager oder Schwägerin")
+		}
+		r.Kind = RelationshipKindBlood
+		return relationshipNameGerman(r, g) + " (angeheiratet)"
+	}
+
+	up, down := r.Up, r.Down
+	var name string
+	switch {
+	case up == 0 && down == 0:
+		return "selbst"
+	case down == 0:
+		if up == 1 {
+			return gendered(g, "Vater", "Mutter", "Elternteil")
+		}
+		return compoundGerman(urPrefixGerman(up-2), gendered(g, "Großvater", "Großmutter", "Großelternteil"))
+	case up == 0:
+		if down == 1 {
+			return gendered(g, "Sohn", "Tochter", "Kind")
+		}
+		return compoundGerman(urPrefixGerman(down-2), gendered(g, "Enkel", "Enkelin", "Enkelkind"))
+	case up == 1 && down == 1:
+		name = gendered(g, "Bruder", "Schwester", "Geschwister")
+	case down == 1:
+		name = compoundGerman(grossPrefixGerman(up-2), gendered(g, "Onkel", "Tante", "Onkel oder Tante"))
+	case up == 1:
+		name = compoundGerman(grossPrefixGerman(down-2), gendered(g, "Neffe", "Nichte", "Neffe oder Nichte"))
+	default:
+		degree, removed := cousinDegree(up, down)
+		name = gendered(g, "Cousin", "Cousine", "Cousin oder Cousine")
+		if degree > 1 {
+			name += fmt.Sprintf(" %d. Grades", degree)
+		}
+		switch removed {
+		case 0:
+		case 1:
+			name += ", um 1 Generation versetzt"
+		default:
+			name += fmt.Sprintf(", um %d Generationen versetzt", removed)
+		}
+	}
+	if r.Half {
+		name = compoundGerman("Halb", name)
+	}
+	return name
+}
+
+// urPrefixGerman returns the prefix for the given number of generations beyond grandparents or grandchildren
+func urPrefixGerman(count int) string {
+	switch {
+	case count <= 0:
+		return ""
+	case count <= 3:
+		return "Ur" + strings.Repeat("ur", count-1)
+	}
+	return fmt.Sprintf("%d-fach Ur", count)
+}
+
+// grossPrefixGerman returns the prefix for the given number of generations beyond uncles or nephews
+func grossPrefixGerman(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	return compoundGerman(urPrefixGerman(count-1), "Groß")
+}
+
+// cousinDegree returns the degree of cousins and by how many generations they are removed
+func cousinDegree(up, down int) (degree, removed int) {
+	degree, removed = up-1, down-up
+	if down < up {
+		degree, removed = down-1, up-down
+	}
+	return degree, removed
+}
+
+// prefixAlternatives adds the prefix to all alternatives of a name, e.g. "uncle or aunt"
+func prefixAlternatives(prefix, name, separator string) string {
+	if prefix == "" {
+		return name
+	}
+	alternatives := strings.Split(name, separator)
+	for i, a := range alternatives {
+		alternatives[i] = prefix + a
+	}
+	return strings.Join(alternatives, separator)
+}
+
+// compoundGerman prefixes all alternatives of a German noun, e.g. "Ur" and "Großvater" become "Urgroßvater"
+func compoundGerman(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	alternatives := strings.Split(name, " oder ")
+	for i, a := range alternatives {
+		alternatives[i] = prefix + strings.ToLower(a[:1]) + a[1:]
+	}
+	return strings.Join(alternatives, " oder ")
+}
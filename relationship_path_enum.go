@@ -0,0 +1,67 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// RelationshipKindBlood is a RelationshipKind of type Blood
+	RelationshipKindBlood RelationshipKind = iota + 1
+	// RelationshipKindPartner is a RelationshipKind of type Partner
+	RelationshipKindPartner
+	// RelationshipKindStep is a RelationshipKind of type Step
+	RelationshipKindStep
+	// RelationshipKindInLaw is a RelationshipKind of type InLaw
+	RelationshipKindInLaw
+)
+
+const _RelationshipKindName = "bloodpartnerstepinLaw"
+
+var _RelationshipKindMap = map[RelationshipKind]string{
+	1: _RelationshipKindName[0:5],
+	2: _RelationshipKindName[5:12],
+	3: _RelationshipKindName[12:16],
+	4: _RelationshipKindName[16:21],
+}
+
+// String implements the Stringer interface.
+func (x RelationshipKind) String() string {
+	if str, ok := _RelationshipKindMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("RelationshipKind(%d)", x)
+}
+
+var _RelationshipKindValue = map[string]RelationshipKind{
+	_RelationshipKindName[0:5]:   1,
+	_RelationshipKindName[5:12]:  2,
+	_RelationshipKindName[12:16]: 3,
+	_RelationshipKindName[16:21]: 4,
+}
+
+// ParseRelationshipKind attempts to convert a string to a RelationshipKind
+func ParseRelationshipKind(name string) (RelationshipKind, error) {
+	if x, ok := _RelationshipKindValue[name]; ok {
+		return x, nil
+	}
+	return RelationshipKind(0), fmt.Errorf("%s is not a valid RelationshipKind", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x RelationshipKind) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *RelationshipKind) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseRelationshipKind(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
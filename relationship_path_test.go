@@ -0,0 +1,120 @@
+package generations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationship(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile("testdata/database/relationship.yml")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		From, To string
+		Kind     RelationshipKind
+		English  string
+		German   string
+		Path     []string
+	}{
+		{"gauss", "gauss", RelationshipKindBlood, "self", "selbst", []string{"gauss"}},
+		{"gauss", "vater", RelationshipKindBlood, "father", "Vater", []string{"gauss", "vater"}},
+		{"gauss", "grossvater", RelationshipKindBlood, "grandfather", "Großvater", []string{"gauss", "vater", "grossvater"}},
+		{"gauss", "urgrossmutter", RelationshipKindBlood, "great-grandmother", "Urgroßmutter", nil},
+		{"urgrossvater", "gauss", RelationshipKindBlood, "great-grandson", "Urenkel", nil},
+		{"gauss", "sohn", RelationshipKindBlood, "son", "Sohn", nil},
+		{"gauss", "enkelin", RelationshipKindBlood, "granddaughter", "Enkelin", []string{"gauss", "sohn", "enkelin"}},
+		{"gauss", "bruder", RelationshipKindBlood, "brother", "Bruder", []string{"gauss", "vater", "bruder"}},
+		{"gauss", "halbschwester", RelationshipKindBlood, "half-sister", "Halbschwester", []string{"gauss", "mutter", "halbschwester"}},
+		{"gauss", "onkel", RelationshipKindBlood, "uncle", "Onkel", []string{"gauss", "vater", "grossvater", "onkel"}},
+		{"gauss", "grosstante", RelationshipKindBlood, "great-aunt", "Großtante", nil},
+		{"gauss", "neffe", RelationshipKindBlood, "nephew", "Neffe", nil},
+		{"gauss", "cousin", RelationshipKindBlood, "first cousin", "Cousin", []string{"gauss", "vater", "grossvater", "onkel", "cousin"}},
+		{"gauss", "cousin-sohn", RelationshipKindBlood, "first cousin once removed", "Cousin, um 1 Generation versetzt", nil},
+		{"gauss", "cousin-vater", RelationshipKindBlood, "first cousin once removed", "Cousin, um 1 Generation versetzt", nil},
+		{"cousin-sohn", "cousin-vater", RelationshipKindBlood, "first cousin twice removed", "Cousin, um 2 Generationen versetzt", nil},
+		{"gauss", "frau", RelationshipKindPartner, "partner", "Partnerin", []string{"gauss", "frau"}},
+		{"frau", "gauss", RelationshipKindPartner, "partner", "Partner", nil},
+		{"gauss", "stiefsohn", RelationshipKindStep, "stepson", "Stiefsohn", []string{"gauss", "frau", "stiefsohn"}},
+		{"gauss", "stiefvater", RelationshipKindStep, "stepfather", "Stiefvater", []string{"gauss", "mutter", "stiefvater"}},
+		{"sohn", "stiefsohn", RelationshipKindBlood, "half-brother", "Halbbruder", nil},
+		{"gauss", "schwiegermutter", RelationshipKindInLaw, "mother-in-law", "Schwiegermutter", []string{"gauss", "frau", "schwiegermutter"}},
+		{"gauss", "schwaegerin", RelationshipKindInLaw, "sister-in-law", "Schwägerin", []string{"gauss", "vater", "bruder", "schwaegerin"}},
+		{"gauss", "angeheiratete-tante", RelationshipKindInLaw, "aunt by marriage", "Tante (angeheiratet)", nil},
+	}
+
+	for _, test := range tests {
+		a, err := database.Get(test.From)
+		assert.Nil(t, err)
+		b, err := database.Get(test.To)
+		assert.Nil(t, err)
+		r, err := Relationship(a, b)
+		assert.Nil(t, err, test.To)
+		assert.Equal(t, test.Kind, r.Kind, test.To)
+		assert.Equal(t, test.English, r.Name(LanguageEn), test.To)
+		assert.Equal(t, test.German, r.Name(LanguageDe), test.To)
+		if test.Path == nil {
+			continue
+		}
+		path := make([]string, len(r.Persons))
+		for i, p := range r.Persons {
+			path[i] = p.GetID()
+		}
+		assert.Equal(t, test.Path, path, test.To)
+	}
+
+	a, err := database.Get("gauss")
+	assert.Nil(t, err)
+	b, err := database.Get("fremd")
+	assert.Nil(t, err)
+	_, err = Relationship(a, b)
+	assert.EqualError(t, err, "no relationship found between gauss and fremd")
+}
+
+func TestRelationshipNames(t *testing.T) {
+	tests := []struct {
+		Up, Down int
+		Half     bool
+		Gender   Gender
+		English  string
+		German   string
+	}{
+		{5, 0, false, GenderMale, "3rd great-grandfather", "Urururgroßvater"},
+		{6, 0, false, GenderFemale, "4th great-grandmother", "4-fach Urgroßmutter"},
+		{4, 1, false, GenderUnknown, "great-great-uncle or great-great-aunt", "Urgroßonkel oder Urgroßtante"},
+		{1, 3, true, GenderFemale, "half-great-niece", "Halbgroßnichte"},
+		{5, 5, false, GenderMale, "4th cousin", "Cousin 4. Grades"},
+		{13, 13, false, GenderMale, "12th cousin", "Cousin 12. Grades"},
+		{2, 2, true, GenderUnknown, "half-first cousin", "Halbcousin oder Halbcousine"},
+	}
+	for _, test := range tests {
+		person := NewDummyFlatPerson()
+		person.Gender = test.Gender.String()
+		r := RelationshipPath{
+			Kind:    RelationshipKindBlood,
+			Persons: []Person{person},
+			Up:      test.Up,
+			Down:    test.Down,
+			Half:    test.Half,
+		}
+		assert.Equal(t, test.English, r.Name(LanguageEn))
+		assert.Equal(t, test.German, r.Name(LanguageDe))
+	}
+}
+
+func TestRelationshipFormat(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile("testdata/database/relationship.yml")
+	assert.Nil(t, err)
+	a, err := database.Get("gauss")
+	assert.Nil(t, err)
+	b, err := database.Get("grossvater")
+	assert.Nil(t, err)
+	r, err := Relationship(a, b)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Großvater", r.Format(Locale{}))
+	assert.Equal(t, "grandfather", r.Format(Locale{Language: LanguageEn}))
+	assert.Equal(t, "Opa", r.Format(NewLocale(LanguageDe, map[string]string{"relationship.grandfather": "Opa"})))
+}
@@ -15,10 +15,14 @@ type RenderPersonOptions struct {
 	NodeType         NodeType
 	TemplateFilename string
 	Date             time.Time
+	// Locale selects the language of generated texts
+	Locale Locale `yaml:"-"`
 
 	// Display filter
 	HideRootNodeHighlighting bool           `yaml:"hide-root-node-highlighting,omitempty"`
 	LastnamePolicy           LastnamePolicy `yaml:"lastname-policy,omitempty"`
+	// NameFormat is the name of the name format for person names, "tree" by default
+	NameFormat string `yaml:"name-format,omitempty"`
 
 	// Output filter
 	HideID          bool     `yaml:"hide-id,omitempty"`
@@ -34,15 +38,28 @@ type RenderPersonOptions struct {
 	HideImage       bool     `yaml:"hide-image,omitempty"`
 	HideJobs        bool     `yaml:"hide-jobs,omitempty"`
 	HideFloruit     bool     `yaml:"hide-floruit,omitempty"`
+	HideResidences  bool     `yaml:"hide-residences,omitempty"`
 	HideComment     bool     `yaml:"hide-comment,omitempty"`
 	HideEngagement  bool     `yaml:"hide-engagement,omitempty"`
 	HideMarriage    bool     `yaml:"hide-marriage,omitempty"`
 	HideMarriageAge bool     `yaml:"hide-marriage-age,omitempty"`
 	HideDivorce     bool     `yaml:"hide-divorce,omitempty"`
+	HideSources     bool     `yaml:"hide-sources,omitempty"`
 
 	// special filters
 	HidePlaces      bool `yaml:"hide-places,omitempty"`
 	HideMiddleNames bool `yaml:"hide-middle-names,omitempty"`
+	// CurrentJobOnly shows only the job held at .Date
+	CurrentJobOnly bool `yaml:"current-job-only,omitempty"`
+
+	// ImplexOf is the id of the node of the person if this node is a reference to it
+	ImplexOf string `yaml:"-"`
+	// ImplexID is the id of the reference node
+	ImplexID string `yaml:"-"`
+	// Kekule is the Kekulé number of the person in a parent tree, 0 if not shown
+	Kekule int `yaml:"-"`
+	// Links connect the node to other nodes of the tree, e.g. to the nodes of non-biological parents
+	Links []NodeLink `yaml:"-"`
 }
 
 func (o *RenderPersonOptions) SetDefaults() *RenderPersonOptions {
@@ -52,6 +69,24 @@ func (o *RenderPersonOptions) SetDefaults() *RenderPersonOptions {
 	return o
 }
 
+// GetNameFormat returns the name format for person names. HideMiddleNames and LastnamePolicy apply unless the name
+// format sets them itself.
+func (o RenderPersonOptions) GetNameFormat() (NameFormat, error) {
+	name := o.NameFormat
+	if name == "" {
+		name = NameFormatTree
+	}
+	f, err := GetNameFormat(name)
+	if err != nil {
+		return f, err
+	}
+	f.HideMiddleNames = f.HideMiddleNames || o.HideMiddleNames
+	if f.LastnamePolicy == 0 {
+		f.LastnamePolicy = o.LastnamePolicy
+	}
+	return f, nil
+}
+
 func (o *RenderPersonOptions) HideAllData() {
 	o.HideAttributes = []string{"all"}
 	o.HideGender = true
@@ -63,10 +98,38 @@ func (o *RenderPersonOptions) HideAllData() {
 	o.HideBurial = true
 	o.HideJobs = true
 	o.HideFloruit = true
+	o.HideResidences = true
+	o.HideComment = true
+	o.HideEngagement = true
+	o.HideMarriage = true
+	o.HideDivorce = true
+	o.HideSources = true
+}
+
+// Compact hides all data except for the name and gender
+func (o *RenderPersonOptions) Compact() {
+	o.HideBirth = true
+	o.HideBaptism = true
+	o.HideImage = true
+	o.HideDeath = true
+	o.HideBurial = true
+	o.HideJobs = true
+	o.HideFloruit = true
+	o.HideResidences = true
 	o.HideComment = true
 	o.HideEngagement = true
 	o.HideMarriage = true
 	o.HideDivorce = true
+	o.HideSources = true
+}
+
+// GetAttributes returns the attributes of the node for p
+func (o RenderPersonOptions) GetAttributes(p Person) []string {
+	attributes := p.GetAttributes()
+	if o.ImplexOf == "" {
+		return attributes
+	}
+	return append(attributes[:len(attributes):len(attributes)], "implex")
 }
 
 func (o *RenderPersonOptions) HideImageByLevel(treeOptions RenderTreeOptions, currentLevel int) *RenderPersonOptions {
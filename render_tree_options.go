@@ -1,11 +1,12 @@
 package generations
 
-//go:generate go-enum -f=render_tree_options.go
+//go:generate go-enum -f=render_tree_options.go --marshal
 
 /* ENUM(
 parent = 1
 child
 sandclock
+connection
 */
 type GraphType int
 
@@ -21,6 +22,8 @@ type RenderTreeOptions struct {
 	RenderPersonOptions *RenderPersonOptions `yaml:"render-person-options,omitempty"`
 
 	TemplateFilenameTree               string `yaml:"template-filename-tree,omitempty"`
+	TemplateFilenameTreeParent         string `yaml:"template-filename-tree-parent,omitempty"`
+	TemplateFilenameTreeChild          string `yaml:"template-filename-tree-child,omitempty"`
 	TemplateFilenamePerson             string `yaml:"template-filename-person,omitempty"`
 	TemplateFilenameParentTree         string `yaml:"template-filename-parent-tree,omitempty"`
 	TemplateFilenameParentTreeHeadless string `yaml:"template-filename-parent-tree-headless,omitempty"`
@@ -29,10 +32,13 @@ type RenderTreeOptions struct {
 
 	FailForIDLookup bool
 
-	GraphType GraphType
+	GraphType GraphType `yaml:"graph-type,omitempty"`
 
 	GenderOrder GenderOrder
 
+	// ParentLinkTypes are the types of parent-child links to follow, biological only by default
+	ParentLinkTypes []ParentLinkType `yaml:"parent-link-types,omitempty"`
+
 	// levels
 	Levels []AbsoluteLevel `yaml:"-"`
 
@@ -45,9 +51,38 @@ type RenderTreeOptions struct {
 	MinImageLevel                *int     `yaml:"min-image-level,omitempty"`
 	MaxImageLevel                *int     `yaml:"max-image-level,omitempty"`
 
+	// HideImplex disables the detection of persons appearing several times in a tree, they are rendered in full then
+	HideImplex bool `yaml:"hide-implex,omitempty"`
+	// Implex collects the persons appearing several times in the tree rendered by RenderGenealogytree
+	Implex *ImplexStatistics `yaml:"-"`
+
+	// ShowKekule adds the Kekulé numbers of the persons to the nodes of parent trees
+	ShowKekule bool `yaml:"show-kekule,omitempty"`
+
+	// ShowConnectionSiblings renders the siblings along the lines of connection graphs
+	ShowConnectionSiblings bool `yaml:"show-connection-siblings,omitempty"`
+
 	HideFamilyIDs bool `yaml:"-"`
 }
 
+// TemplateFilenames returns the names of all templates set in the options
+func (o RenderTreeOptions) TemplateFilenames() []string {
+	result := []string{
+		o.TemplateFilenameTree,
+		o.TemplateFilenameTreeParent,
+		o.TemplateFilenameTreeChild,
+		o.TemplateFilenamePerson,
+		o.TemplateFilenameParentTree,
+		o.TemplateFilenameParentTreeHeadless,
+		o.TemplateFilenameChildTree,
+		o.TemplateFilenameUnionTree,
+	}
+	if o.RenderPersonOptions != nil {
+		result = append(result, o.RenderPersonOptions.TemplateFilename)
+	}
+	return result
+}
+
 func (o *RenderTreeOptions) SetDefaults() *RenderTreeOptions {
 	// default generation limits
 	maxGenerations := 1000
@@ -65,9 +100,15 @@ func (o *RenderTreeOptions) SetDefaults() *RenderTreeOptions {
 	}
 
 	// other defaults
+	if o.GraphType == 0 {
+		o.GraphType = GraphTypeSandclock
+	}
 	if o.GenderOrder == 0 {
 		o.GenderOrder = GenderOrderMaleFirst
 	}
+	if len(o.ParentLinkTypes) == 0 {
+		o.ParentLinkTypes = []ParentLinkType{ParentLinkTypeBiological}
+	}
 
 	// RenderPersonOptions need to be initialized too
 	if o.RenderPersonOptions == nil {
@@ -42,6 +42,22 @@ func ParseGenderOrder(name string) (GenderOrder, error) {
 	return GenderOrder(0), fmt.Errorf("%s is not a valid GenderOrder", name)
 }
 
+// MarshalText implements the text marshaller method
+func (x GenderOrder) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *GenderOrder) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseGenderOrder(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
 const (
 	// GraphTypeParent is a GraphType of type Parent
 	GraphTypeParent GraphType = iota + 1
@@ -49,14 +65,17 @@ const (
 	GraphTypeChild
 	// GraphTypeSandclock is a GraphType of type Sandclock
 	GraphTypeSandclock
+	// GraphTypeConnection is a GraphType of type Connection
+	GraphTypeConnection
 )
 
-const _GraphTypeName = "parentchildsandclock"
+const _GraphTypeName = "parentchildsandclockconnection"
 
 var _GraphTypeMap = map[GraphType]string{
 	1: _GraphTypeName[0:6],
 	2: _GraphTypeName[6:11],
 	3: _GraphTypeName[11:20],
+	4: _GraphTypeName[20:30],
 }
 
 // String implements the Stringer interface.
@@ -71,6 +90,7 @@ var _GraphTypeValue = map[string]GraphType{
 	_GraphTypeName[0:6]:   1,
 	_GraphTypeName[6:11]:  2,
 	_GraphTypeName[11:20]: 3,
+	_GraphTypeName[20:30]: 4,
 }
 
 // ParseGraphType attempts to convert a string to a GraphType
@@ -80,3 +100,19 @@ func ParseGraphType(name string) (GraphType, error) {
 	}
 	return GraphType(0), fmt.Errorf("%s is not a valid GraphType", name)
 }
+
+// MarshalText implements the text marshaller method
+func (x GraphType) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *GraphType) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseGraphType(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -0,0 +1,206 @@
+package generations
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Site is a family website with a page per person and an index by surname
+type Site struct {
+	// Persons are ordered by surname and first names
+	Persons   []*SitePerson
+	Surnames  []SiteSurname
+	Generated time.Time
+}
+
+// SitePerson is the page of a person
+type SitePerson struct {
+	Person Person
+	// Page is the filename of the page
+	Page string
+	// Private persons are shown with their names and relatives only, see PrivacyPolicy.PrivatePersons
+	Private bool
+	// Image is the filename of the portrait in the site, empty if there is none or it is hidden
+	Image    string
+	Parents  []SiteParent
+	Partners []SitePartner
+}
+
+// SiteParent is a parental figure of a person
+type SiteParent struct {
+	Person *SitePerson
+	Type   ParentLinkType
+}
+
+// SitePartner is a partner of a person with their common children
+type SitePartner struct {
+	// Person is nil for the children with an unknown other parent
+	Person *SitePerson
+	// Relationship is nil if the partners are known by their children only
+	Relationship PartnerRelationship
+	Children     []*SitePerson
+}
+
+// SiteSurname are the persons with the same surname
+type SiteSurname struct {
+	Name    string
+	Persons []*SitePerson
+}
+
+// SiteOptions control which data a site shows
+type SiteOptions struct {
+	// Now is the date living persons are determined at, the current time if zero
+	Now time.Time
+	// Privacy decides which persons are private, its field actions don't apply
+	Privacy PrivacyPolicy
+}
+
+var sitePageRegexp = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// NewSite builds the pages of the given persons. Relatives not in the list are left out.
+func NewSite(persons []Person, o SiteOptions) (*Site, error) {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	private, err := o.Privacy.PrivatePersons(persons, o.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Site{
+		Generated: o.Now,
+	}
+	index := make(map[string]*SitePerson, len(persons))
+	pages := make(map[string]bool, len(persons))
+	for _, p := range persons {
+		sp := &SitePerson{
+			Person:  p,
+			Page:    sitePage(p, pages),
+			Private: private[p.GetBestID()],
+		}
+		if image := p.GetImageFilename(); image != "" && !sp.Private {
+			sp.Image = filepath.ToSlash(filepath.Join("images", strings.TrimSuffix(sp.Page, ".html")+filepath.Ext(image)))
+		}
+		index[p.GetBestID()] = sp
+		s.Persons = append(s.Persons, sp)
+	}
+
+	for _, sp := range s.Persons {
+		err = sp.addRelatives(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(s.Persons, func(i, j int) bool {
+		return siteSortKey(s.Persons[i].Person) < siteSortKey(s.Persons[j].Person)
+	})
+	for _, sp := range s.Persons {
+		surname := sp.Person.GetName().Last
+		if len(s.Surnames) == 0 || s.Surnames[len(s.Surnames)-1].Name != surname {
+			s.Surnames = append(s.Surnames, SiteSurname{Name: surname})
+		}
+		last := &s.Surnames[len(s.Surnames)-1]
+		last.Persons = append(last.Persons, sp)
+	}
+	return s, nil
+}
+
+func (sp *SitePerson) addRelatives(index map[string]*SitePerson) error {
+	p := sp.Person
+	for _, linkType := range []ParentLinkType{ParentLinkTypeBiological, ParentLinkTypeAdoptive, ParentLinkTypeStep, ParentLinkTypeFoster} {
+		parents, err := p.GetParentsByType(linkType)
+		if err != nil {
+			return err
+		}
+		for _, parent := range parents.GetPersons() {
+			if parentPage, ok := index[parent.GetBestID()]; ok {
+				sp.Parents = append(sp.Parents, SiteParent{Person: parentPage, Type: linkType})
+			}
+		}
+	}
+
+	partners, err := p.GetPartners()
+	if err != nil {
+		return err
+	}
+	listed := make(map[string]bool)
+	for _, partner := range partners.GetPersons() {
+		partnerPage, ok := index[partner.GetBestID()]
+		if !ok {
+			continue
+		}
+		children, err := p.GetChildrenWith(partner)
+		if err != nil {
+			return err
+		}
+		union := SitePartner{
+			Person:       partnerPage,
+			Relationship: siteRelationship(p, partner),
+			Children:     sitePersons(children, index, listed),
+		}
+		sp.Partners = append(sp.Partners, union)
+	}
+
+	// children with an unknown or left out other parent
+	children, err := p.GetChildren()
+	if err != nil {
+		return err
+	}
+	if others := sitePersons(children, index, listed); len(others) > 0 {
+		sp.Partners = append(sp.Partners, SitePartner{Children: others})
+	}
+	return nil
+}
+
+// sitePersons returns the pages of the persons in the list that are not listed yet and marks them as listed
+func sitePersons(persons PersonList, index map[string]*SitePerson, listed map[string]bool) []*SitePerson {
+	var result []*SitePerson
+	for _, p := range persons.GetPersons() {
+		id := p.GetBestID()
+		if page, ok := index[id]; ok && !listed[id] {
+			listed[id] = true
+			result = append(result, page)
+		}
+	}
+	return result
+}
+
+func siteRelationship(p, partner Person) PartnerRelationship {
+	for _, r := range p.GetRelationships() {
+		other, err := r.GetPartner()
+		if err == nil && other.GetBestID() == partner.GetBestID() {
+			return r
+		}
+	}
+	for _, r := range partner.GetRelationships() {
+		other, err := r.GetPartner()
+		if err == nil && other.GetBestID() == p.GetBestID() {
+			return r
+		}
+	}
+	return nil
+}
+
+// sitePage returns a unique filename for the page of p, used contains the filenames taken already
+func sitePage(p Person, used map[string]bool) string {
+	base := strings.Trim(sitePageRegexp.ReplaceAllString(p.GetBestID(), "-"), "-")
+	if base == "" {
+		base = "person"
+	}
+	page := base + ".html"
+	for i := 2; used[page]; i++ {
+		page = base + "-" + strconv.Itoa(i) + ".html"
+	}
+	used[page] = true
+	return page
+}
+
+func siteSortKey(p Person) string {
+	name := p.GetName()
+	return strings.ToLower(name.Last + "\x00" + strings.Join(name.First, " ") + "\x00" + string(p.GetBirth().Date.YearOnly()))
+}
@@ -0,0 +1,61 @@
+package generations
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSite(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "relationship.yml"))
+	assert.Nil(t, err)
+	gauss, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+	gauss.(*FlatPerson).Birth = DatePlace{Date: "1777"}
+	gauss.(*FlatPerson).ImageFilename = "portraits/gauss.jpg"
+
+	s, err := NewSite(database.GetPersons(), SiteOptions{Now: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)})
+	assert.Nil(t, err)
+	assert.Len(t, s.Persons, len(database.Persons))
+	assert.Len(t, s.Surnames, 1)
+
+	pages := make(map[string]*SitePerson)
+	for _, p := range s.Persons {
+		pages[p.Person.GetBestID()] = p
+	}
+	p := pages["gauss"]
+	assert.Equal(t, "gauss.html", p.Page)
+	assert.False(t, p.Private)
+	assert.Equal(t, "images/gauss.jpg", p.Image)
+	assert.Equal(t, []SiteParent{
+		{Person: pages["mutter"], Type: ParentLinkTypeBiological},
+		{Person: pages["vater"], Type: ParentLinkTypeBiological},
+	}, p.Parents)
+	if assert.Len(t, p.Partners, 1) {
+		assert.Equal(t, pages["frau"], p.Partners[0].Person)
+		assert.NotNil(t, p.Partners[0].Relationship)
+		assert.Equal(t, []*SitePerson{pages["sohn"]}, p.Partners[0].Children)
+	}
+
+	// persons without dates are considered living
+	assert.True(t, pages["bruder"].Private)
+	// children with an unknown other parent are listed separately
+	p = pages["bruder"]
+	if assert.Len(t, p.Partners, 2) {
+		assert.Equal(t, pages["schwaegerin"], p.Partners[0].Person)
+		assert.Empty(t, p.Partners[0].Children)
+		assert.Nil(t, p.Partners[1].Person)
+		assert.Equal(t, []*SitePerson{pages["neffe"]}, p.Partners[1].Children)
+	}
+}
+
+func TestSitePage(t *testing.T) {
+	used := make(map[string]bool)
+	assert.Equal(t, "gauss.html", sitePage(&FlatPerson{ID: "gauss"}, used))
+	assert.Equal(t, "gauss-2.html", sitePage(&FlatPerson{ID: "gauss"}, used))
+	assert.Equal(t, "G-hlMela1954.html", sitePage(&FlatPerson{ID: "GöhlMela1954"}, used))
+	assert.Equal(t, "person.html", sitePage(&FlatPerson{ID: "../"}, used))
+}
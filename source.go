@@ -0,0 +1,69 @@
+package generations
+
+import "github.com/jojomi/strtpl"
+
+//go:generate go-enum -f=source.go --marshal
+
+// ReferenceQuality rates how reliable the evidence of a reference is (like GEDCOM's QUAY)
+/* ENUM(
+unreliable = 1
+questionable
+secondary
+primary
+*/
+type ReferenceQuality int
+
+// Source is a record genealogical data was taken from, e.g. a church book, a civil register or a family bible
+type Source struct {
+	ID         string `yaml:"id,omitempty"`
+	Title      string `yaml:"title,omitempty"`
+	Author     string `yaml:"author,omitempty"`
+	Archive    string `yaml:"archive,omitempty"`
+	CallNumber string `yaml:"call_number,omitempty"`
+	URL        string `yaml:"url,omitempty"`
+	Repository string `yaml:"repository,omitempty"`
+}
+
+// Reference cites a Source for a person or for a single fact like a birth or a marriage
+type Reference struct {
+	SourceID   string           `yaml:"source_id,omitempty"`
+	Page       string           `yaml:"page,omitempty"`
+	Quality    ReferenceQuality `yaml:"quality,omitempty"`
+	Transcript string           `yaml:"transcript,omitempty"`
+}
+
+// IsEmpty returns true iff the source has no data that could be displayed
+func (s Source) IsEmpty() bool {
+	return s.Title == "" && s.Author == "" && s.Archive == "" && s.CallNumber == "" && s.URL == "" && s.Repository == ""
+}
+
+// Format displays the source in a single line, e.g. "Author: Title. Repository. Archive, call number. URL"
+func (s Source) Format() string {
+	return strtpl.MustEval(
+		`{{- with .Author }}{{ . }}: {{ end -}}
+		{{- .Title -}}
+		{{- with .Repository }}. {{ . }}{{ end -}}
+		{{- with .Archive }}. {{ . }}{{ end -}}
+		{{- with .CallNumber }}, {{ . }}{{ end -}}
+		{{- with .URL }}. {{ . }}{{ end -}}
+		`,
+		s,
+	)
+}
+
+// GetSourceIDs returns the IDs of the sources cited by the given references, without duplicates
+func GetSourceIDs(references []Reference) []string {
+	result := make([]string, 0, len(references))
+	seen := make(map[string]struct{}, len(references))
+	for _, r := range references {
+		if r.SourceID == "" {
+			continue
+		}
+		if _, ok := seen[r.SourceID]; ok {
+			continue
+		}
+		seen[r.SourceID] = struct{}{}
+		result = append(result, r.SourceID)
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// ReferenceQualityUnreliable is a ReferenceQuality of type Unreliable
+	ReferenceQualityUnreliable ReferenceQuality = iota + 1
+	// ReferenceQualityQuestionable is a ReferenceQuality of type Questionable
+	ReferenceQualityQuestionable
+	// ReferenceQualitySecondary is a ReferenceQuality of type Secondary
+	ReferenceQualitySecondary
+	// ReferenceQualityPrimary is a ReferenceQuality of type Primary
+	ReferenceQualityPrimary
+)
+
+const _ReferenceQualityName = "unreliablequestionablesecondaryprimary"
+
+var _ReferenceQualityMap = map[ReferenceQuality]string{
+	1: _ReferenceQualityName[0:10],
+	2: _ReferenceQualityName[10:22],
+	3: _ReferenceQualityName[22:31],
+	4: _ReferenceQualityName[31:38],
+}
+
+// String implements the Stringer interface.
+func (x ReferenceQuality) String() string {
+	if str, ok := _ReferenceQualityMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("ReferenceQuality(%d)", x)
+}
+
+var _ReferenceQualityValue = map[string]ReferenceQuality{
+	_ReferenceQualityName[0:10]:  1,
+	_ReferenceQualityName[10:22]: 2,
+	_ReferenceQualityName[22:31]: 3,
+	_ReferenceQualityName[31:38]: 4,
+}
+
+// ParseReferenceQuality attempts to convert a string to a ReferenceQuality
+func ParseReferenceQuality(name string) (ReferenceQuality, error) {
+	if x, ok := _ReferenceQualityValue[name]; ok {
+		return x, nil
+	}
+	return ReferenceQuality(0), fmt.Errorf("%s is not a valid ReferenceQuality", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x ReferenceQuality) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *ReferenceQuality) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseReferenceQuality(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
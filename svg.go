@@ -0,0 +1,580 @@
+package generations
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	svgFontSize    = 11.0
+	svgLineHeight  = 14.0
+	svgCharWidth   = 6.0 // average width of a character at svgFontSize
+	svgPadding     = 6.0
+	svgBoxMinWidth = 100.0
+	svgBoxMaxWidth = 200.0
+	svgGapX        = 14.0
+	svgGapY        = 40.0
+	svgMargin      = 10.0
+)
+
+// svgBox is a person in an SVG graph
+type svgBox struct {
+	node  PersonNode
+	lines []string
+	// generation is relative to the root, positive for ancestors
+	generation int
+	// side boxes are siblings in parent trees and partners in child trees, they get the leaf color of their level
+	side bool
+	x, y float64
+}
+
+// svgUnit is a row of boxes with the units above and below it that are connected to it
+type svgUnit struct {
+	boxes    []*svgBox
+	up, down []*svgGroup
+}
+
+// svgGroup are the units connected to some boxes of a unit, e.g. the children of a couple
+type svgGroup struct {
+	from  []*svgBox
+	units []*svgUnit
+	// targets are the boxes of the units that are connected
+	targets []*svgBox
+}
+
+type svgLayout struct {
+	options      RenderTreeOptions
+	probandLevel int
+	boxes        []*svgBox
+	boxWidth     float64
+	boxHeight    float64
+	buffer       bytes.Buffer
+}
+
+// SVG renders the model as a standalone SVG image. The person boxes show the data selected by the person options and
+// are filled with the level colors, probandLevel is the level of the root.
+func (m *TreeModel) SVG(probandLevel int) ([]byte, error) {
+	l := &svgLayout{
+		options:      m.Options,
+		probandLevel: probandLevel,
+	}
+	root := l.build(m)
+	if root == nil {
+		return []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="0" height="0"></svg>` + "\n"), nil
+	}
+
+	// all boxes have the same size
+	l.boxWidth = svgBoxMinWidth
+	lines := 1
+	for _, b := range l.boxes {
+		for _, line := range b.lines {
+			l.boxWidth = math.Max(l.boxWidth, float64(len([]rune(line)))*svgCharWidth+2*svgPadding)
+		}
+		if len(b.lines) > lines {
+			lines = len(b.lines)
+		}
+	}
+	l.boxWidth = math.Min(l.boxWidth, svgBoxMaxWidth)
+	l.boxHeight = float64(lines)*svgLineHeight + 2*svgPadding
+
+	width := math.Max(l.width(root, true), l.width(root, false))
+	l.placeRow(root, svgMargin, width)
+	l.placeUnits(root, svgMargin, width, true)
+	l.placeUnits(root, svgMargin, width, false)
+
+	maxGeneration, minGeneration := 0, 0
+	for _, b := range l.boxes {
+		if b.generation > maxGeneration {
+			maxGeneration = b.generation
+		}
+		if b.generation < minGeneration {
+			minGeneration = b.generation
+		}
+	}
+	for _, b := range l.boxes {
+		b.y = svgMargin + float64(maxGeneration-b.generation)*(l.boxHeight+svgGapY)
+	}
+	height := float64(maxGeneration-minGeneration+1)*(l.boxHeight+svgGapY) - svgGapY
+
+	fmt.Fprintf(&l.buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s" font-family="sans-serif" font-size="%s">`+"\n",
+		svgNumber(width+2*svgMargin), svgNumber(height+2*svgMargin), svgNumber(svgFontSize))
+	l.buffer.WriteString(`<rect width="100%" height="100%" fill="white"/>` + "\n")
+	l.buffer.WriteString(`<g stroke="#555" stroke-width="1" fill="none">` + "\n")
+	l.writeConnectors(root)
+	l.buffer.WriteString("</g>\n")
+	for _, b := range l.boxes {
+		l.writeBox(b)
+	}
+	l.buffer.WriteString("</svg>\n")
+	return l.buffer.Bytes(), nil
+}
+
+// build returns the row of the root with the units above and below it
+func (l *svgLayout) build(m *TreeModel) *svgUnit {
+	switch m.Type {
+	case GraphTypeParent:
+		if m.Parent == nil {
+			return nil
+		}
+		return l.parentUnit(m.Parent, 0)
+	case GraphTypeChild, GraphTypeConnection:
+		if m.Child == nil {
+			return nil
+		}
+		return l.childUnit(m.Child, 0)
+	default:
+		if m.Child == nil {
+			return nil
+		}
+		// the child tree holds the root, its parents are in the headless parent tree
+		root := l.childUnit(m.Child, 0)
+		var older, younger []*svgBox
+		for _, n := range m.SiblingsOlder {
+			older = append(older, l.box(n, 0, false))
+		}
+		for _, n := range m.SiblingsYounger {
+			younger = append(younger, l.box(n, 0, false))
+		}
+		root.boxes = append(append(older, root.boxes...), younger...)
+		if m.Parent != nil && len(m.Parent.Parents) > 0 {
+			group := &svgGroup{
+				from: append(append(older, root.boxes[len(older)]), younger...),
+			}
+			for _, parent := range m.Parent.Parents {
+				l.addUnit(group, l.parentUnit(parent, 1))
+			}
+			root.up = []*svgGroup{group}
+		}
+		return root
+	}
+}
+
+func (l *svgLayout) parentUnit(n *ParentNode, generation int) *svgUnit {
+	u := &svgUnit{}
+	for _, s := range n.SiblingsOlder {
+		u.boxes = append(u.boxes, l.box(s, generation, true))
+	}
+	u.boxes = append(u.boxes, l.box(n.G, generation, false))
+	for _, s := range n.SiblingsYounger {
+		u.boxes = append(u.boxes, l.box(s, generation, true))
+	}
+	if len(n.Parents) == 0 {
+		return u
+	}
+	group := &svgGroup{
+		from: append([]*svgBox{}, u.boxes...),
+	}
+	for _, parent := range n.Parents {
+		l.addUnit(group, l.parentUnit(parent, generation+1))
+	}
+	u.up = []*svgGroup{group}
+	return u
+}
+
+func (l *svgLayout) childUnit(n *ChildNode, generation int) *svgUnit {
+	g := l.box(n.G, generation, false)
+	u := &svgUnit{
+		boxes: []*svgBox{g},
+	}
+	unions := append([]*UnionNode{{Partner: n.Partner, Children: n.Children}}, n.Unions...)
+	for _, union := range unions {
+		from := []*svgBox{g}
+		if union.Partner != nil {
+			partner := l.box(*union.Partner, generation, true)
+			u.boxes = append(u.boxes, partner)
+			from = append(from, partner)
+		}
+		if len(union.Children) == 0 {
+			continue
+		}
+		group := &svgGroup{
+			from: from,
+		}
+		for _, child := range union.Children {
+			l.addUnit(group, l.childUnit(child, generation-1))
+		}
+		u.down = append(u.down, group)
+	}
+	return u
+}
+
+// addUnit adds u to group, its first box in the tree direction is the one connected
+func (l *svgLayout) addUnit(group *svgGroup, u *svgUnit) {
+	group.units = append(group.units, u)
+	target := u.boxes[0]
+	for _, b := range u.boxes {
+		if !b.side {
+			target = b
+			break
+		}
+	}
+	group.targets = append(group.targets, target)
+}
+
+func (l *svgLayout) box(n PersonNode, generation int, side bool) *svgBox {
+	b := &svgBox{
+		node:       n,
+		lines:      svgLines(n),
+		generation: generation,
+		side:       side,
+	}
+	l.boxes = append(l.boxes, b)
+	return b
+}
+
+func (l *svgLayout) rowWidth(u *svgUnit) float64 {
+	return float64(len(u.boxes))*(l.boxWidth+svgGapX) - svgGapX
+}
+
+// width returns the width of u and its units in one direction
+func (l *svgLayout) width(u *svgUnit, up bool) float64 {
+	return math.Max(l.rowWidth(u), l.unitsWidth(u, up))
+}
+
+func (l *svgLayout) unitsWidth(u *svgUnit, up bool) float64 {
+	groups := u.down
+	if up {
+		groups = u.up
+	}
+	var (
+		result float64
+		count  int
+	)
+	for _, g := range groups {
+		for _, unit := range g.units {
+			result += l.width(unit, up)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return result + float64(count-1)*svgGapX
+}
+
+// placeRow centers the boxes of u in the given space
+func (l *svgLayout) placeRow(u *svgUnit, left, width float64) {
+	x := left + (width-l.rowWidth(u))/2
+	for _, b := range u.boxes {
+		b.x = x
+		x += l.boxWidth + svgGapX
+	}
+}
+
+// placeUnits centers the units of u in one direction in the given space
+func (l *svgLayout) placeUnits(u *svgUnit, left, width float64, up bool) {
+	groups := u.down
+	if up {
+		groups = u.up
+	}
+	x := left + (width-l.unitsWidth(u, up))/2
+	for _, g := range groups {
+		for _, unit := range g.units {
+			w := l.width(unit, up)
+			l.placeRow(unit, x, w)
+			l.placeUnits(unit, x, w, up)
+			x += w + svgGapX
+		}
+	}
+}
+
+// writeConnectors draws the lines from the boxes of u to its units and recurses
+func (l *svgLayout) writeConnectors(u *svgUnit) {
+	for _, g := range u.up {
+		top := g.from[0].y
+		l.writeGroup(g, top-svgGapY/2, top, g.targets[0].y+l.boxHeight)
+		for _, unit := range g.units {
+			l.writeConnectors(unit)
+		}
+	}
+	for i, g := range u.down {
+		bottom := g.from[0].y + l.boxHeight
+		// every union gets its own bar
+		bar := bottom + svgGapY*float64(i+1)/float64(len(u.down)+1)
+		l.writeGroup(g, bar, bottom, g.targets[0].y)
+		for _, unit := range g.units {
+			l.writeConnectors(unit)
+		}
+	}
+}
+
+// writeGroup connects the from boxes (edge at y fromY) and the targets (edge at y toY) by a horizontal bar at y bar
+func (l *svgLayout) writeGroup(g *svgGroup, bar, fromY, toY float64) {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	stub := func(b *svgBox, y float64, others []*svgBox) {
+		x := b.x + l.boxWidth/2
+		minX = math.Min(minX, x)
+		maxX = math.Max(maxX, x)
+		dash := ""
+		if svgLinked(b, others) {
+			dash = ` stroke-dasharray="6,3"`
+		}
+		l.writeLine(x, y, x, bar, dash)
+	}
+	for _, b := range g.from {
+		stub(b, fromY, g.targets)
+	}
+	for _, b := range g.targets {
+		stub(b, toY, g.from)
+	}
+	if maxX > minX {
+		l.writeLine(minX, bar, maxX, bar, "")
+	}
+}
+
+// svgLinked returns true if b is connected to one of others by a link, e.g. a non-biological parent link
+func svgLinked(b *svgBox, others []*svgBox) bool {
+	for _, other := range others {
+		for _, link := range b.node.Options.Links {
+			if link.From == other.node.ID() {
+				return true
+			}
+		}
+		for _, link := range other.node.Options.Links {
+			if link.From == b.node.ID() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (l *svgLayout) writeLine(x1, y1, x2, y2 float64, attributes string) {
+	fmt.Fprintf(&l.buffer, `<line x1="%s" y1="%s" x2="%s" y2="%s"%s/>`+"\n", svgNumber(x1), svgNumber(y1), svgNumber(x2), svgNumber(y2), attributes)
+}
+
+func (l *svgLayout) writeBox(b *svgBox) {
+	p := b.node.Person
+	fill, opacity := l.fill(b)
+	stroke := "#777"
+	if !b.node.Options.HideGender {
+		switch p.GetGender() {
+		case GenderMale:
+			stroke = "steelblue"
+		case GenderFemale:
+			stroke = "indianred"
+		}
+	}
+	strokeWidth, dash := "1", ""
+	for _, attribute := range b.node.Options.GetAttributes(p) {
+		switch {
+		case attribute == "rootnode":
+			strokeWidth = "2.5"
+		case attribute == "implex":
+			dash = ` stroke-dasharray="2,2"`
+		}
+	}
+
+	fmt.Fprintf(&l.buffer, `<g id="%s">`, svgEscape(b.node.ID()))
+	if name := p.GetName().FormatFullIn(b.node.Options.Locale); name != "" {
+		fmt.Fprintf(&l.buffer, `<title>%s</title>`, svgEscape(name))
+	}
+	fmt.Fprintf(&l.buffer, `<rect x="%s" y="%s" width="%s" height="%s" rx="3" fill="%s" fill-opacity="%s" stroke="%s" stroke-width="%s"%s/>`,
+		svgNumber(b.x), svgNumber(b.y), svgNumber(l.boxWidth), svgNumber(l.boxHeight), fill, svgNumber(opacity), stroke, strokeWidth, dash)
+	maxChars := int((l.boxWidth - 2*svgPadding) / svgCharWidth)
+	for i, line := range b.lines {
+		weight := ""
+		if i == 0 && !b.node.Options.HideName {
+			weight = ` font-weight="bold"`
+		}
+		fmt.Fprintf(&l.buffer, `<text x="%s" y="%s"%s>%s</text>`,
+			svgNumber(b.x+svgPadding), svgNumber(b.y+svgPadding+float64(i+1)*svgLineHeight-3), weight, svgEscape(truncate(line, maxChars)))
+	}
+	l.buffer.WriteString("</g>\n")
+}
+
+// fill returns the color and opacity of a box from the colors of its level
+func (l *svgLayout) fill(b *svgBox) (string, float64) {
+	index := l.probandLevel + b.generation
+	for _, level := range l.options.Levels {
+		if level.Index != index {
+			continue
+		}
+		color := level.Color.Main
+		if b.side && index != l.probandLevel && level.Color.Leaf != "" {
+			color = level.Color.Leaf
+		}
+		if fill, opacity, ok := svgColor(color); ok {
+			return fill, opacity
+		}
+	}
+	return "white", 1
+}
+
+// svgNameLines splits the parts of a name into the given names and the surname
+func svgNameLines(parts []NamePart, l Locale) []string {
+	var (
+		result  []string
+		line    []NamePart
+		surname bool
+	)
+	for _, part := range parts {
+		isSurname := part.Kind == NamePartKindParticle || part.Kind == NamePartKindSurname
+		continued := part.Kind == NamePartKindBirth || part.Kind == NamePartKindAlias
+		if len(line) > 0 && isSurname != surname && !continued {
+			result = append(result, formatNameParts(line, l))
+			line = nil
+		}
+		if len(line) == 0 {
+			part.Separator = ""
+			surname = isSurname
+		}
+		line = append(line, part)
+	}
+	if len(line) > 0 {
+		result = append(result, formatNameParts(line, l))
+	}
+	return result
+}
+
+// svgLines returns the text of a person box, what is hidden in the person options is left out
+func svgLines(n PersonNode) []string {
+	var (
+		o      = n.Options
+		p      = n.Person
+		result []string
+	)
+	if !o.HideName {
+		f, err := o.GetNameFormat()
+		if err != nil {
+			f = NameFormat{HideMiddleNames: o.HideMiddleNames, LastnamePolicy: o.LastnamePolicy}
+		}
+		// the small boxes show the plain names unless a name format is selected
+		if o.NameFormat == "" {
+			f.HideTitle, f.HideNick, f.HideAlias = true, true, true
+		}
+		result = append(result, svgNameLines(p.GetName().Parts(f), o.Locale)...)
+	}
+
+	event := func(symbol string, d DatePlace, suffix string) {
+		if d.Empty() {
+			return
+		}
+		line := symbol + " " + string(d.Date)
+		if !o.HidePlaces && d.Place != "" {
+			line += " " + d.Place
+		}
+		if suffix != "" {
+			line += " (" + suffix + ")"
+		}
+		result = append(result, strings.TrimSpace(line))
+	}
+	if !o.HideBirth {
+		var age string
+		if o.ShowAge && p.GetDeath().Empty() {
+			age = p.GetAge(o.Date).String()
+		}
+		event("*", p.GetBirth(), age)
+	}
+	if !o.HideBaptism {
+		event("~", p.GetBaptism(), "")
+	}
+	if !o.HideDeath {
+		var age string
+		if !o.HideBirth && !o.HideDeathAge {
+			age = p.GetDeathAge().String()
+		}
+		event("†", p.GetDeath(), age)
+	}
+	if !o.HideBurial {
+		event("⚰", p.GetBurial(), "")
+	}
+	if !o.HideJobs {
+		jobs := p.GetJobs()
+		if o.CurrentJobOnly {
+			jobs = jobs.CurrentAt(o.Date)
+		}
+		if j := jobs.Format(); j != "" {
+			result = append(result, j)
+		}
+	}
+	if !o.HideComment && p.GetComment() != "" {
+		result = append(result, p.GetComment())
+	}
+	if len(result) == 0 {
+		result = append(result, "?")
+	}
+	return result
+}
+
+func svgNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+func svgEscape(s string) string {
+	var buffer bytes.Buffer
+	xml.EscapeText(&buffer, []byte(s))
+	return buffer.String()
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length || length < 1 {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
+
+// svgColor converts a color of the LaTeX xcolor package like "DodgerBlue!20" to an SVG color and opacity. Only named
+// colors and their tints are supported, ok is false for others like mixtures.
+func svgColor(color string) (fill string, opacity float64, ok bool) {
+	parts := strings.Split(strings.TrimSpace(color), "!")
+	if len(parts) > 2 {
+		// mixtures of colors
+		return "", 0, false
+	}
+	name := strings.ToLower(parts[0])
+	if hex, known := svgCustomColors[name]; known {
+		fill = hex
+	} else if _, known := svgColorNames[name]; known {
+		fill = name
+	} else {
+		return "", 0, false
+	}
+	opacity = 1
+	if len(parts) > 1 {
+		percentage, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return "", 0, false
+		}
+		// tints are mixed with white which is the background of the image
+		opacity = math.Max(0, math.Min(percentage, 100)) / 100
+	}
+	return fill, opacity, true
+}
+
+// svgCustomColors are the colors defined in the default document template that SVG does not know
+var svgCustomColors = map[string]string{
+	"amaranth":       "#e52b50",
+	"bleudefrance":   "#318ce7",
+	"caribbeangreen": "#00cc99",
+	"burgundy":       "#800021",
+	"byzantine":      "#bd33a4",
+	"cream":          "#fffdd1",
+	"darktangerine":  "#ffa812",
+}
+
+var svgColorNames = func() map[string]struct{} {
+	result := make(map[string]struct{})
+	for _, name := range strings.Fields(`aliceblue antiquewhite aqua aquamarine azure beige bisque black blanchedalmond
+		blue blueviolet brown burlywood cadetblue chartreuse chocolate coral cornflowerblue cornsilk crimson cyan darkblue
+		darkcyan darkgoldenrod darkgray darkgreen darkgrey darkkhaki darkmagenta darkolivegreen darkorange darkorchid darkred
+		darksalmon darkseagreen darkslateblue darkslategray darkslategrey darkturquoise darkviolet deeppink deepskyblue
+		dimgray dimgrey dodgerblue firebrick floralwhite forestgreen fuchsia gainsboro ghostwhite gold goldenrod gray grey
+		green greenyellow honeydew hotpink indianred indigo ivory khaki lavender lavenderblush lawngreen lemonchiffon
+		lightblue lightcoral lightcyan lightgoldenrodyellow lightgray lightgreen lightgrey lightpink lightsalmon
+		lightseagreen lightskyblue lightslategray lightslategrey lightsteelblue lightyellow lime limegreen linen magenta
+		maroon mediumaquamarine mediumblue mediumorchid mediumpurple mediumseagreen mediumslateblue mediumspringgreen
+		mediumturquoise mediumvioletred midnightblue mintcream mistyrose moccasin navajowhite navy oldlace olive olivedrab
+		orange orangered orchid palegoldenrod palegreen paleturquoise palevioletred papayawhip peachpuff peru pink plum
+		powderblue purple red rosybrown royalblue saddlebrown salmon sandybrown seagreen seashell sienna silver skyblue
+		slateblue slategray slategrey snow springgreen steelblue tan teal thistle tomato turquoise violet wheat white
+		whitesmoke yellow yellowgreen`) {
+		result[name] = struct{}{}
+	}
+	return result
+}()
@@ -0,0 +1,187 @@
+package generations
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var svgBoxRegexp = regexp.MustCompile(`<g id="([^"]+)">(?:<title>[^<]*</title>)?<rect x="([\d.]+)" y="([\d.]+)" [^>]* fill="([^"]+)" fill-opacity="([\d.]+)"`)
+
+type svgTestBox struct {
+	x, y    float64
+	fill    string
+	opacity string
+}
+
+// svgTestBoxes returns the boxes of an SVG by id
+func svgTestBoxes(svg string) map[string]svgTestBox {
+	result := make(map[string]svgTestBox)
+	for _, match := range svgBoxRegexp.FindAllStringSubmatch(svg, -1) {
+		x, _ := strconv.ParseFloat(match[2], 64)
+		y, _ := strconv.ParseFloat(match[3], 64)
+		result[match[1]] = svgTestBox{x: x, y: y, fill: match[4], opacity: match[5]}
+	}
+	return result
+}
+
+func TestTreeModelSVG(t *testing.T) {
+	levels := []AbsoluteLevel{
+		{Index: 2, Color: LevelColor{Main: "DodgerBlue!20", Leaf: "cream"}},
+		{Index: 1, Color: LevelColor{Main: "amaranth!50", Leaf: "Gray!10"}},
+		{Index: 0, Color: LevelColor{Main: "gold"}},
+	}
+
+	// parent graph, the proband is level 1
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "parents-siblings.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+	m, err := BuildTree(person, RenderTreeOptions{
+		GraphType:   GraphTypeParent,
+		GenderOrder: GenderOrderMaleFirst,
+		Levels:      levels,
+	})
+	assert.Nil(t, err)
+	svg, err := m.SVG(1)
+	assert.Nil(t, err)
+	boxes := svgTestBoxes(string(svg))
+	assert.Len(t, boxes, 4)
+	assert.Equal(t, boxes["gauss"].y, boxes["schwester"].y)
+	assert.NotEqual(t, boxes["gauss"].x, boxes["schwester"].x)
+	assert.Equal(t, boxes["papa"].y, boxes["mama"].y)
+	assert.True(t, boxes["papa"].x < boxes["mama"].x)
+	assert.True(t, boxes["papa"].y < boxes["gauss"].y)
+	assert.Equal(t, svgTestBox{x: boxes["gauss"].x, y: boxes["gauss"].y, fill: "#e52b50", opacity: "0.5"}, boxes["gauss"])
+	assert.Equal(t, "#e52b50", boxes["schwester"].fill, "siblings in the proband level get the main color")
+	assert.Equal(t, "dodgerblue", boxes["papa"].fill)
+	assert.Equal(t, "0.2", boxes["papa"].opacity)
+
+	// child graph, the proband is level 2
+	database = NewMemoryDatabase()
+	err = database.ParseYamlFile(filepath.Join("testdata", "database", "register.yml"))
+	assert.Nil(t, err)
+	person, err = database.GetByID("gauss")
+	assert.Nil(t, err)
+	m, err = BuildTree(person, RenderTreeOptions{
+		GraphType: GraphTypeChild,
+		Levels:    levels,
+	})
+	assert.Nil(t, err)
+	svg, err = m.SVG(2)
+	assert.Nil(t, err)
+	boxes = svgTestBoxes(string(svg))
+	assert.Len(t, boxes, 9)
+	assert.True(t, boxes["gauss"].x < boxes["erste-frau"].x)
+	assert.True(t, boxes["erste-frau"].x < boxes["zweite-frau"].x)
+	assert.True(t, boxes["tochter"].x < boxes["sohn"].x)
+	assert.True(t, boxes["sohn"].x < boxes["nachzuegler"].x)
+	assert.True(t, boxes["tochter"].x < boxes["schwiegersohn"].x)
+	assert.True(t, boxes["gauss"].y < boxes["tochter"].y)
+	assert.True(t, boxes["enkel"].y < boxes["urenkel"].y)
+	assert.Equal(t, "dodgerblue", boxes["gauss"].fill)
+	assert.Equal(t, "dodgerblue", boxes["erste-frau"].fill, "partners in the proband level get the main color")
+	assert.Equal(t, "#e52b50", boxes["tochter"].fill)
+	assert.Equal(t, "gray", boxes["schwiegersohn"].fill, "partners in child levels get the leaf color")
+	assert.Equal(t, "0.1", boxes["schwiegersohn"].opacity)
+	assert.Equal(t, "gold", boxes["enkel"].fill)
+	assert.Equal(t, "white", boxes["urenkel"].fill, "levels without color are white")
+	assert.Contains(t, string(svg), `stroke="indianred"`)
+	assert.NotContains(t, string(svg), `stroke-dasharray`)
+
+	// edges to adoptive parents are dashed
+	database = NewMemoryDatabase()
+	err = database.ParseYamlFile(filepath.Join("testdata", "database", "adoption.yml"))
+	assert.Nil(t, err)
+	person, err = database.GetByID("gauss")
+	assert.Nil(t, err)
+	m, err = BuildTree(person, RenderTreeOptions{
+		GraphType:       GraphTypeParent,
+		ParentLinkTypes: []ParentLinkType{ParentLinkTypeAdoptive},
+	})
+	assert.Nil(t, err)
+	svg, err = m.SVG(0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, strings.Count(string(svg), `stroke-dasharray="6,3"`), "stubs of the child and both parents")
+}
+
+func TestSVGLines(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "single-full-details.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		Name     string
+		Person   *FlatPerson
+		Options  RenderPersonOptions
+		Expected []string
+	}{
+		{
+			Name:     "full name",
+			Expected: []string{"Carl Philip Emanuel", "Gauss"},
+		},
+		{
+			Name:     "middle names hidden",
+			Options:  RenderPersonOptions{HideMiddleNames: true},
+			Expected: []string{"Carl", "Gauss"},
+		},
+		{
+			Name:     "title and nick only with a name format",
+			Person:   &FlatPerson{Name: Name{Title: "Dr.", First: []string{"Johann"}, Used: "Hans", Nick: "Hansi", Last: "Meier"}},
+			Options:  RenderPersonOptions{HideMiddleNames: true},
+			Expected: []string{"Hans", "Meier"},
+		},
+		{
+			Name:     "inverse name format",
+			Options:  RenderPersonOptions{NameFormat: NameFormatInverse},
+			Expected: []string{"Gauss", "Carl Philip Emanuel"},
+		},
+		{
+			Name:     "everything hidden",
+			Options:  RenderPersonOptions{HideName: true},
+			Expected: []string{"?"},
+		},
+	}
+
+	for _, test := range tests {
+		var p Person = person
+		if test.Person != nil {
+			p = test.Person
+		}
+		assert.Equal(t, test.Expected, svgLines(PersonNode{Person: p, Options: test.Options}), test.Name)
+	}
+}
+
+func TestSVGColor(t *testing.T) {
+	tests := []struct {
+		Color   string
+		Fill    string
+		Opacity float64
+		OK      bool
+	}{
+		{Color: "DodgerBlue!20", Fill: "dodgerblue", Opacity: 0.2, OK: true},
+		{Color: "gold", Fill: "gold", Opacity: 1, OK: true},
+		{Color: "bleudefrance!150", Fill: "#318ce7", Opacity: 1, OK: true},
+		{Color: "red!50!blue", OK: false},
+		{Color: "red!x", OK: false},
+		{Color: "unknowncolor", OK: false},
+		{Color: "", OK: false},
+	}
+
+	for _, test := range tests {
+		fill, opacity, ok := svgColor(test.Color)
+		assert.Equal(t, test.OK, ok, test.Color)
+		if !test.OK {
+			continue
+		}
+		assert.Equal(t, test.Fill, fill, test.Color)
+		assert.Equal(t, test.Opacity, opacity, test.Color)
+	}
+}
@@ -0,0 +1,205 @@
+package generations
+
+import (
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// templateFuncs returns the functions available in templates
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// text
+		"noEmptyLines":           withoutEmptyLines,
+		"noEmptyLinesString":     withoutEmptyLinesString,
+		"toString":               toString,
+		"join":                   strings.Join,
+		"getFilteredStringSlice": getFilteredStringSlice,
+		"latexify":               latexify,
+		"default":                defaultValue,
+		// LaTeX
+		latexEscapeFunc: escapeLaTeXValue,
+		"escapeLatex":   escapeLaTeXValue,
+		latexRawFunc:    rawLaTeX,
+		// persons
+		"formatDate":   formatDate,
+		"age":          templateAge,
+		"formatName":   formatName,
+		"getSourceIDs": GetSourceIDs,
+		"mom":          templateMom,
+		"dad":          templateDad,
+		"parents":      templateParents,
+		"children":     templateChildren,
+		"partners":     templatePartners,
+	}
+}
+
+// defaultValue returns value unless it is empty (see isEmptyValue), def otherwise. It is meant to be piped into:
+// {{ .Place | default "unbekannt" }}
+func defaultValue(def, value interface{}) interface{} {
+	if isEmptyValue(value) {
+		return def
+	}
+	return value
+}
+
+// isEmptyValue returns true for nil, zero values, empty strings, slices and maps, dummy persons and values with an
+// Empty method returning true (e.g. Date or AgeRange)
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case interface{ Empty() bool }:
+		return v.Empty()
+	case interface{ IsDummy() bool }:
+		return v.IsDummy()
+	}
+	r := reflect.ValueOf(value)
+	switch r.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return r.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return r.IsNil()
+	}
+	return r.IsZero()
+}
+
+// templateLocale accepts a Locale, a Language or its name
+func templateLocale(locale interface{}) (Locale, error) {
+	switch l := locale.(type) {
+	case Locale:
+		return l, nil
+	case Language:
+		return Locale{Language: l}, nil
+	case string:
+		language, err := ParseLanguage(l)
+		if err != nil {
+			return Locale{}, err
+		}
+		return Locale{Language: language}, nil
+	}
+	return Locale{}, errors.Errorf("invalid locale %v", locale)
+}
+
+// formatDate formats a Date, DatePlace or time.Time for readers in the given locale or language:
+// {{ formatDate "de" .Date }} or {{ formatDate $.Options.Locale .Date }}
+func formatDate(locale, value interface{}) (string, error) {
+	l, err := templateLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case Date:
+		return v.Format(l), nil
+	case DatePlace:
+		return v.Date.Format(l), nil
+	case time.Time:
+		if v.IsZero() {
+			return "", nil
+		}
+		return PartialDate{Year: v.Year(), Month: int(v.Month()), Day: v.Day()}.Format(l), nil
+	}
+	return "", errors.Errorf("can't format %T as date", value)
+}
+
+// templateAge returns the age of somebody born at birth at a Date, DatePlace or time.Time:
+// {{ age .Person.GetBirth.Date .Options.Date }}
+func templateAge(birth Date, at interface{}) (AgeRange, error) {
+	switch v := at.(type) {
+	case Date:
+		return AgeBetween(birth, v), nil
+	case DatePlace:
+		return AgeBetween(birth, v.Date), nil
+	case time.Time:
+		return AgeAt(birth, v), nil
+	}
+	return NoAge, errors.Errorf("can't compute an age at %T", at)
+}
+
+// formatName formats the name of a Name or Person with a name format or the name of one (e.g. "full", "inverse" or
+// "no-middle"), optionally in a locale: {{ formatName "full" .Person }} or {{ formatName "full" .Person "en" }}
+func formatName(format, value interface{}, locale ...interface{}) (string, error) {
+	var name Name
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case Name:
+		name = v
+	case Person:
+		name = v.GetName()
+	default:
+		return "", errors.Errorf("can't format %T as name", value)
+	}
+	var f NameFormat
+	switch v := format.(type) {
+	case NameFormat:
+		f = v
+	case string:
+		var err error
+		f, err = GetNameFormat(v)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", errors.Errorf("invalid name format %v", format)
+	}
+	var l Locale
+	if len(locale) > 0 {
+		var err error
+		l, err = templateLocale(locale[0])
+		if err != nil {
+			return "", err
+		}
+	}
+	return name.Format(f, l), nil
+}
+
+// templateMom returns the biological mother of p, nil if she is unknown: {{ with mom .Person }}...{{ end }}
+func templateMom(p Person) (Person, error) {
+	return knownPerson(p.GetMom())
+}
+
+// templateDad returns the biological father of p, nil if he is unknown
+func templateDad(p Person) (Person, error) {
+	return knownPerson(p.GetDad())
+}
+
+func knownPerson(p Person, err error) (Person, error) {
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.IsDummy() {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// templateParents returns the biological parents of p
+func templateParents(p Person) ([]Person, error) {
+	parents, err := p.GetParentsByType(ParentLinkTypeBiological)
+	if err != nil {
+		return nil, err
+	}
+	return parents.GetPersons(), nil
+}
+
+// templateChildren returns the biological children of p
+func templateChildren(p Person) ([]Person, error) {
+	children, err := p.GetChildren()
+	if err != nil {
+		return nil, err
+	}
+	return children.GetPersons(), nil
+}
+
+// templatePartners returns the partners of p
+func templatePartners(p Person) ([]Person, error) {
+	partners, err := p.GetPartners()
+	if err != nil {
+		return nil, err
+	}
+	return partners.GetPersons(), nil
+}
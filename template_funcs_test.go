@@ -0,0 +1,95 @@
+package generations
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	gauss := &FlatPerson{
+		Name:    Name{First: []string{"Johann", "Carl", "Friedrich"}, Last: "Gauss", Birth: "Hauser"},
+		Birth:   DatePlace{Date: "1827", Place: "Hannover"},
+		Baptism: DatePlace{Date: "10.10.1827", Place: "Hannover"},
+		Death:   DatePlace{Date: "um 1900"},
+	}
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "parents.yml"))
+	assert.Nil(t, err)
+	child, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		Template string
+		Data     interface{}
+		Expected string
+	}{
+		{Template: `{{ .Person.GetBirth.Place | default "unbekannt" }}`, Data: gauss, Expected: "Hannover"},
+		{Template: `{{ .Person.GetFloruit | default "unbekannt" }}`, Data: child, Expected: "unbekannt"},
+		{Template: `{{ .Person.GetBirth.Date | default "?" }}`, Data: child, Expected: "?"},
+		{Template: `{{ 0 | default 7 }}`, Data: child, Expected: "7"},
+		{Template: `{{ formatDate "de" .Person.GetBaptism }}`, Data: gauss, Expected: "10.10.1827"},
+		{Template: `{{ formatDate "en" .Person.GetBaptism.Date }}`, Data: gauss, Expected: "10 October 1827"},
+		{Template: `{{ age .Person.GetBirth.Date .Person.GetDeath }}`, Data: gauss, Expected: `\textasciitilde{}72–73`},
+		{Template: `{{ formatName "full" .Person }}`, Data: gauss, Expected: "Johann Carl Friedrich Gauss, geb. Hauser"},
+		{Template: `{{ formatName "no-middle" .Person.GetName }}`, Data: gauss, Expected: "Johann Gauss, geb. Hauser"},
+		{Template: `{{ formatName "inverse" .Person "en" }}`, Data: gauss, Expected: "Gauss, Johann Carl Friedrich, née Hauser"},
+		{Template: `{{ escapeLatex "50%" }}`, Data: gauss, Expected: `50\%`},
+		{Template: `{{ with mom .Person }}{{ .GetID }}{{ end }}/{{ with dad .Person }}{{ .GetID }}{{ end }}`, Data: child, Expected: "mama/papa"},
+		{Template: `{{ with mom .Person }}{{ .GetID }}{{ else }}none{{ end }}`, Data: gauss, Expected: "none"},
+		{Template: `{{ range parents .Person }}{{ .GetID }},{{ end }}`, Data: child, Expected: "mama,papa,"},
+		{Template: `{{ range children .Person }}{{ .GetID }}{{ end }}`, Data: child, Expected: ""},
+	}
+
+	for _, test := range tests {
+		r := NewTemplateRegistry(TemplatePath{fstest.MapFS{"test.tpl": {Data: []byte(test.Template)}}})
+		result, err := r.Render("test.tpl", struct{ Person Person }{Person: test.Data.(Person)})
+		assert.Nil(t, err, test.Template)
+		assert.Equal(t, test.Expected, string(result), test.Template)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	tests := []struct {
+		Input   interface{}
+		English string
+		German  string
+	}{
+		{Input: Date(""), English: "", German: ""},
+		{Input: Date("1850"), English: "1850", German: "1850"},
+		{Input: Date("1850-03"), English: "March 1850", German: "03.1850"},
+		{Input: Date("1850-03-12"), English: "12 March 1850", German: "12.03.1850"},
+		{Input: Date("about 1900"), English: "about 1900", German: "ca. 1900"},
+		{Input: Date("est 1900"), English: "est. 1900", German: "geschätzt 1900"},
+		{Input: Date("before 1932"), English: "before 1932", German: "vor 1932"},
+		{Input: Date("after 1932-05"), English: "after May 1932", German: "nach 05.1932"},
+		{Input: Date("between 1850 and 1855"), English: "between 1850 and 1855", German: "zwischen 1850 und 1855"},
+		{Input: Date("?"), English: "unknown", German: "unbekannt"},
+		{Input: Date("next summer"), English: "next summer", German: "next summer"},
+		{Input: DatePlace{Date: "1850", Place: "Berlin"}, English: "1850", German: "1850"},
+		{Input: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), English: "2 January 2019", German: "02.01.2019"},
+		{Input: time.Time{}, English: "", German: ""},
+	}
+
+	for _, test := range tests {
+		result, err := formatDate("en", test.Input)
+		assert.Nil(t, err)
+		assert.Equal(t, test.English, result)
+		result, err = formatDate(LanguageDe, test.Input)
+		assert.Nil(t, err)
+		assert.Equal(t, test.German, result)
+	}
+
+	iso := NewLocale(LanguageEn, map[string]string{"date.day": "{year}-{month2}-{day2}", "date.about": "~{0}"})
+	result, err := formatDate(iso, Date("about 1850-03-12"))
+	assert.Nil(t, err)
+	assert.Equal(t, "~1850-03-12", result)
+
+	_, err = formatDate("xx", Date("1850"))
+	assert.NotNil(t, err)
+	_, err = formatDate("en", 1850)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,83 @@
+package generations
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// TemplatePath is a search path for template files. Relative names are looked up in its file systems in order, the
+// first one containing a file wins. Absolute names are read from disk.
+type TemplatePath []fs.FS
+
+// TemplateDir is a directory on disk as a file system, unlike os.DirFS names may leave the directory (e.g. "../x")
+type TemplateDir string
+
+// Open opens the file name relative to the directory
+func (d TemplateDir) Open(name string) (fs.File, error) {
+	return os.Open(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+// Open opens the first file with the given name in the search path
+func (p TemplatePath) Open(name string) (fs.File, error) {
+	if filepath.IsAbs(name) {
+		return os.Open(name)
+	}
+	name = path.Clean(filepath.ToSlash(name))
+	for _, fsys := range p {
+		f, err := fsys.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !isMissingFile(err) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadFile returns the content of the first file with the given name in the search path
+func (p TemplatePath) ReadFile(name string) ([]byte, error) {
+	f, err := p.Open(name)
+	if isMissingFile(err) {
+		return nil, errors.NotFoundf("template %s in search path", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+// Glob returns the names of the files matching pattern in any file system of the search path
+func (p TemplatePath) Glob(pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	var result []string
+	for _, fsys := range p {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				result = append(result, match)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// isMissingFile returns true if err means that a file does not exist or can't exist in a file system
+func isMissingFile(err error) bool {
+	if pathErr, ok := err.(*fs.PathError); ok {
+		err = pathErr.Err
+	}
+	return os.IsNotExist(err) || err == fs.ErrInvalid
+}
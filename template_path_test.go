@@ -0,0 +1,70 @@
+package generations
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplatePath(t *testing.T) {
+	user := fstest.MapFS{
+		"templates/person.tpl":  {Data: []byte("user person")},
+		"templates/site/a.html": {Data: []byte("user a")},
+	}
+	defaults := fstest.MapFS{
+		"templates/person.tpl":  {Data: []byte("default person")},
+		"templates/tree.tpl":    {Data: []byte("default tree")},
+		"templates/site/a.html": {Data: []byte("default a")},
+		"templates/site/b.html": {Data: []byte("default b")},
+	}
+	p := TemplatePath{user, defaults}
+
+	tests := []struct {
+		Name     string
+		Expected string
+	}{
+		{Name: "templates/person.tpl", Expected: "user person"},
+		{Name: "templates/tree.tpl", Expected: "default tree"},
+		{Name: "./templates/site/../tree.tpl", Expected: "default tree"},
+		{Name: filepath.Join("templates", "site", "b.html"), Expected: "default b"},
+	}
+	for _, test := range tests {
+		content, err := p.ReadFile(test.Name)
+		assert.Nil(t, err, test.Name)
+		assert.Equal(t, test.Expected, string(content), test.Name)
+	}
+
+	_, err := p.ReadFile("templates/missing.tpl")
+	assert.True(t, errors.IsNotFound(err))
+	_, err = p.ReadFile("../outside.tpl")
+	assert.True(t, errors.IsNotFound(err), "names outside of file systems are missing")
+
+	matches, err := p.Glob("templates/site/*.html")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"templates/site/a.html", "templates/site/b.html"}, matches)
+
+	// directories on disk and absolute names
+	dir, err := ioutil.TempDir("", "generations-test-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	err = os.MkdirAll(filepath.Join(dir, "templates"), 0750)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "templates", "tree.tpl"), []byte("disk tree"), 0644)
+	assert.Nil(t, err)
+
+	p = TemplatePath{TemplateDir(dir), defaults}
+	content, err := p.ReadFile("templates/tree.tpl")
+	assert.Nil(t, err)
+	assert.Equal(t, "disk tree", string(content))
+	content, err = p.ReadFile("templates/person.tpl")
+	assert.Nil(t, err)
+	assert.Equal(t, "default person", string(content))
+	content, err = TemplatePath{defaults}.ReadFile(filepath.Join(dir, "templates", "tree.tpl"))
+	assert.Nil(t, err)
+	assert.Equal(t, "disk tree", string(content))
+}
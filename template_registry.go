@@ -0,0 +1,83 @@
+package generations
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/juju/errors"
+)
+
+// TemplateRegistry reads and parses each template once, printed values are escaped for LaTeX (see LaTeX)
+type TemplateRegistry struct {
+	// Path is the search path the templates are read from
+	Path TemplatePath
+
+	mutex     sync.Mutex
+	templates map[string]*template.Template
+}
+
+// Templates is the registry used by RenderTemplateFile, it reads templates relative to the working directory
+var Templates = NewTemplateRegistry(TemplatePath{TemplateDir(".")})
+
+// NewTemplateRegistry returns an empty registry reading templates from the given search path
+func NewTemplateRegistry(path TemplatePath) *TemplateRegistry {
+	return &TemplateRegistry{
+		Path:      path,
+		templates: make(map[string]*template.Template),
+	}
+}
+
+// Load parses the templates with the given names that are not loaded yet, empty names are skipped. The errors of all
+// templates are returned together, parse errors start with file and line (e.g. "template: templates/person.tpl:12:").
+func (r *TemplateRegistry) Load(names ...string) error {
+	var messages []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		_, err := r.Get(name)
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// Get returns the parsed template with the given name, it is loaded on first use
+func (r *TemplateRegistry) Get(name string) (*template.Template, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if t, ok := r.templates[name]; ok {
+		return t, nil
+	}
+	content, err := r.Path.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(name).Funcs(templateFuncs()).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	escapeTemplate(t)
+	r.templates[name] = t
+	return t, nil
+}
+
+// Render executes the template with the given name
+func (r *TemplateRegistry) Render(name string, data interface{}) ([]byte, error) {
+	t, err := r.Get(name)
+	if err != nil {
+		return []byte{}, err
+	}
+	var result bytes.Buffer
+	err = t.Execute(&result, data)
+	if err != nil {
+		return []byte{}, err
+	}
+	return result.Bytes(), nil
+}
@@ -0,0 +1,41 @@
+package generations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRegistry(t *testing.T) {
+	files := fstest.MapFS{
+		"person.tpl": {Data: []byte(`{{ .Name }} & Co.`)},
+		"broken.tpl": {Data: []byte("line 1\nline 2 {{ if }}\n")},
+		"other.tpl":  {Data: []byte("{{ range }}")},
+	}
+	r := NewTemplateRegistry(TemplatePath{files})
+
+	err := r.Load("person.tpl", "")
+	assert.Nil(t, err)
+	first, err := r.Get("person.tpl")
+	assert.Nil(t, err)
+
+	// templates are read once
+	files["person.tpl"] = &fstest.MapFile{Data: []byte("changed")}
+	second, err := r.Get("person.tpl")
+	assert.Nil(t, err)
+	assert.True(t, first == second)
+	result, err := r.Render("person.tpl", struct{ Name string }{Name: "Smith & Sons"})
+	assert.Nil(t, err)
+	assert.Equal(t, `Smith \& Sons & Co.`, string(result))
+
+	// all errors are reported with file and line
+	err = r.Load("person.tpl", "broken.tpl", "other.tpl", "missing.tpl")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "template: broken.tpl:2:")
+	assert.Contains(t, err.Error(), "template: other.tpl:1:")
+	assert.Contains(t, err.Error(), "missing.tpl")
+
+	_, err = r.Render("broken.tpl", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,348 @@
+package generations
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+)
+
+// TreeModel is a graph of persons built from a database, independent of the output format
+type TreeModel struct {
+	Type GraphType `json:"type"`
+	Root Person    `json:"-"`
+	// Parent is the ancestry of the root for parent and sandclock graphs, headless for the latter
+	Parent *ParentNode `json:"parent,omitempty"`
+	// Child is the descendancy of the root for child and sandclock graphs, of the common ancestor for connection graphs
+	Child *ChildNode `json:"child,omitempty"`
+	// SiblingsOlder and SiblingsYounger are the siblings of the root in sandclock graphs
+	SiblingsOlder   []PersonNode `json:"siblings-older,omitempty"`
+	SiblingsYounger []PersonNode `json:"siblings-younger,omitempty"`
+
+	// Options the model was built with, serializers use its templates
+	Options RenderTreeOptions `json:"-"`
+}
+
+// PersonNode is a person in a graph with the options to render it with, Options.NodeType is its genealogytree node type
+type PersonNode struct {
+	Person  Person
+	Options RenderPersonOptions
+}
+
+// NodeLink connects two nodes of a graph in addition to its edges
+type NodeLink struct {
+	// From and To are the ids of the nodes
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Style is the name of the style to draw the link with, e.g. link-adoptive
+	Style string `json:"style"`
+}
+
+// ID returns the genealogytree id of the node
+func (n PersonNode) ID() string {
+	if n.Options.ImplexID != "" {
+		return n.Options.ImplexID
+	}
+	return n.Person.GetBestID()
+}
+
+// FamilyNode is the common part of parent and child families
+type FamilyNode struct {
+	// ID is the genealogytree id of the family, empty if family IDs are hidden
+	ID string     `json:"id,omitempty"`
+	G  PersonNode `json:"g"`
+	// EdgeStyle is the style of the edges of the family, empty unless all of its links are of one non-biological type
+	EdgeStyle string `json:"edge-style,omitempty"`
+}
+
+// ParentNode is a person with its ancestry, a leaf if there are no parents
+type ParentNode struct {
+	FamilyNode
+	Parents         []*ParentNode `json:"parents,omitempty"`
+	SiblingsOlder   []PersonNode  `json:"siblings-older,omitempty"`
+	SiblingsYounger []PersonNode  `json:"siblings-younger,omitempty"`
+	// Headless families render their parents only, the person is part of another tree
+	Headless bool `json:"headless,omitempty"`
+}
+
+// ChildNode is a person with its descendancy, a leaf if there are no partners or children
+type ChildNode struct {
+	FamilyNode
+	// Partner and Children are the first union of the person, Partner is nil if unknown or hidden
+	Partner  *PersonNode  `json:"partner,omitempty"`
+	Children []*ChildNode `json:"children,omitempty"`
+	// Unions are the other partners and their children
+	Unions []*UnionNode `json:"unions,omitempty"`
+}
+
+// UnionNode is an additional partner of a person and their children
+type UnionNode struct {
+	ID        string `json:"id,omitempty"`
+	EdgeStyle string `json:"edge-style,omitempty"`
+	// Partner is nil if unknown or hidden
+	Partner  *PersonNode  `json:"partner,omitempty"`
+	Children []*ChildNode `json:"children,omitempty"`
+}
+
+// IsLeaf returns true if the node has no parents
+func (n *ParentNode) IsLeaf() bool {
+	return len(n.Parents) == 0
+}
+
+// parentNodes returns the nodes of the parents
+func (n *ParentNode) parentNodes() []PersonNode {
+	result := make([]PersonNode, len(n.Parents))
+	for i, parent := range n.Parents {
+		result[i] = parent.G
+	}
+	return result
+}
+
+// linkParents links each of children to the nodes of parents it is no biological child of
+func linkParents(children []PersonNode, parents []PersonNode) {
+	for i := range children {
+		children[i].Options.Links = parentNodeLinks(children[i], parents...)
+	}
+}
+
+// parentNodeLinks returns the links from the nodes of parents to the node of child for non-biological parents
+func parentNodeLinks(child PersonNode, parents ...PersonNode) []NodeLink {
+	var result []NodeLink
+	for _, parent := range parents {
+		linkType, ok := child.Person.GetParentLinkType(parent.Person)
+		if !ok || linkType.GetStyle() == "" {
+			continue
+		}
+		result = append(result, NodeLink{From: parent.ID(), To: child.ID(), Style: linkType.GetStyle()})
+	}
+	return result
+}
+
+// familyEdgeStyle returns the style shared by the links between all children and parents of a family, empty if the
+// links differ or are biological. genealogytree styles the edges of a family as a whole.
+func familyEdgeStyle(children []PersonNode, parents []PersonNode) string {
+	var (
+		result string
+		first  = true
+	)
+	for _, child := range children {
+		for _, parent := range parents {
+			style := ""
+			if linkType, ok := child.Person.GetParentLinkType(parent.Person); ok {
+				style = linkType.GetStyle()
+			}
+			if first {
+				result, first = style, false
+				continue
+			}
+			if style != result {
+				return ""
+			}
+		}
+	}
+	return result
+}
+
+// IsLeaf returns true if the node has no partners or children
+func (n *ChildNode) IsLeaf() bool {
+	return n.Partner == nil && len(n.Children) == 0 && len(n.Unions) == 0
+}
+
+// BuildTree builds the graph selected by o.GraphType for the given person
+func BuildTree(p Person, o RenderTreeOptions) (*TreeModel, error) {
+	o.SetDefaults()
+	switch {
+	case o.HideImplex:
+		o.Implex = nil
+	case o.Implex == nil:
+		o.Implex = &ImplexStatistics{}
+	default:
+		o.Implex.reset()
+	}
+
+	var (
+		m   = &TreeModel{Type: o.GraphType, Root: p, Options: o}
+		err error
+	)
+	switch o.GraphType {
+	case GraphTypeParent:
+		m.Parent, err = buildFullParentTree(p, o, false)
+		if err != nil {
+			return nil, errors.Annotate(err, "could not build parent tree")
+		}
+	case GraphTypeChild:
+		m.Child, err = buildFullChildTree(p, o)
+		if err != nil {
+			return nil, errors.Annotate(err, "could not build child tree")
+		}
+	case GraphTypeConnection:
+		return nil, errors.New("connection graphs need two persons, use BuildConnectionTree")
+	default:
+		m.Parent, err = buildFullParentTree(p, o, true)
+		if err != nil {
+			return nil, errors.Annotate(err, "could not build parent subtree")
+		}
+		m.Child, err = buildFullChildTree(p, o)
+		if err != nil {
+			return nil, errors.Annotate(err, "could not build child subtree")
+		}
+		m.SiblingsOlder, m.SiblingsYounger, err = buildSandclockSiblings(p, o)
+		if err != nil {
+			return nil, err
+		}
+		// the root and its siblings are the children of the headless parent family
+		parents := m.Parent.parentNodes()
+		m.Child.G.Options.Links = parentNodeLinks(m.Child.G, parents...)
+		linkParents(m.SiblingsOlder, parents)
+		linkParents(m.SiblingsYounger, parents)
+		children := append(append([]PersonNode{m.Child.G}, m.SiblingsOlder...), m.SiblingsYounger...)
+		m.Parent.EdgeStyle = familyEdgeStyle(children, parents)
+	}
+	return m, nil
+}
+
+func buildSandclockSiblings(p Person, o RenderTreeOptions) (older, younger []PersonNode, err error) {
+	if o.MaxParentSiblingsGenerations == GenerationsNone {
+		return nil, nil, nil
+	}
+	mom, err := p.GetMomByType(o.ParentLinkTypes...)
+	if err != nil {
+		return nil, nil, err
+	}
+	dad, err := p.GetDadByType(o.ParentLinkTypes...)
+	if err != nil {
+		return nil, nil, err
+	}
+	siblings := NewPersonList(nil)
+	if !dad.IsDummy() {
+		siblings, err = dad.GetChildrenWithByType(mom, o.ParentLinkTypes...)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if !mom.IsDummy() {
+		siblings, err = mom.GetChildrenWithByType(NewDummyFlatPerson(), o.ParentLinkTypes...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// apply ignore rules
+	siblings = nonIgnored(siblings, o)
+
+	// split older and younger
+	youngerList, olderList := SplitPersons(siblings, p)
+	opts := *o.RenderPersonOptions
+	opts.NodeType = NodeTypeC
+	opts = *opts.HideImageByLevel(o, 0)
+	older = buildPersonNodes(olderList, opts, o)
+	younger = buildPersonNodes(youngerList, opts, o)
+	return older, younger, nil
+}
+
+func buildPersonNodes(personList PersonList, renderPersonOptions RenderPersonOptions, o RenderTreeOptions) []PersonNode {
+	var result []PersonNode
+	for _, person := range personList.GetPersons() {
+		opts, _ := o.Implex.visit(person, renderPersonOptions)
+		result = append(result, PersonNode{Person: person, Options: opts})
+	}
+	return result
+}
+
+// Persons returns the persons in the graph in order of their first node, each person once
+func (m *TreeModel) Persons() []Person {
+	var (
+		result []Person
+		seen   = make(map[string]bool)
+	)
+	for _, n := range m.Nodes() {
+		id := n.Person.GetBestID()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, n.Person)
+	}
+	return result
+}
+
+// Nodes returns the person nodes of the graph
+func (m *TreeModel) Nodes() []PersonNode {
+	var (
+		result    []PersonNode
+		addParent func(n *ParentNode)
+		addChild  func(n *ChildNode)
+	)
+	addParent = func(n *ParentNode) {
+		if !n.Headless {
+			result = append(result, n.SiblingsOlder...)
+			result = append(result, n.G)
+			result = append(result, n.SiblingsYounger...)
+		}
+		for _, parent := range n.Parents {
+			addParent(parent)
+		}
+	}
+	addChild = func(n *ChildNode) {
+		result = append(result, n.G)
+		unions := append([]*UnionNode{{Partner: n.Partner, Children: n.Children}}, n.Unions...)
+		for _, union := range unions {
+			if union.Partner != nil {
+				result = append(result, *union.Partner)
+			}
+			for _, child := range union.Children {
+				addChild(child)
+			}
+		}
+	}
+
+	result = append(result, m.SiblingsOlder...)
+	if m.Child != nil {
+		addChild(m.Child)
+	}
+	result = append(result, m.SiblingsYounger...)
+	if m.Parent != nil {
+		addParent(m.Parent)
+	}
+	return result
+}
+
+// Links returns the links between the nodes of the graph that are drawn in addition to its edges, none if node ids are
+// hidden. Nodes repeating a person (implex) link to the node of the person, links to non-biological parents are
+// shown by the edge styles of the families instead.
+func (m *TreeModel) Links() []NodeLink {
+	var result []NodeLink
+	if m.Options.RenderPersonOptions != nil && m.Options.RenderPersonOptions.HideID {
+		return result
+	}
+	for _, n := range m.Nodes() {
+		if n.Options.ImplexOf != "" {
+			result = append(result, NodeLink{From: n.Options.ImplexID, To: n.Options.ImplexOf, Style: "link-implex"})
+		}
+	}
+	return result
+}
+
+// MarshalJSON implements the json.Marshaler interface, persons are reduced to the data identifying them
+func (n PersonNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID         string     `json:"id,omitempty"`
+		Name       string     `json:"name,omitempty"`
+		Type       string     `json:"type"`
+		Attributes []string   `json:"attributes,omitempty"`
+		ImplexOf   string     `json:"implex-of,omitempty"`
+		Kekule     int        `json:"kekule,omitempty"`
+		Links      []NodeLink `json:"links,omitempty"`
+	}{
+		ID:         n.Person.GetBestID(),
+		Name:       n.Person.GetName().FormatFullIn(n.Options.Locale),
+		Type:       n.Options.NodeType.String(),
+		Attributes: n.Options.GetAttributes(n.Person),
+		ImplexOf:   n.Options.ImplexOf,
+		Kekule:     n.Options.Kekule,
+		Links:      n.Options.Links,
+	})
+}
+
+// JSON serializes the model
+func (m *TreeModel) JSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
@@ -0,0 +1,185 @@
+package generations
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTree(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "relationship.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	m, err := BuildTree(person, RenderTreeOptions{
+		GraphType:            GraphTypeSandclock,
+		MaxParentGenerations: 2,
+		IgnoreIDs:            []string{"stiefsohn"},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, GraphTypeSandclock, m.Type)
+	assert.True(t, m.Parent.Headless)
+	assert.Equal(t, []string{
+		"g vater (p grossvater, p grossmutter)",
+		"p mutter",
+	}, describeParentNodes(m.Parent.Parents))
+	assert.Empty(t, m.Parent.SiblingsOlder)
+	assert.Equal(t, []string{"c bruder"}, describePersonNodes(append(m.SiblingsOlder, m.SiblingsYounger...)))
+
+	assert.Equal(t, NodeTypeG, m.Child.G.Options.NodeType)
+	assert.Equal(t, "p frau", describePersonNode(*m.Child.Partner))
+	assert.Equal(t, []string{"g sohn"}, describeChildNodes(m.Child.Children))
+	assert.Empty(t, m.Child.Unions)
+
+	data, err := m.JSON()
+	assert.Nil(t, err)
+	var decoded struct {
+		Type  string
+		Child struct {
+			G struct {
+				ID         string
+				Type       string
+				Attributes []string
+			}
+		}
+	}
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "sandclock", decoded.Type)
+	assert.Equal(t, "gauss", decoded.Child.G.ID)
+	assert.Equal(t, "g", decoded.Child.G.Type)
+	assert.Equal(t, []string{"rootnode"}, decoded.Child.G.Attributes)
+}
+
+func TestBuildTreeUnions(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "two-wifes.yml"))
+	assert.Nil(t, err)
+	person, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+
+	m, err := BuildTree(person, RenderTreeOptions{
+		GraphType: GraphTypeChild,
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, m.Parent)
+	assert.Equal(t, "p frau1", describePersonNode(*m.Child.Partner))
+	assert.Equal(t, []string{"c sohn1"}, describeChildNodes(m.Child.Children))
+	assert.Len(t, m.Child.Unions, 1)
+	assert.Equal(t, "p frau2", describePersonNode(*m.Child.Unions[0].Partner))
+	assert.Equal(t, []string{"c sohn2"}, describeChildNodes(m.Child.Unions[0].Children))
+	assert.True(t, m.Child.Children[0].IsLeaf())
+}
+
+func describePersonNode(n PersonNode) string {
+	return n.Options.NodeType.String() + " " + n.Person.GetID()
+}
+
+func describePersonNodes(nodes []PersonNode) []string {
+	var result []string
+	for _, n := range nodes {
+		result = append(result, describePersonNode(n))
+	}
+	return result
+}
+
+func describeParentNodes(nodes []*ParentNode) []string {
+	var result []string
+	for _, n := range nodes {
+		description := describePersonNode(n.G)
+		if !n.IsLeaf() {
+			description += " (" + strings.Join(describeParentNodes(n.Parents), ", ") + ")"
+		}
+		result = append(result, description)
+	}
+	return result
+}
+
+func describeChildNodes(nodes []*ChildNode) []string {
+	var result []string
+	for _, n := range nodes {
+		result = append(result, describePersonNode(n.G))
+	}
+	return result
+}
+
+func TestTreeModelLinks(t *testing.T) {
+	database := NewMemoryDatabase()
+	err := database.ParseYamlFile(filepath.Join("testdata", "database", "adoption.yml"))
+	assert.Nil(t, err)
+	gauss, err := database.GetByID("gauss")
+	assert.Nil(t, err)
+	adoptiveMom, err := database.GetByID("adoptiv-mama")
+	assert.Nil(t, err)
+
+	adoptive := []NodeLink{
+		{From: "adoptiv-papa", To: "gauss", Style: "link-adoptive"},
+		{From: "adoptiv-mama", To: "gauss", Style: "link-adoptive"},
+	}
+	tests := []struct {
+		Name              string
+		Person            Person
+		GraphType         GraphType
+		ParentLinkTypes   []ParentLinkType
+		ExpectedLinks     []NodeLink
+		ExpectedEdgeStyle string
+	}{
+		{Name: "biological parents", Person: gauss, GraphType: GraphTypeParent},
+		{Name: "adoptive parents", Person: gauss, GraphType: GraphTypeParent, ParentLinkTypes: []ParentLinkType{ParentLinkTypeAdoptive}, ExpectedLinks: adoptive, ExpectedEdgeStyle: "link-adoptive"},
+		{Name: "sandclock", Person: gauss, GraphType: GraphTypeSandclock, ParentLinkTypes: []ParentLinkType{ParentLinkTypeAdoptive}, ExpectedLinks: adoptive, ExpectedEdgeStyle: "link-adoptive"},
+		{Name: "adoptive children", Person: adoptiveMom, GraphType: GraphTypeChild, ParentLinkTypes: []ParentLinkType{ParentLinkTypeBiological, ParentLinkTypeAdoptive}, ExpectedLinks: adoptive, ExpectedEdgeStyle: "link-adoptive"},
+	}
+	for _, test := range tests {
+		o := RenderTreeOptions{
+			GraphType:       test.GraphType,
+			ParentLinkTypes: test.ParentLinkTypes,
+		}
+		addTestTemplates(&o)
+		m, err := BuildTree(test.Person, o)
+		assert.Nil(t, err, test.Name)
+
+		var links []NodeLink
+		for _, n := range m.Nodes() {
+			links = append(links, n.Options.Links...)
+		}
+		assert.ElementsMatch(t, test.ExpectedLinks, links, test.Name)
+		// the links are shown by the edges of the families, not drawn over the tree
+		assert.Empty(t, m.Links(), test.Name)
+
+		family := m.Parent
+		if test.GraphType == GraphTypeChild {
+			family = &ParentNode{FamilyNode: m.Child.FamilyNode}
+		}
+		assert.Equal(t, test.ExpectedEdgeStyle, family.EdgeStyle, test.Name)
+
+		output, err := m.Genealogytree()
+		assert.Nil(t, err, test.Name)
+		if test.ExpectedEdgeStyle != "" {
+			assert.Contains(t, string(output), "edges={foreground={"+test.ExpectedEdgeStyle+"}}", test.Name)
+		} else {
+			assert.NotContains(t, string(output), "edges=", test.Name)
+		}
+	}
+
+	// the link is a property of the tree, not of the person
+	assert.NotContains(t, gauss.GetAttributes(), "link-adoptive")
+
+	// families with biological and adoptive parents keep their regular edges
+	mixed := NewMemoryDatabase()
+	err = mixed.ParseYamlFile(filepath.Join("testdata", "database", "adoption-mixed.yml"))
+	assert.Nil(t, err)
+	stepChild, err := mixed.GetByID("stiefkind")
+	assert.Nil(t, err)
+	m, err := BuildTree(stepChild, RenderTreeOptions{
+		GraphType:       GraphTypeParent,
+		ParentLinkTypes: []ParentLinkType{ParentLinkTypeBiological, ParentLinkTypeAdoptive},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "", m.Parent.EdgeStyle)
+	assert.Equal(t, []NodeLink{{From: "stiefpapa", To: "stiefkind", Style: "link-adoptive"}}, m.Parent.G.Options.Links)
+}
@@ -0,0 +1,307 @@
+package generations
+
+import (
+	"fmt"
+	"strings"
+)
+
+//go:generate go-enum -f=validate.go --marshal
+
+// IssueSeverity tells if an issue is certainly an error or only implausible
+/* ENUM(
+error = 1
+warning
+*/
+type IssueSeverity int
+
+// genealogytreeSyntaxChars can't be part of ids, attributes and image filenames, they are written to the trees as given
+const genealogytreeSyntaxChars = "{}%#\\,]"
+
+// minParentAge is the minimum age of a parent at the birth of a child to be considered plausible
+const minParentAge = 12
+
+// Issue is a problem found in a database by Validate
+type Issue struct {
+	Severity IssueSeverity `json:"severity"`
+	Position Position      `json:"position"`
+	PersonID string        `json:"person,omitempty"`
+	Message  string        `json:"message"`
+	// Breaking is set for issues that break rendering trees like references to missing persons
+	Breaking bool `json:"breaking,omitempty"`
+}
+
+func (i Issue) String() string {
+	result := i.Severity.String() + ": " + i.Message
+	if i.PersonID != "" {
+		result += " (" + i.PersonID + ")"
+	}
+	if position := i.Position.String(); position != "" {
+		result = position + ": " + result
+	}
+	return result
+}
+
+// Validate checks the references between persons and the plausibility of their data
+func Validate(d Database) []Issue {
+	v := validator{
+		database: d,
+		issues:   make([]Issue, 0),
+	}
+	persons := d.GetPersons()
+	v.checkDuplicates(persons)
+	v.checkMerged()
+	for _, p := range persons {
+		v.checkPerson(p)
+	}
+	v.checkCycles(persons)
+	return v.issues
+}
+
+// HasErrors returns true iff any of the issues is an error
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == IssueSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBreakingIssues returns true iff any of the issues breaks rendering trees
+func HasBreakingIssues(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+type validator struct {
+	database Database
+	issues   []Issue
+}
+
+func (v *validator) add(severity IssueSeverity, p Person, field string, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{
+		Severity: severity,
+		Position: p.GetPosition(field),
+		PersonID: p.GetBestID(),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// addBreaking adds an error that breaks rendering trees
+func (v *validator) addBreaking(p Person, field string, format string, args ...interface{}) {
+	v.add(IssueSeverityError, p, field, format, args...)
+	v.issues[len(v.issues)-1].Breaking = true
+}
+
+func (v *validator) checkDuplicates(persons []Person) {
+	seen := make(map[string]Person, 2*len(persons))
+	for _, p := range persons {
+		for _, field := range []string{"id", "uuid"} {
+			value := p.GetID()
+			if field == "uuid" {
+				value = p.GetUUID()
+			}
+			if value == "" || (field == "uuid" && value == p.GetID()) {
+				continue
+			}
+			if other, ok := seen[value]; ok {
+				v.add(IssueSeverityError, p, field, "duplicate %s %s, already used at %s", field, value, other.GetPosition(""))
+				continue
+			}
+			seen[value] = p
+		}
+	}
+}
+
+// checkMerged reports persons that were given in several database files and have been merged into one
+func (v *validator) checkMerged() {
+	m, ok := v.database.(*MemoryDatabase)
+	if !ok {
+		return
+	}
+	for _, p := range m.merged {
+		existing, err := v.database.GetByID(p.GetBestID())
+		if err != nil {
+			continue
+		}
+		v.add(IssueSeverityWarning, p, "id", "duplicate id %s, merged with %s", p.GetBestID(), existing.GetPosition(""))
+	}
+}
+
+func (v *validator) checkPerson(p Person) {
+	v.checkSyntax(p, "id", "id", p.GetID())
+	v.checkSyntax(p, "uuid", "uuid", p.GetUUID())
+	v.checkSyntax(p, "image", "image filename", p.GetImageFilename())
+	if flat, ok := p.(*FlatPerson); ok {
+		for i, a := range flat.Attributes {
+			v.checkSyntax(p, fmt.Sprintf("attributes[%d]", i), "attribute", a)
+		}
+	}
+	for _, sourceID := range GetSourceIDs(p.GetAllReferences()) {
+		v.checkSyntax(p, "sources", "source id", sourceID)
+	}
+
+	if flat, ok := p.(*FlatPerson); ok && flat.Gender != "" {
+		if _, err := ParseGender(flat.Gender); err != nil {
+			v.add(IssueSeverityError, p, "gender", "invalid gender %q", flat.Gender)
+		}
+	}
+
+	birth, birthOK := parseValidDate(p.GetBirth().Date)
+	death, deathOK := parseValidDate(p.GetDeath().Date)
+	if birthOK && deathOK && isCertainlyBefore(death, birth) {
+		v.add(IssueSeverityError, p, "death", "death (%s) before birth (%s)", p.GetDeath().Date, p.GetBirth().Date)
+	}
+
+	for i, link := range p.GetParentLinks() {
+		v.checkParent(p, link, parentLinkField(p, i, link), birth, birthOK)
+	}
+
+	partners := make(map[string]struct{})
+	for i, r := range getRelationships(p) {
+		// the partner may be unknown
+		if r.PartnerID == "" {
+			continue
+		}
+		field := fmt.Sprintf("partners[%d]", i)
+		partner, err := v.database.GetByID(r.PartnerID)
+		if err != nil {
+			v.addBreaking(p, field, "partner %s not found", r.PartnerID)
+			continue
+		}
+		if _, ok := partners[partner.GetBestID()]; ok {
+			v.add(IssueSeverityWarning, p, field, "partner %s given twice", r.PartnerID)
+		}
+		partners[partner.GetBestID()] = struct{}{}
+
+		// partners are usually given on one side only, but if both sides list partners they should match
+		other := getRelationships(partner)
+		if len(other) == 0 {
+			continue
+		}
+		reciprocal := false
+		for _, o := range other {
+			if p.MatchesIDUUID(o.PartnerID) {
+				reciprocal = true
+				break
+			}
+		}
+		if !reciprocal {
+			v.add(IssueSeverityWarning, p, field, "partner %s does not list %s as partner", r.PartnerID, p.GetBestID())
+		}
+	}
+}
+
+// checkSyntax reports values that would break the syntax of the trees
+func (v *validator) checkSyntax(p Person, field, name, value string) {
+	if strings.ContainsAny(value, genealogytreeSyntaxChars) {
+		v.addBreaking(p, field, "invalid %s %q, it must not contain any of %s", name, value, genealogytreeSyntaxChars)
+	}
+}
+
+func (v *validator) checkParent(p Person, link ParentLink, field string, birth FuzzyDate, birthOK bool) {
+	parent, err := v.database.GetByID(link.ID)
+	if err != nil {
+		v.addBreaking(p, field, "parent %s not found", link.ID)
+		return
+	}
+	if parent.MatchesIDUUID(p.GetID(), p.GetUUID()) {
+		v.addBreaking(p, field, "person is its own parent")
+		return
+	}
+
+	role := link.Role
+	gender := parent.GetGender()
+	switch {
+	case role == ParentRoleMom && gender == GenderMale:
+		v.add(IssueSeverityWarning, p, field, "mother %s is male", link.ID)
+	case role == ParentRoleDad && gender == GenderFemale:
+		v.add(IssueSeverityWarning, p, field, "father %s is female", link.ID)
+	case role == 0 && gender == GenderMale:
+		role = ParentRoleDad
+	case role == 0 && gender == GenderFemale:
+		role = ParentRoleMom
+	}
+
+	if link.GetType() != ParentLinkTypeBiological || !birthOK {
+		return
+	}
+	if parentDeath, ok := parseValidDate(parent.GetDeath().Date); ok {
+		latestDeath, bounded := parentDeath.Latest()
+		// a child can be born some months after the death of its father
+		if role == ParentRoleDad {
+			latestDeath = latestDeath.AddDate(0, 10, 0)
+		}
+		if earliestBirth, ok := birth.Earliest(); ok && bounded && earliestBirth.After(latestDeath) {
+			v.add(IssueSeverityWarning, p, field, "born (%s) after death of parent %s (%s)", p.GetBirth().Date, link.ID, parent.GetDeath().Date)
+		}
+	}
+	if parentBirth, ok := parseValidDate(parent.GetBirth().Date); ok {
+		age := ageBetween(parentBirth, birth)
+		if !age.Empty() && age.Max < minParentAge {
+			v.add(IssueSeverityWarning, p, field, "parent %s only %s years old at birth", link.ID, age)
+		}
+		if earliestParentBirth, ok := parentBirth.Earliest(); ok {
+			if latestBirth, ok := birth.Latest(); ok && latestBirth.Before(earliestParentBirth) {
+				v.add(IssueSeverityWarning, p, field, "born (%s) before parent %s (%s)", p.GetBirth().Date, link.ID, parent.GetBirth().Date)
+			}
+		}
+	}
+}
+
+// checkCycles reports persons that are their own ancestors
+func (v *validator) checkCycles(persons []Person) {
+	for _, cycle := range findAncestryCycles(v.database, persons) {
+		p := cycle.persons[len(cycle.persons)-2]
+		link := p.GetParentLinks()[cycle.linkIndex]
+		v.addBreaking(p, parentLinkField(p, cycle.linkIndex, link), "cycle in ancestry: %s", formatCycle(cycle.persons))
+	}
+}
+
+// parentLinkField returns the database field of the parent link with the given index as returned by GetParentLinks
+func parentLinkField(p Person, index int, link ParentLink) string {
+	// parent links start with mom and dad as given in the database
+	rawParents := 0
+	if p.GetRawMom() != "" {
+		rawParents++
+	}
+	if p.GetRawDad() != "" {
+		rawParents++
+	}
+	if index < rawParents {
+		return link.Role.String()
+	}
+	return fmt.Sprintf("parents[%d]", index-rawParents)
+}
+
+func getRelationships(p Person) []FlatRelationship {
+	if flat, ok := p.(*FlatPerson); ok {
+		return flat.Partners
+	}
+	return nil
+}
+
+func parseValidDate(d Date) (FuzzyDate, bool) {
+	fuzzy, err := d.Parse()
+	if err != nil || fuzzy.IsUnknown() {
+		return FuzzyDate{}, false
+	}
+	return fuzzy, true
+}
+
+// isCertainlyBefore returns true iff a ends before b starts
+func isCertainlyBefore(a, b FuzzyDate) bool {
+	latest, ok := a.Latest()
+	if !ok {
+		return false
+	}
+	earliest, ok := b.Earliest()
+	if !ok {
+		return false
+	}
+	return latest.Before(earliest)
+}
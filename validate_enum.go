@@ -0,0 +1,59 @@
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package generations
+
+import (
+	"fmt"
+)
+
+const (
+	// IssueSeverityError is a IssueSeverity of type Error
+	IssueSeverityError IssueSeverity = iota + 1
+	// IssueSeverityWarning is a IssueSeverity of type Warning
+	IssueSeverityWarning
+)
+
+const _IssueSeverityName = "errorwarning"
+
+var _IssueSeverityMap = map[IssueSeverity]string{
+	1: _IssueSeverityName[0:5],
+	2: _IssueSeverityName[5:12],
+}
+
+// String implements the Stringer interface.
+func (x IssueSeverity) String() string {
+	if str, ok := _IssueSeverityMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("IssueSeverity(%d)", x)
+}
+
+var _IssueSeverityValue = map[string]IssueSeverity{
+	_IssueSeverityName[0:5]:  1,
+	_IssueSeverityName[5:12]: 2,
+}
+
+// ParseIssueSeverity attempts to convert a string to a IssueSeverity
+func ParseIssueSeverity(name string) (IssueSeverity, error) {
+	if x, ok := _IssueSeverityValue[name]; ok {
+		return x, nil
+	}
+	return IssueSeverity(0), fmt.Errorf("%s is not a valid IssueSeverity", name)
+}
+
+// MarshalText implements the text marshaller method
+func (x IssueSeverity) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method
+func (x *IssueSeverity) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseIssueSeverity(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
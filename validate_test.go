@@ -0,0 +1,128 @@
+package generations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/validate/issues.yml")
+	assert.Nil(t, err)
+
+	issues := Validate(db)
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.String()
+	}
+	file := "testdata/validate/issues.yml"
+	assert.Equal(t, []string{
+		file + ":47: error: duplicate id gauss, already used at " + file + ":2 (gauss)",
+		file + ":48: error: duplicate uuid sohn, already used at " + file + ":21 (gauss)",
+		file + ":9: warning: partner osthoff does not list gauss as partner (gauss)",
+		file + ":10: error: partner unbekannt not found (gauss)",
+		file + ":23: warning: mother gauss is male (sohn)",
+		file + ":24: warning: father osthoff is female (sohn)",
+		file + ":28: warning: born (1812) after death of parent osthoff (1809-10-11) (spaet)",
+		file + ":33: warning: parent osthoff only 9–10 years old at birth (frueh)",
+		file + ":42: error: invalid gender \"mann\" (tippfehler)",
+		file + ":45: error: death (1849) before birth (1850) (tippfehler)",
+		file + ":41: error: parent osthof not found (tippfehler)",
+	}, messages)
+	assert.True(t, HasErrors(issues))
+	assert.True(t, HasBreakingIssues(issues))
+
+	// the test databases are valid
+	db = NewMemoryDatabase()
+	err = db.ParseYamlFile("testdata/database/sources.yml")
+	assert.Nil(t, err)
+	assert.Empty(t, Validate(db))
+}
+
+func TestScanPersonPositions(t *testing.T) {
+	data := []byte(`# comment
+- id: gauss
+  partners:
+    - partner_id: a
+      marriage:
+        date: 1805
+    - partner_id: b
+  mom: mama
+-
+  id: mama
+`)
+	positions := scanPersonPositions("db.yml", data)
+	assert.Len(t, positions, 2)
+	assert.Equal(t, Position{File: "db.yml", Line: 2}, positions[0].get("id"))
+	assert.Equal(t, Position{File: "db.yml", Line: 8}, positions[0].get("mom"))
+	assert.Equal(t, Position{File: "db.yml", Line: 7}, positions[0].get("partners[1]"))
+	assert.Equal(t, Position{File: "db.yml", Line: 3}, positions[0].get("partners[2]"))
+	assert.Equal(t, Position{File: "db.yml", Line: 2}, positions[0].get("dad"))
+	assert.Equal(t, Position{File: "db.yml", Line: 10}, positions[1].get("id"))
+}
+
+func TestValidateCycles(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/validate/cycle.yml")
+	assert.Nil(t, err)
+
+	issues := Validate(db)
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.String()
+	}
+	file := "testdata/validate/cycle.yml"
+	assert.Equal(t, []string{
+		file + ":9: error: cycle in ancestry: gauss → papa → opa → gauss (opa)",
+		file + ":21: error: cycle in ancestry: pflegemutter → pflegekind → pflegemutter (pflegekind)",
+	}, messages)
+}
+
+func TestValidateSyntax(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/validate/syntax.yml")
+	assert.Nil(t, err)
+
+	issues := Validate(db)
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.String()
+	}
+	file := "testdata/validate/syntax.yml"
+	assert.Equal(t, []string{
+		file + ":2: error: invalid id \"gauss}\", it must not contain any of {}%#\\,] (gauss})",
+		file + ":3: error: invalid uuid \"6F2A,1777\", it must not contain any of {}%#\\,] (gauss})",
+		file + ":7: error: invalid image filename \"bilder\\\\gauss.jpg\", it must not contain any of {}%#\\,] (gauss})",
+		file + ":6: error: invalid attribute \"50%\", it must not contain any of {}%#\\,] (gauss})",
+		file + ":8: error: invalid source id \"kb#1\", it must not contain any of {}%#\\,] (gauss})",
+	}, messages)
+	assert.True(t, HasBreakingIssues(issues))
+}
+
+func TestValidateMerged(t *testing.T) {
+	db := NewMemoryDatabase()
+	err := db.ParseYamlFile("testdata/validate/cycle.yml")
+	assert.Nil(t, err)
+	err = db.ParseYamlFile("testdata/validate/merged.yml")
+	assert.Nil(t, err)
+
+	issues := Validate(db)
+	messages := make([]string, 0)
+	for _, issue := range issues {
+		if issue.Severity == IssueSeverityWarning {
+			messages = append(messages, issue.String())
+		}
+	}
+	file := "testdata/validate/merged.yml"
+	assert.Equal(t, []string{
+		file + ":1: warning: duplicate id gauss, merged with testdata/validate/cycle.yml:1 (gauss)",
+		file + ":4: warning: duplicate id papa, merged with testdata/validate/cycle.yml:4 (papa)",
+	}, messages)
+
+	// implausible data alone does not break rendering
+	assert.False(t, HasBreakingIssues([]Issue{
+		{Severity: IssueSeverityError, Message: "invalid gender \"mann\""},
+		{Severity: IssueSeverityWarning, Message: "mother gauss is male"},
+	}))
+}